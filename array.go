@@ -11,7 +11,6 @@ package goavro
 
 import (
 	"fmt"
-	"io"
 	"math"
 	"reflect"
 )
@@ -24,83 +23,16 @@ func makeArrayCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 	}
 	itemCodec, err := buildCodec(st, enclosingNamespace, itemSchema, cb)
 	if err != nil {
-		return nil, fmt.Errorf("Array items ought to be valid Avro type: %s", err)
+		return nil, fmt.Errorf("Array items ought to be valid Avro type: %w", err)
 	}
 
-	return &Codec{
-		typeName: &name{"array", nullNamespace},
-		nativeFromBinary: func(buf []byte) (interface{}, []byte, error) {
-			var value interface{}
-			var err error
-
-			// block count and block size
-			if value, buf, err = longNativeFromBinary(buf); err != nil {
-				return nil, nil, fmt.Errorf("cannot decode binary array block count: %s", err)
-			}
-			blockCount := value.(int64)
-			if blockCount < 0 {
-				// NOTE: A negative block count implies there is a long encoded
-				// block size following the negative block count. We have no use
-				// for the block size in this decoder, so we read and discard
-				// the value.
-				if blockCount == math.MinInt64 {
-					// The minimum number for any signed numerical type can never be made positive
-					return nil, nil, fmt.Errorf("cannot decode binary array with block count: %d", blockCount)
-				}
-				blockCount = -blockCount // convert to its positive equivalent
-				if _, buf, err = longNativeFromBinary(buf); err != nil {
-					return nil, nil, fmt.Errorf("cannot decode binary array block size: %s", err)
-				}
-			}
-			// Ensure block count does not exceed some sane value.
-			if blockCount > MaxBlockCount {
-				return nil, nil, fmt.Errorf("cannot decode binary array when block count exceeds MaxBlockCount: %d > %d", blockCount, MaxBlockCount)
-			}
-			// NOTE: While the attempt of a RAM optimization shown below is not
-			// necessary, many encoders will encode all items in a single block.
-			// We can optimize amount of RAM allocated by runtime for the array
-			// by initializing the array for that number of items.
-			arrayValues := make([]interface{}, 0, blockCount)
-
-			for blockCount != 0 {
-				// Decode `blockCount` datum values from buffer
-				for i := int64(0); i < blockCount; i++ {
-					if value, buf, err = itemCodec.nativeFromBinary(buf); err != nil {
-						return nil, nil, fmt.Errorf("cannot decode binary array item %d: %s", i+1, err)
-					}
-					arrayValues = append(arrayValues, value)
-				}
-				// Decode next blockCount from buffer, because there may be more blocks
-				if value, buf, err = longNativeFromBinary(buf); err != nil {
-					return nil, nil, fmt.Errorf("cannot decode binary array block count: %s", err)
-				}
-				blockCount = value.(int64)
-				if blockCount < 0 {
-					// NOTE: A negative block count implies there is a long
-					// encoded block size following the negative block count. We
-					// have no use for the block size in this decoder, so we
-					// read and discard the value.
-					if blockCount == math.MinInt64 {
-						// The minimum number for any signed numerical type can
-						// never be made positive
-						return nil, nil, fmt.Errorf("cannot decode binary array with block count: %d", blockCount)
-					}
-					blockCount = -blockCount // convert to its positive equivalent
-					if _, buf, err = longNativeFromBinary(buf); err != nil {
-						return nil, nil, fmt.Errorf("cannot decode binary array block size: %s", err)
-					}
-				}
-				// Ensure block count does not exceed some sane value.
-				if blockCount > MaxBlockCount {
-					return nil, nil, fmt.Errorf("cannot decode binary array when block count exceeds MaxBlockCount: %d > %d", blockCount, MaxBlockCount)
-				}
-			}
-			return arrayValues, buf, nil
-		},
+	c := &Codec{
+		typeName:  &name{"array", nullNamespace},
+		itemCodec: itemCodec,
 		binaryFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
 			arrayValues, err := convertArray(datum)
 			if err != nil {
-				return nil, fmt.Errorf("cannot encode binary array: %s", err)
+				return nil, fmt.Errorf("cannot encode binary array: %w", err)
 			}
 
 			arrayLength := int64(len(arrayValues))
@@ -117,7 +49,7 @@ func makeArrayCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 				}
 
 				if buf, err = itemCodec.binaryFromNative(buf, item); err != nil {
-					return nil, fmt.Errorf("cannot encode binary array item %d: %v: %s", i+1, item, err)
+					return nil, fmt.Errorf("cannot encode binary array item %d: %v: %w", i+1, item, err)
 				}
 
 				remainingInBlock--
@@ -127,77 +59,208 @@ func makeArrayCodec(st map[string]*Codec, enclosingNamespace string, schemaMap m
 			return longBinaryFromNative(buf, 0) // append trailing 0 block count to signal end of Array
 		},
 		nativeFromTextual: func(buf []byte) (interface{}, []byte, error) {
-			var arrayValues []interface{}
-			var value interface{}
-			var err error
-			var b byte
+			return genericArrayTextDecoder(buf, itemCodec, false)
+		},
+		nativeFromTextualStandard: func(buf []byte) (interface{}, []byte, error) {
+			return genericArrayTextDecoder(buf, itemCodec, true)
+		},
+		textualFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
+			return genericArrayTextEncoder(buf, datum, itemCodec, false)
+		},
+		textualStandardFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
+			return genericArrayTextEncoder(buf, datum, itemCodec, true)
+		},
+	}
+	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
+		return genericArrayBinaryDecoder(buf, itemCodec.nativeFromBinary, c.decodeLimits)
+	}
+	return c, nil
+}
 
-			if buf, err = advanceAndConsume(buf, '['); err != nil {
-				return nil, nil, fmt.Errorf("cannot decode textual array: %s", err)
-			}
-			if buf, _ = advanceToNonWhitespace(buf); len(buf) == 0 {
-				return nil, nil, fmt.Errorf("cannot decode textual array: %s", io.ErrShortBuffer)
+// genericArrayTextDecoder decodes a textual array, using the standard JSON
+// decoder of itemCodec for its items when standard is true.
+func genericArrayTextDecoder(buf []byte, itemCodec *Codec, standard bool) (interface{}, []byte, error) {
+	var arrayValues []interface{}
+	var value interface{}
+	var err error
+	var b byte
+
+	if buf, err = advanceAndConsume(buf, '['); err != nil {
+		return nil, nil, fmt.Errorf("cannot decode textual array: %w", err)
+	}
+	if buf, _ = advanceToNonWhitespace(buf); len(buf) == 0 {
+		return nil, nil, fmt.Errorf("cannot decode textual array: %w", ErrShortBuffer{})
+	}
+	// NOTE: Special case for empty array
+	if buf[0] == ']' {
+		return arrayValues, buf[1:], nil
+	}
+
+	// NOTE: Also terminates when read ']' byte.
+	for len(buf) > 0 {
+		// decode value
+		if standard {
+			value, buf, err = itemCodec.nativeStandard(buf)
+		} else {
+			value, buf, err = itemCodec.nativeFromTextual(buf)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot decode textual array: %w", err)
+		}
+		arrayValues = append(arrayValues, value)
+		// either comma or closing curly brace
+		if buf, _ = advanceToNonWhitespace(buf); len(buf) == 0 {
+			return nil, nil, fmt.Errorf("cannot decode textual array: %w", ErrShortBuffer{})
+		}
+		switch b = buf[0]; b {
+		case ']':
+			return arrayValues, buf[1:], nil
+		case ',':
+			// no-op
+		default:
+			return nil, nil, fmt.Errorf("cannot decode textual array: expected ',' or ']'; received: %q", b)
+		}
+		// NOTE: consume comma from above
+		if buf, _ = advanceToNonWhitespace(buf[1:]); len(buf) == 0 {
+			return nil, nil, fmt.Errorf("cannot decode textual array: %w", ErrShortBuffer{})
+		}
+	}
+	return nil, buf, ErrShortBuffer{}
+}
+
+// genericArrayBinaryDecoder decodes the blocks of a binary array, using
+// itemNativeFromBinary to decode each item, and rejecting blocks exceeding
+// limits.
+func genericArrayBinaryDecoder(buf []byte, itemNativeFromBinary func([]byte) (interface{}, []byte, error), limits decodeLimits) (interface{}, []byte, error) {
+	var value interface{}
+	var err error
+	start := len(buf)
+
+	// block count and block size
+	if value, buf, err = longNativeFromBinary(buf); err != nil {
+		return nil, nil, fmt.Errorf("cannot decode binary array block count: %w", err)
+	}
+	blockCount := value.(int64)
+	if blockCount < 0 {
+		// NOTE: A negative block count implies there is a long encoded
+		// block size following the negative block count. This decoder
+		// only uses the block size to check it does not exceed its
+		// limit.
+		if blockCount == math.MinInt64 {
+			// The minimum number for any signed numerical type can never be made positive
+			return nil, nil, fmt.Errorf("cannot decode binary array with block count: %d", blockCount)
+		}
+		blockCount = -blockCount // convert to its positive equivalent
+		if value, buf, err = longNativeFromBinary(buf); err != nil {
+			return nil, nil, fmt.Errorf("cannot decode binary array block size: %w", err)
+		}
+		if blockSize, maxBlockSize := value.(int64), limits.blockSize(); blockSize > maxBlockSize {
+			return nil, nil, fmt.Errorf("cannot decode binary array when block size exceeds MaxBlockSize: %d > %d", blockSize, maxBlockSize)
+		}
+	}
+	// Ensure block count does not exceed some sane value.
+	if maxBlockCount := limits.blockCount(); blockCount > maxBlockCount {
+		return nil, nil, fmt.Errorf("cannot decode binary array when block count exceeds MaxBlockCount: %d > %d", blockCount, maxBlockCount)
+	}
+	if limits.itemCountExceeded(0, blockCount) {
+		return nil, nil, fmt.Errorf("cannot decode binary array when item count exceeds limit: %d > %d", blockCount, limits.maxItemCount)
+	}
+	// NOTE: While the attempt of a RAM optimization shown below is not
+	// necessary, many encoders will encode all items in a single block.
+	// We can optimize amount of RAM allocated by runtime for the array
+	// by initializing the array for that number of items, although never
+	// for more items than there are bytes remaining, so a malformed block
+	// count does not allocate before any of its items are read.
+	arrayValues := make([]interface{}, 0, initialBlockCapacity(blockCount, buf))
+
+	for blockCount != 0 {
+		// Decode `blockCount` datum values from buffer
+		for i := int64(0); i < blockCount; i++ {
+			var newBuf []byte
+			if value, newBuf, err = itemNativeFromBinary(buf); err != nil {
+				return nil, nil, newDecodeError(err, fmt.Errorf("cannot decode binary array item %d: %w", i+1, err), fmt.Sprintf("[%d]", len(arrayValues)), start-len(buf))
 			}
-			// NOTE: Special case for empty array
-			if buf[0] == ']' {
-				return arrayValues, buf[1:], nil
+			buf = newBuf
+			arrayValues = append(arrayValues, value)
+		}
+		// Decode next blockCount from buffer, because there may be more blocks
+		if value, buf, err = longNativeFromBinary(buf); err != nil {
+			return nil, nil, fmt.Errorf("cannot decode binary array block count: %w", err)
+		}
+		blockCount = value.(int64)
+		if blockCount < 0 {
+			// NOTE: A negative block count implies there is a long
+			// encoded block size following the negative block count.
+			// This decoder only uses the block size to check it does
+			// not exceed its limit.
+			if blockCount == math.MinInt64 {
+				// The minimum number for any signed numerical type can
+				// never be made positive
+				return nil, nil, fmt.Errorf("cannot decode binary array with block count: %d", blockCount)
 			}
-
-			// NOTE: Also terminates when read ']' byte.
-			for len(buf) > 0 {
-				// decode value
-				value, buf, err = itemCodec.nativeFromTextual(buf)
-				if err != nil {
-					return nil, nil, fmt.Errorf("cannot decode textual array: %s", err)
-				}
-				arrayValues = append(arrayValues, value)
-				// either comma or closing curly brace
-				if buf, _ = advanceToNonWhitespace(buf); len(buf) == 0 {
-					return nil, nil, fmt.Errorf("cannot decode textual array: %s", io.ErrShortBuffer)
-				}
-				switch b = buf[0]; b {
-				case ']':
-					return arrayValues, buf[1:], nil
-				case ',':
-					// no-op
-				default:
-					return nil, nil, fmt.Errorf("cannot decode textual array: expected ',' or ']'; received: %q", b)
-				}
-				// NOTE: consume comma from above
-				if buf, _ = advanceToNonWhitespace(buf[1:]); len(buf) == 0 {
-					return nil, nil, fmt.Errorf("cannot decode textual array: %s", io.ErrShortBuffer)
-				}
+			blockCount = -blockCount // convert to its positive equivalent
+			if value, buf, err = longNativeFromBinary(buf); err != nil {
+				return nil, nil, fmt.Errorf("cannot decode binary array block size: %w", err)
 			}
-			return nil, buf, io.ErrShortBuffer
-		},
-		textualFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
-			arrayValues, err := convertArray(datum)
-			if err != nil {
-				return nil, fmt.Errorf("cannot encode textual array: %s", err)
+			if blockSize, maxBlockSize := value.(int64), limits.blockSize(); blockSize > maxBlockSize {
+				return nil, nil, fmt.Errorf("cannot decode binary array when block size exceeds MaxBlockSize: %d > %d", blockSize, maxBlockSize)
 			}
+		}
+		// Ensure block count does not exceed some sane value.
+		if maxBlockCount := limits.blockCount(); blockCount > maxBlockCount {
+			return nil, nil, fmt.Errorf("cannot decode binary array when block count exceeds MaxBlockCount: %d > %d", blockCount, maxBlockCount)
+		}
+		if limits.itemCountExceeded(len(arrayValues), blockCount) {
+			return nil, nil, fmt.Errorf("cannot decode binary array when item count exceeds limit: %d > %d", int64(len(arrayValues))+blockCount, limits.maxItemCount)
+		}
+	}
+	return arrayValues, buf, nil
+}
 
-			var atLeastOne bool
+// initialBlockCapacity returns the number of items to allocate for a block of
+// blockCount items, which is at most the number of bytes remaining in buf.
+// Items encoded using no bytes, such as nulls, may still exceed it, and are
+// appended as they are decoded.
+func initialBlockCapacity(blockCount int64, buf []byte) int64 {
+	if remaining := int64(len(buf)); blockCount > remaining {
+		return remaining
+	}
+	return blockCount
+}
 
-			buf = append(buf, '[')
+// genericArrayTextEncoder encodes a native Go slice to a JSON text blob, using
+// itemCodec for every item. When standard is true, items are encoded as
+// standard JSON rather than as textual Avro data.
+func genericArrayTextEncoder(buf []byte, datum interface{}, itemCodec *Codec, standard bool) ([]byte, error) {
+	arrayValues, err := convertArray(datum)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode textual array: %w", err)
+	}
 
-			for i, item := range arrayValues {
-				atLeastOne = true
+	var atLeastOne bool
 
-				// Encode value
-				buf, err = itemCodec.textualFromNative(buf, item)
-				if err != nil {
-					// field was specified in datum; therefore its value was invalid
-					return nil, fmt.Errorf("cannot encode textual array item %d; %v: %s", i+1, item, err)
-				}
-				buf = append(buf, ',')
-			}
+	buf = append(buf, '[')
 
-			if atLeastOne {
-				return append(buf[:len(buf)-1], ']'), nil
-			}
-			return append(buf, ']'), nil
-		},
-	}, nil
+	for i, item := range arrayValues {
+		atLeastOne = true
+
+		// Encode value
+		if standard {
+			buf, err = itemCodec.textualStandard(buf, item)
+		} else {
+			buf, err = itemCodec.textualFromNative(buf, item)
+		}
+		if err != nil {
+			// field was specified in datum; therefore its value was invalid
+			return nil, fmt.Errorf("cannot encode textual array item %d; %v: %w", i+1, item, err)
+		}
+		buf = append(buf, ',')
+	}
+
+	if atLeastOne {
+		return append(buf[:len(buf)-1], ']'), nil
+	}
+	return append(buf, ']'), nil
 }
 
 // convertArray converts interface{} to []interface{} if possible.
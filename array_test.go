@@ -66,6 +66,61 @@ func TestArrayDecodeNextBlockCountTooNegative(t *testing.T) {
 	testBinaryDecodeFail(t, `{"type":"array","items":"int"}`, append([]byte{2, 6}, append(mostNegativeBlockCount, []byte{2, 6, 0}...)...), "block count")
 }
 
+func TestArrayDecodeBlockLimits(t *testing.T) {
+	const schema = `{"type":"array","items":"int"}`
+	codec, err := NewCodecWithOptions(schema, WithMaxBlockCount(2), WithMaxBlockSize(8))
+	ensureError(t, err)
+
+	// within the limits
+	datum, _, err := codec.NativeFromBinary([]byte{4, 2, 4, 1, 2, 6, 0})
+	ensureError(t, err)
+	if actual, expected := len(datum.([]interface{})), 3; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	for _, c := range []struct {
+		encoded []byte
+		message string
+	}{
+		{[]byte{6, 2, 4, 6, 0}, "block count exceeds MaxBlockCount: 3 > 2"},
+		{[]byte{4, 2, 4, 6, 2, 4, 6, 0}, "block count exceeds MaxBlockCount: 3 > 2"},
+		{[]byte{5, 6, 2, 4, 6, 0}, "block count exceeds MaxBlockCount: 3 > 2"},
+		{[]byte{1, 0xc8, 0x01, 2, 0}, "block size exceeds MaxBlockSize: 100 > 8"},
+		{[]byte{2, 2, 1, 0xc8, 0x01, 2, 0}, "block size exceeds MaxBlockSize: 100 > 8"},
+	} {
+		_, _, err = codec.NativeFromBinary(c.encoded)
+		ensureError(t, err, c.message)
+	}
+
+	// codecs created without the options are unaffected
+	testBinaryDecodePass(t, schema, []interface{}{1, 2, 3}, []byte{6, 2, 4, 6, 0})
+	testBinaryDecodePass(t, schema, []interface{}{1}, []byte{1, 0xc8, 0x01, 2, 0})
+}
+
+func TestArrayDecodeItemCountLimit(t *testing.T) {
+	codec, err := NewCodecWithOptions(`{"type":"array","items":"int"}`, WithMaxItemCount(5))
+	ensureError(t, err)
+
+	// five items in blocks of two, two, and one
+	datum, _, err := codec.NativeFromBinary([]byte{4, 2, 4, 4, 2, 4, 2, 2, 0})
+	ensureError(t, err)
+	if actual, expected := len(datum.([]interface{})), 5; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	// each block is small, but the third one exceeds the limit
+	_, _, err = codec.NativeFromBinary([]byte{4, 2, 4, 4, 2, 4, 4, 2, 4, 0})
+	ensureError(t, err, "item count exceeds limit: 6 > 5")
+	_, _, err = codec.NativeFromBinary([]byte{12, 2, 4, 6, 8, 10, 12, 0})
+	ensureError(t, err, "item count exceeds limit: 6 > 5")
+
+	// the limit applies to each array, rather than to all arrays of a datum
+	codec, err = NewCodecWithOptions(`{"type":"array","items":{"type":"array","items":"int"}}`, WithMaxItemCount(2))
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinary([]byte{4, 4, 2, 4, 0, 4, 2, 4, 0, 0})
+	ensureError(t, err)
+}
+
 func TestArrayNull(t *testing.T) {
 	testBinaryCodecPass(t, `{"type":"array","items":"null"}`, []interface{}{}, []byte{0})
 	testBinaryCodecPass(t, `{"type":"array","items":"null"}`, []interface{}{nil}, []byte{2, 0})
@@ -20,7 +20,7 @@ import (
 func bytesBinaryReader(ior io.Reader) ([]byte, error) {
 	size, err := longBinaryReader(ior)
 	if err != nil {
-		return nil, fmt.Errorf("cannot read bytes: cannot read size: %s", err)
+		return nil, fmt.Errorf("cannot read bytes: cannot read size: %w", err)
 	}
 	if size < 0 {
 		return nil, fmt.Errorf("cannot read bytes: size is negative: %d", size)
@@ -31,7 +31,7 @@ func bytesBinaryReader(ior io.Reader) ([]byte, error) {
 	buf := make([]byte, size)
 	_, err = io.ReadAtLeast(ior, buf, int(size))
 	if err != nil {
-		return nil, fmt.Errorf("cannot read bytes: %s", err)
+		return nil, fmt.Errorf("cannot read bytes: %w", err)
 	}
 	return buf, nil
 }
@@ -86,7 +86,7 @@ func metadataBinaryReader(ior io.Reader) (map[string][]byte, error) {
 
 	// block count and block size
 	if value, err = longBinaryReader(ior); err != nil {
-		return nil, fmt.Errorf("cannot read map block count: %s", err)
+		return nil, fmt.Errorf("cannot read map block count: %w", err)
 	}
 	blockCount := value.(int64)
 	if blockCount < 0 {
@@ -100,7 +100,7 @@ func metadataBinaryReader(ior io.Reader) (map[string][]byte, error) {
 		// size in this decoder, so we read and discard the value.
 		blockCount = -blockCount // convert to its positive equivalent
 		if _, err = longBinaryReader(ior); err != nil {
-			return nil, fmt.Errorf("cannot read map block size: %s", err)
+			return nil, fmt.Errorf("cannot read map block size: %w", err)
 		}
 	}
 	// Ensure block count does not exceed some sane value.
@@ -119,7 +119,7 @@ func metadataBinaryReader(ior io.Reader) (map[string][]byte, error) {
 			// first decode the key string
 			keyBytes, err := bytesBinaryReader(ior)
 			if err != nil {
-				return nil, fmt.Errorf("cannot read map key: %s", err)
+				return nil, fmt.Errorf("cannot read map key: %w", err)
 			}
 			key := string(keyBytes)
 			if _, ok := mapValues[key]; ok {
@@ -128,13 +128,13 @@ func metadataBinaryReader(ior io.Reader) (map[string][]byte, error) {
 			// metadata values are always bytes
 			buf, err := bytesBinaryReader(ior)
 			if err != nil {
-				return nil, fmt.Errorf("cannot read map value for key %q: %s", key, err)
+				return nil, fmt.Errorf("cannot read map value for key %q: %w", key, err)
 			}
 			mapValues[key] = buf
 		}
 		// Decode next blockCount from buffer, because there may be more blocks
 		if value, err = longBinaryReader(ior); err != nil {
-			return nil, fmt.Errorf("cannot read map block count: %s", err)
+			return nil, fmt.Errorf("cannot read map block count: %w", err)
 		}
 		blockCount = value.(int64)
 		if blockCount < 0 {
@@ -148,7 +148,7 @@ func metadataBinaryReader(ior io.Reader) (map[string][]byte, error) {
 			// the block size in this decoder, so we read and discard the value.
 			blockCount = -blockCount // convert to its positive equivalent
 			if _, err = longBinaryReader(ior); err != nil {
-				return nil, fmt.Errorf("cannot read map block size: %s", err)
+				return nil, fmt.Errorf("cannot read map block size: %w", err)
 			}
 		}
 		// Ensure block count does not exceed some sane value.
@@ -158,3 +158,86 @@ func metadataBinaryReader(ior io.Reader) (map[string][]byte, error) {
 	}
 	return mapValues, nil
 }
+
+// binaryReaderChunkSize is the minimum number of bytes a BinaryReader attempts
+// to read from its io.Reader each time it needs more data.
+const binaryReaderChunkSize = 4096
+
+// BinaryReader decodes a stream of concatenated binary encoded Avro values
+// read from an io.Reader, such as one written by a BinaryWriter. Unlike
+// OCFReader, it expects neither a header nor blocks in the stream, only the
+// encoded values themselves.
+//
+//     br := codec.NewBinaryReader(ior)
+//     for {
+//         datum, err := br.Next()
+//         if err == io.EOF {
+//             break
+//         }
+//         if err != nil {
+//             return err
+//         }
+//         // use datum
+//     }
+type BinaryReader struct {
+	codec *Codec
+	ior   io.Reader
+	buf   []byte // bytes read from ior not yet decoded
+	rerr  error  // sticky error returned by ior
+}
+
+// NewBinaryReader returns a BinaryReader which decodes values using the Codec
+// from the binary Avro data read from ior.
+func (c *Codec) NewBinaryReader(ior io.Reader) *BinaryReader {
+	return &BinaryReader{codec: c, ior: ior}
+}
+
+// Next decodes and returns the next value from the stream. It returns io.EOF
+// when the stream ends between two values, and a different error when the
+// stream ends in the middle of a value.
+func (br *BinaryReader) Next() (interface{}, error) {
+	for {
+		if len(br.buf) > 0 {
+			datum, newBuf, err := br.codec.boundedNativeFromBinary(br.buf)
+			if err == nil {
+				br.buf = newBuf
+				return datum, nil
+			}
+			// NOTE: Decoders do not consistently report when a value is merely
+			// truncated, so presume more data is required until the stream is
+			// exhausted.
+			if br.rerr == io.EOF {
+				return nil, fmt.Errorf("cannot decode binary datum: %w", br.codec.located(err, 0))
+			}
+			if br.rerr == nil && int64(len(br.buf)) > MaxBlockSize {
+				return nil, fmt.Errorf("cannot decode binary datum: size exceeds MaxBlockSize: %d > %d: %w", len(br.buf), MaxBlockSize, br.codec.located(err, 0))
+			}
+		}
+		if br.rerr != nil {
+			return nil, br.rerr // NOTE: send back unaltered io.EOF
+		}
+		br.fill()
+	}
+}
+
+// fill reads more data from the underlying io.Reader, growing the buffer when
+// it has no spare capacity.
+func (br *BinaryReader) fill() {
+	if len(br.buf) == cap(br.buf) {
+		size := binaryReaderChunkSize
+		if len(br.buf) > size {
+			size = len(br.buf) // double the size of the buffer for large values
+		}
+		// NOTE: Always allocate a new buffer rather than moving the remaining
+		// bytes to the front of the existing one, because previously decoded
+		// values, such as bytes, may refer to the existing one.
+		buf := make([]byte, len(br.buf), len(br.buf)+size)
+		copy(buf, br.buf)
+		br.buf = buf
+	}
+	n, err := br.ior.Read(br.buf[len(br.buf):cap(br.buf)])
+	br.buf = br.buf[:len(br.buf)+n]
+	if err != nil {
+		br.rerr = err
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"fmt"
+	"io"
+)
+
+// BinaryWriter encodes values using a Codec, and writes them to an io.Writer
+// as a stream of concatenated binary encoded Avro values, which may be read
+// using a BinaryReader.
+//
+//     bw := codec.NewBinaryWriter(bufio.NewWriter(iow))
+//     for _, datum := range data {
+//         if err := bw.Write(datum); err != nil {
+//             return err
+//         }
+//     }
+//     return bw.Flush()
+type BinaryWriter struct {
+	codec *Codec
+	iow   io.Writer
+	buf   []byte // reused across calls to Write to reduce allocations
+}
+
+// NewBinaryWriter returns a BinaryWriter which encodes values using the Codec,
+// and writes them to iow.
+func (c *Codec) NewBinaryWriter(iow io.Writer) *BinaryWriter {
+	return &BinaryWriter{codec: c, iow: iow}
+}
+
+// Write encodes datum and writes it to the underlying io.Writer. Nothing is
+// written when datum cannot be encoded.
+func (bw *BinaryWriter) Write(datum interface{}) error {
+	buf, err := bw.codec.encodeBinary(bw.buf[:0], datum)
+	if err != nil {
+		return err
+	}
+	bw.buf = buf
+	if _, err = bw.iow.Write(buf); err != nil {
+		return fmt.Errorf("cannot write binary datum: %w", err)
+	}
+	return nil
+}
+
+// Flush flushes the underlying io.Writer when it has a Flush method, such as a
+// *bufio.Writer, and does nothing otherwise.
+func (bw *BinaryWriter) Flush() error {
+	if flusher, ok := bw.iow.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			return fmt.Errorf("cannot flush: %w", err)
+		}
+	}
+	return nil
+}
@@ -10,14 +10,15 @@
 package goavro
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"reflect"
 	"testing"
-
-	"github.com/mohae/deepcopy"
+	"testing/iotest"
 )
 
 var morePositiveThanMaxBlockCount, morePositiveThanMaxBlockSize, moreNegativeThanMaxBlockCount, mostNegativeBlockCount []byte
@@ -106,7 +107,7 @@ func testBinaryDecodePass(t *testing.T, schema string, datum interface{}, encode
 		t.Errorf("schema: %s; Datum: %v; Actual: %#v; Expected: %#v", schema, datum, actual, expected)
 	}
 
-	datumCopy := deepcopy.Copy(datum)
+	datumCopy := CopyNative(datum)
 
 	if reflect.DeepEqual(value, datumCopy) {
 		return
@@ -171,8 +172,8 @@ func testBinaryDecodePass(t *testing.T, schema string, datum interface{}, encode
 	}
 
 	if actual != expected {
-		// this is silly - but for certain types - specifically the logical binary types (math/big.Rat, etc)
-		// deepcopy does not work and we end up with false false
+		// NOTE: Values of logical types, such as *big.Rat, and types
+		// implementing Str, print differently from the values they decode as.
 
 		originalExpected := fmt.Sprintf("%v", datum)
 
@@ -221,3 +222,147 @@ func testBinaryCodecPass(t *testing.T, schema string, datum interface{}, buf []b
 	testBinaryDecodePass(t, schema, datum, buf)
 	testBinaryEncodePass(t, schema, datum, buf)
 }
+
+func TestBinaryReader(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":"long"},{"name":"f2","type":"bytes"}]}`)
+	ensureError(t, err)
+
+	const count = 1000
+	var stream []byte
+	for i := 0; i < count; i++ {
+		stream, err = codec.BinaryFromNative(stream, map[string]interface{}{"f1": int64(i), "f2": bytes.Repeat([]byte{byte(i)}, i)})
+		ensureError(t, err)
+	}
+
+	t.Run("buffer refill", func(t *testing.T) {
+		// NOTE: Reading one byte at a time ensures values span buffer refills.
+		for _, ior := range []io.Reader{bytes.NewReader(stream), iotest.OneByteReader(bytes.NewReader(stream)), iotest.DataErrReader(bytes.NewReader(stream))} {
+			br := codec.NewBinaryReader(ior)
+			var decoded []interface{}
+			for {
+				datum, err := br.Next()
+				if err == io.EOF {
+					break
+				}
+				ensureError(t, err)
+				decoded = append(decoded, datum)
+			}
+			if actual, expected := len(decoded), count; actual != expected {
+				t.Fatalf("GOT: %v; WANT: %v", actual, expected)
+			}
+			// NOTE: Check values after reading the entire stream, because
+			// decoded bytes must not be overwritten by later reads.
+			for i, datum := range decoded {
+				record := datum.(map[string]interface{})
+				if actual, expected := record["f1"], int64(i); actual != expected {
+					t.Fatalf("GOT: %v; WANT: %v", actual, expected)
+				}
+				if actual, expected := record["f2"].([]byte), bytes.Repeat([]byte{byte(i)}, i); !bytes.Equal(actual, expected) {
+					t.Fatalf("GOT: %v; WANT: %v", actual, expected)
+				}
+			}
+			if _, err := br.Next(); err != io.EOF {
+				t.Errorf("GOT: %v; WANT: %v", err, io.EOF)
+			}
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if _, err := codec.NewBinaryReader(bytes.NewReader(nil)).Next(); err != io.EOF {
+			t.Errorf("GOT: %v; WANT: %v", err, io.EOF)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		br := codec.NewBinaryReader(bytes.NewReader(stream[:len(stream)-1]))
+		var err error
+		for i := 0; i < count; i++ {
+			if _, err = br.Next(); err != nil {
+				break
+			}
+		}
+		ensureError(t, err, "cannot decode binary datum", "short buffer")
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		br := codec.NewBinaryReader(iotest.TimeoutReader(iotest.OneByteReader(bytes.NewReader(stream))))
+		_, err := br.Next()
+		if err != iotest.ErrTimeout {
+			t.Errorf("GOT: %v; WANT: %v", err, iotest.ErrTimeout)
+		}
+	})
+}
+
+func TestBinaryWriter(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":"long"},{"name":"f2","type":"string"}]}`)
+	ensureError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		const count = 10000
+		var stream bytes.Buffer
+		bw := codec.NewBinaryWriter(bufio.NewWriter(&stream))
+		var expected []byte
+		for i := 0; i < count; i++ {
+			datum := map[string]interface{}{"f1": int64(i), "f2": fmt.Sprintf("record %d", i)}
+			ensureError(t, bw.Write(datum))
+			expected, err = codec.BinaryFromNative(expected, datum)
+			ensureError(t, err)
+		}
+		ensureError(t, bw.Flush())
+		if actual := stream.Bytes(); !bytes.Equal(actual, expected) {
+			t.Fatalf("GOT: %d bytes; WANT: %d bytes", len(actual), len(expected))
+		}
+
+		br := codec.NewBinaryReader(&stream)
+		for i := 0; i < count; i++ {
+			datum, err := br.Next()
+			ensureError(t, err)
+			if actual, expected := datum.(map[string]interface{})["f1"], int64(i); actual != expected {
+				t.Fatalf("GOT: %v; WANT: %v", actual, expected)
+			}
+		}
+		if _, err := br.Next(); err != io.EOF {
+			t.Errorf("GOT: %v; WANT: %v", err, io.EOF)
+		}
+	})
+
+	t.Run("encode error", func(t *testing.T) {
+		var stream bytes.Buffer
+		bw := codec.NewBinaryWriter(&stream)
+		ensureError(t, bw.Write(map[string]interface{}{"f1": int64(1), "f2": "some string"}))
+		ensureError(t, bw.Write(map[string]interface{}{"f1": int64(2)}), "f2")
+		if actual, expected := stream.Len(), 13; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+		ensureError(t, bw.Flush()) // bytes.Buffer has no Flush method
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		bw := codec.NewBinaryWriter(ShortWriter(new(bytes.Buffer), 3))
+		ensureError(t, bw.Write(map[string]interface{}{"f1": int64(1), "f2": "some string"}), "cannot write binary datum", "short write")
+	})
+}
+
+func TestBinaryDecodeErrorLocation(t *testing.T) {
+	const schema = `{"type":"record","name":"r1","fields":[
+		{"name":"id","type":"long"},
+		{"name":"f1","type":{"type":"array","items":["null",{"type":"record","name":"r2","fields":[{"name":"f2","type":"int"},{"name":"f3","type":"string"}]}]}},
+		{"name":"m","type":{"type":"map","values":"int"}}
+	]}`
+
+	// second array item has a negative string size
+	testBinaryDecodeFail(t, schema, []byte("\x02\x04\x02\x02\x02a\x02\x02\x01"), `cannot decode binary record "r1" field "f1": cannot decode binary array item 2: cannot decode binary union item 2: cannot decode binary record "r2" field "f3": cannot decode binary string: cannot decode binary bytes: negative size: -1; offset: 8; path: r1.f1[1].f3`)
+	// first array item has a union index out of range
+	testBinaryDecodeFail(t, schema, []byte("\x02\x02\x06"), "read index: 3; offset: 2; path: r1.f1[0]")
+	// map value is truncated
+	testBinaryDecodeFail(t, schema, []byte("\x02\x00\x02\x02k\x80"), `offset: 5; path: r1.m["k"]`)
+
+	// errors of values which are not records, arrays, maps, or unions are not
+	// located
+	codec, err := NewCodec(`"string"`)
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinary([]byte("\x01"))
+	if actual, expected := err.Error(), "cannot decode binary string: cannot decode binary bytes: negative size: -1"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
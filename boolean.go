@@ -13,12 +13,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"io"
 )
 
 func booleanNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 	if len(buf) < 1 {
-		return nil, nil, io.ErrShortBuffer
+		return nil, nil, ErrShortBuffer{}
 	}
 	var b byte
 	b, buf = buf[0], buf[1:]
@@ -46,13 +45,13 @@ func booleanBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 
 func booleanNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 	if len(buf) < 4 {
-		return nil, nil, fmt.Errorf("cannot decode textual boolean: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode textual boolean: %w", ErrShortBuffer{})
 	}
 	if bytes.Equal(buf[:4], []byte("true")) {
 		return true, buf[4:], nil
 	}
 	if len(buf) < 5 {
-		return nil, nil, fmt.Errorf("cannot decode textual boolean: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode textual boolean: %w", ErrShortBuffer{})
 	}
 	if bytes.Equal(buf[:5], []byte("false")) {
 		return false, buf[5:], nil
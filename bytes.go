@@ -13,7 +13,6 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"reflect"
 	"unicode"
@@ -21,25 +20,66 @@ import (
 	"unicode/utf8"
 )
 
+// WithBytesAsString returns a CodecOption which makes the bytes decoders of the
+// Codec return strings rather than []byte. Fixed values, and values of logical
+// types annotating bytes, such as decimal, decode as they otherwise would.
+//
+//     codec, err := goavro.NewCodecWithOptions(`"bytes"`, goavro.WithBytesAsString())
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     native, _, err := codec.NativeFromBinary([]byte("\x04hi"))
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Printf("%#v", native) // "hi"
+func WithBytesAsString() CodecOption {
+	return func(o *codecOptions) {
+		o.bytesAsString = true
+	}
+}
+
+// bytesAsStringCodec returns a copy of the bytes codec c whose decoders return
+// strings rather than []byte.
+func bytesAsStringCodec(c *Codec) *Codec {
+	cp := *c
+	cp.bytesAsString = true
+	cp.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
+		value, buf, err := c.nativeFromBinary(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		return string(value.([]byte)), buf, nil
+	}
+	cp.nativeFromTextual = func(buf []byte) (interface{}, []byte, error) {
+		value, buf, err := c.nativeFromTextual(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		return string(value.([]byte)), buf, nil
+	}
+	return &cp
+}
+
 ////////////////////////////////////////
 // Binary Decode
 ////////////////////////////////////////
 
 func bytesNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 	if len(buf) < 1 {
-		return nil, nil, fmt.Errorf("cannot decode binary bytes: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode binary bytes: %w", ErrShortBuffer{})
 	}
 	var decoded interface{}
 	var err error
 	if decoded, buf, err = longNativeFromBinary(buf); err != nil {
-		return nil, nil, fmt.Errorf("cannot decode binary bytes: %s", err)
+		return nil, nil, fmt.Errorf("cannot decode binary bytes: %w", err)
 	}
 	size := decoded.(int64) // always returns int64
 	if size < 0 {
 		return nil, nil, fmt.Errorf("cannot decode binary bytes: negative size: %d", size)
 	}
 	if size > int64(len(buf)) {
-		return nil, nil, fmt.Errorf("cannot decode binary bytes: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode binary bytes: %w", ErrShortBuffer{})
 	}
 	return buf[:size], buf[size:], nil
 }
@@ -47,7 +87,7 @@ func bytesNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 func stringNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 	d, b, err := bytesNativeFromBinary(buf)
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot decode binary string: %s", err)
+		return nil, nil, fmt.Errorf("cannot decode binary string: %w", err)
 	}
 	return string(d.([]byte)), b, nil
 }
@@ -97,7 +137,7 @@ func stringBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 func bytesNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 	buflen := len(buf)
 	if buflen < 2 {
-		return nil, nil, fmt.Errorf("cannot decode textual bytes: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode textual bytes: %w", ErrShortBuffer{})
 	}
 	if buf[0] != '"' {
 		return nil, nil, fmt.Errorf("cannot decode textual bytes: expected initial \"; found: %#U", buf[0])
@@ -120,15 +160,18 @@ func bytesNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 				// subtract another 1 because already consumed u but have yet to
 				// increment i.
 				if i > buflen-6 {
-					return nil, nil, fmt.Errorf("cannot decode textual bytes: %s", io.ErrShortBuffer)
+					return nil, nil, fmt.Errorf("cannot decode textual bytes: %w", ErrShortBuffer{})
 				}
 				// NOTE: Avro bytes represent binary data, and do not
 				// necessarily represent text. Therefore, Avro bytes are not
 				// encoded in UTF-16. Each \u is followed by 4 hexadecimal
 				// digits, the first and second of which must be 0.
-				v, err := parseUint64FromHexSlice(buf[i+3 : i+5])
+				v, err := parseUint64FromHexSlice(buf[i+1 : i+5])
 				if err != nil {
-					return nil, nil, fmt.Errorf("cannot decode textual bytes: %s", err)
+					return nil, nil, fmt.Errorf("cannot decode textual bytes: %w", err)
+				}
+				if v > 0xff {
+					return nil, nil, fmt.Errorf("cannot decode textual bytes: code point ought to be between 0 and 255; received: %#U", rune(v))
 				}
 				i += 4 // absorb 4 characters: one 'u' and three of the digits
 				newBytes = append(newBytes, byte(v))
@@ -144,6 +187,20 @@ func bytesNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 		if b == '"' {
 			return newBytes, buf[i+1:], nil
 		}
+		if b >= utf8.RuneSelf {
+			// NOTE: Each byte is a code point between 0 and 255, which JSON
+			// may also write as an unescaped UTF-8 encoded character.
+			r, width := utf8.DecodeRune(buf[i:])
+			if r == utf8.RuneError && width == 1 {
+				return nil, nil, fmt.Errorf("cannot decode textual bytes: invalid UTF-8 byte: %#x", b)
+			}
+			if r > 0xff {
+				return nil, nil, fmt.Errorf("cannot decode textual bytes: code point ought to be between 0 and 255; received: %#U", r)
+			}
+			i += width - 1 // absorb remaining bytes of UTF-8 encoded character
+			newBytes = append(newBytes, byte(r))
+			continue
+		}
 		newBytes = append(newBytes, b)
 	}
 	return nil, nil, fmt.Errorf("cannot decode textual bytes: expected final \"; found: %#U", buf[buflen-1])
@@ -152,7 +209,7 @@ func bytesNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 func stringNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 	buflen := len(buf)
 	if buflen < 2 {
-		return nil, nil, fmt.Errorf("cannot decode textual string: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode textual string: %w", ErrShortBuffer{})
 	}
 	if buf[0] != '"' {
 		return nil, nil, fmt.Errorf("cannot decode textual string: expected initial \"; found: %#U", buf[0])
@@ -175,11 +232,11 @@ func stringNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 				// subtract another 1 because already consumed u but have yet to
 				// increment i.
 				if i > buflen-6 {
-					return nil, nil, fmt.Errorf("cannot decode textual string: %s", io.ErrShortBuffer)
+					return nil, nil, fmt.Errorf("cannot decode textual string: %w", ErrShortBuffer{})
 				}
 				v, err := parseUint64FromHexSlice(buf[i+1 : i+5])
 				if err != nil {
-					return nil, nil, fmt.Errorf("cannot decode textual string: %s", err)
+					return nil, nil, fmt.Errorf("cannot decode textual string: %w", err)
 				}
 				i += 4 // absorb 4 characters: one 'u' and three of the digits
 
@@ -197,7 +254,7 @@ func stringNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 
 					v, err = parseUint64FromHexSlice(buf[i+2 : i+6])
 					if err != nil {
-						return nil, nil, fmt.Errorf("cannot decode textual string: %s", err)
+						return nil, nil, fmt.Errorf("cannot decode textual string: %w", err)
 					}
 					i += 5 // absorb 5 characters: two for '\u', and 3 of the 4 digits
 
@@ -222,7 +279,7 @@ func stringNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 		newBytes = append(newBytes, b)
 	}
 	if escaped {
-		return nil, nil, fmt.Errorf("cannot decode textual string: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode textual string: %w", ErrShortBuffer{})
 	}
 	return nil, nil, fmt.Errorf("cannot decode textual string: expected final \"; found: %x", buf[buflen-1])
 }
@@ -248,11 +305,11 @@ func unescapeUnicodeString(some string) (string, error) {
 				// subtract another 1 because already consumed u but have yet to
 				// increment i.
 				if i > buflen-6 {
-					return "", fmt.Errorf("cannot replace escaped characters with UTF-8 equivalent: %s", io.ErrShortBuffer)
+					return "", fmt.Errorf("cannot replace escaped characters with UTF-8 equivalent: %w", ErrShortBuffer{})
 				}
 				v, err := parseUint64FromHexSlice(buf[i+1 : i+5])
 				if err != nil {
-					return "", fmt.Errorf("cannot replace escaped characters with UTF-8 equivalent: %s", err)
+					return "", fmt.Errorf("cannot replace escaped characters with UTF-8 equivalent: %w", err)
 				}
 				i += 4 // absorb 4 characters: one 'u' and three of the digits
 
@@ -270,7 +327,7 @@ func unescapeUnicodeString(some string) (string, error) {
 
 					v, err = parseUint64FromHexSlice(buf[i+2 : i+6])
 					if err != nil {
-						return "", fmt.Errorf("cannot replace escaped characters with UTF-8 equivalents: %s", err)
+						return "", fmt.Errorf("cannot replace escaped characters with UTF-8 equivalents: %w", err)
 					}
 					i += 5 // absorb 5 characters: two for '\u', and 3 of the 4 digits
 
@@ -292,7 +349,7 @@ func unescapeUnicodeString(some string) (string, error) {
 		newBytes = append(newBytes, b)
 	}
 	if escaped {
-		return "", fmt.Errorf("cannot replace escaped characters with UTF-8 equivalents: %s", io.ErrShortBuffer)
+		return "", fmt.Errorf("cannot replace escaped characters with UTF-8 equivalents: %w", ErrShortBuffer{})
 	}
 	return string(newBytes), nil
 }
@@ -471,7 +528,7 @@ func decodedStringFromJSON(buf []byte) (string, []byte, error) {
 	fmt.Fprintf(os.Stderr, "decodedStringFromJSON(%v)\n", buf)
 	buflen := len(buf)
 	if buflen < 2 {
-		return "", buf, fmt.Errorf("cannot decode string: %s", io.ErrShortBuffer)
+		return "", buf, fmt.Errorf("cannot decode string: %w", ErrShortBuffer{})
 	}
 	if buf[0] != '"' {
 		return "", buf, fmt.Errorf("cannot decode string: expected initial '\"'; found: %#U", buf[0])
@@ -494,11 +551,11 @@ func decodedStringFromJSON(buf []byte) (string, []byte, error) {
 				// subtract another 1 because already consumed u but have yet to
 				// increment i.
 				if i > buflen-6 {
-					return "", buf[i+1:], fmt.Errorf("cannot decode string: %s", io.ErrShortBuffer)
+					return "", buf[i+1:], fmt.Errorf("cannot decode string: %w", ErrShortBuffer{})
 				}
 				v, err := parseUint64FromHexSlice(buf[i+1 : i+5])
 				if err != nil {
-					return "", buf[i+1:], fmt.Errorf("cannot decode string: %s", err)
+					return "", buf[i+1:], fmt.Errorf("cannot decode string: %w", err)
 				}
 				i += 4 // absorb 4 characters: one 'u' and three of the digits
 
@@ -516,7 +573,7 @@ func decodedStringFromJSON(buf []byte) (string, []byte, error) {
 
 					v, err = parseUint64FromHexSlice(buf[i+2 : i+6])
 					if err != nil {
-						return "", buf[i+1:], fmt.Errorf("cannot decode string: cannot decode second half of surrogate pair: %s", err)
+						return "", buf[i+1:], fmt.Errorf("cannot decode string: cannot decode second half of surrogate pair: %w", err)
 					}
 					i += 5 // absorb 5 characters: two for '\u', and 3 of the 4 digits
 
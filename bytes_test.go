@@ -10,9 +10,13 @@
 package goavro
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"math/big"
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestSchemaPrimitiveCodecBytes(t *testing.T) {
@@ -62,6 +66,129 @@ func TestPrimitiveBytesText(t *testing.T) {
 
 	testTextCodecPass(t, `"bytes"`, []byte("⌘ "), []byte("\"\\u0001\\u00E2\\u008C\\u0098 \""))
 	testTextCodecPass(t, `"bytes"`, []byte("😂"), []byte(`"\u00F0\u009F\u0098\u0082"`))
+
+	// each byte is a code point between 0 and 255
+	testTextDecodeFail(t, `"bytes"`, []byte(`"\u0100"`), "code point ought to be between 0 and 255; received: U+0100")
+	testTextDecodeFail(t, `"bytes"`, []byte(`"\uFFFF"`), "code point ought to be between 0 and 255")
+	testTextDecodeFail(t, `"bytes"`, []byte(`"⌘"`), "code point ought to be between 0 and 255; received: U+2318")
+	testTextDecodeFail(t, `"bytes"`, []byte("\"\xff\""), "invalid UTF-8 byte: 0xff")
+	testTextDecodePass(t, `"bytes"`, []byte("\xe9\xff"), []byte(`"éÿ"`))
+	testTextDecodePass(t, `"bytes"`, []byte("\xe9\xff"), []byte(`"\u00e9\u00FF"`))
+}
+
+func TestPrimitiveBytesTextAllBytes(t *testing.T) {
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+	codec, err := NewCodec(`"bytes"`)
+	ensureError(t, err)
+	text, err := codec.TextualFromNative(nil, all)
+	ensureError(t, err)
+	for _, b := range text {
+		if b >= utf8.RuneSelf {
+			t.Fatalf("GOT: %q; WANT: ASCII text", text)
+		}
+	}
+	native, _, err := codec.NativeFromTextual(text)
+	ensureError(t, err)
+	if actual := native.([]byte); !bytes.Equal(actual, all) {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, all)
+	}
+
+	// fixed values use the same encoding
+	codec, err = NewCodec(`{"type":"fixed","name":"f1","size":256}`)
+	ensureError(t, err)
+	fixedText, err := codec.TextualFromNative(nil, all)
+	ensureError(t, err)
+	if !bytes.Equal(fixedText, text) {
+		t.Errorf("GOT: %s; WANT: %s", fixedText, text)
+	}
+	native, _, err = codec.NativeFromTextual(fixedText)
+	ensureError(t, err)
+	if actual := native.([]byte); !bytes.Equal(actual, all) {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, all)
+	}
+}
+
+func TestBytesAsString(t *testing.T) {
+	const schema = `{"type":"record","name":"r1","fields":[
+		{"name":"payload","type":"bytes"},
+		{"name":"maybe","type":["null",{"type":"bytes"}]},
+		{"name":"list","type":{"type":"array","items":"bytes"}},
+		{"name":"hash","type":{"type":"fixed","name":"f1","size":2}},
+		{"name":"amount","type":{"type":"bytes","logicalType":"decimal","precision":4,"scale":2}}
+	]}`
+	datum := map[string]interface{}{
+		"payload": []byte("hi"),
+		"maybe":   Union("bytes", []byte("yo")),
+		"list":    []interface{}{[]byte("a"), "b"},
+		"hash":    []byte("ab"),
+		"amount":  big.NewRat(5, 4),
+	}
+
+	// bytes decode as []byte by default
+	codec, err := NewCodec(schema)
+	ensureError(t, err)
+	buf, err := codec.BinaryFromNative(nil, datum)
+	ensureError(t, err)
+	native, _, err := codec.NativeFromBinary(buf)
+	ensureError(t, err)
+	if actual, ok := native.(map[string]interface{})["payload"].([]byte); !ok || string(actual) != "hi" {
+		t.Errorf("GOT: %#v; WANT: []byte(\"hi\")", native.(map[string]interface{})["payload"])
+	}
+
+	for _, codec := range []*Codec{
+		func() *Codec {
+			codec, err := NewCodecWithOptions(schema, WithBytesAsString())
+			ensureError(t, err)
+			return codec
+		}(),
+		func() *Codec {
+			derived, err := codec.With(WithBytesAsString())
+			ensureError(t, err)
+			return derived
+		}(),
+	} {
+		native, _, err := codec.NativeFromBinary(buf)
+		ensureError(t, err)
+		m := native.(map[string]interface{})
+		if actual, expected := fmt.Sprintf("%#v %#v %#v %#v %v", m["payload"], *m["maybe"].(*string), m["list"], m["hash"], m["amount"]), `"hi" "yo" []interface {}{"a", "b"} []byte{0x61, 0x62} 5/4`; actual != expected {
+			t.Errorf("GOT: %s; WANT: %s", actual, expected)
+		}
+
+		// decoded strings encode back to the same data
+		actual, err := codec.BinaryFromNative(nil, map[string]interface{}{
+			"payload": m["payload"],
+			"maybe":   Union("bytes", *m["maybe"].(*string)),
+			"list":    m["list"],
+			"hash":    m["hash"],
+			"amount":  m["amount"],
+		})
+		ensureError(t, err)
+		if !bytes.Equal(actual, buf) {
+			t.Errorf("GOT: %#v; WANT: %#v", actual, buf)
+		}
+
+		text, err := codec.TextualFromNative(nil, native)
+		ensureError(t, err)
+		native, _, err = codec.NativeFromTextual(text)
+		ensureError(t, err)
+		if actual, ok := native.(map[string]interface{})["payload"].(string); !ok || actual != "hi" {
+			t.Errorf("GOT: %#v; WANT: \"hi\"", native.(map[string]interface{})["payload"])
+		}
+	}
+
+	// strings promoted to bytes also decode as strings
+	resolved, err := NewCodecForReaderWriter(`"bytes"`, `"string"`)
+	ensureError(t, err)
+	resolved, err = resolved.With(WithBytesAsString())
+	ensureError(t, err)
+	native, _, err = resolved.NativeFromBinary([]byte("\x04hi"))
+	ensureError(t, err)
+	if actual, ok := native.(string); !ok || actual != "hi" {
+		t.Errorf("GOT: %#v; WANT: \"hi\"", native)
+	}
 }
 
 func TestSchemaPrimitiveStringCodec(t *testing.T) {
@@ -10,12 +10,51 @@
 package goavro
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"sort"
 	"strconv"
 	"strings"
 )
 
+// NormalizeSchema returns the schema with its insignificant whitespace removed,
+// the keys of its objects sorted, and its strings and their escapes written the
+// same way, so schemas which differ only in their formatting are returned as
+// the same string, which may be used to deduplicate schemas. Unlike the Parsing
+// Canonical Form returned by CanonicalSchema, every attribute of the schema is
+// kept, including documentation, default values, and custom properties, and
+// arrays, such as union members, record fields, and enum symbols, keep their
+// order. Numbers are kept as written. An error is returned when the schema is
+// not valid.
+//
+//     a, _ := goavro.NormalizeSchema(`{ "type": "array", "items": "int" }`)
+//     b, _ := goavro.NormalizeSchema(`{"items":"int","type":"array"}`)
+//     fmt.Println(a == b, a)
+//     // Output: true {"items":"int","type":"array"}
+func NormalizeSchema(schema string) (string, error) {
+	if _, err := NewCodec(schema); err != nil {
+		return "", err
+	}
+	decoder := json.NewDecoder(strings.NewReader(schema))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("cannot unmarshal schema JSON: %w", err) // should not get here because schema was validated above
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return "", fmt.Errorf("cannot unmarshal schema JSON: data ought not follow schema") // should not get here because schema was validated above
+	}
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", fmt.Errorf("cannot marshal schema JSON: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
 // pcfProcessor is a function type that given a parsed JSON object, returns its
 // Parsing Canonical Form according to the Avro specification.
 type pcfProcessor func(s interface{}) (string, error)
@@ -33,7 +72,7 @@ func parsingCanonicalForm(schema interface{}, parentNamespace string, typeLookup
 		return pcfArray(val, parentNamespace, typeLookup)
 	case string:
 		// JSON string values are decoded as a Go string
-		return pcfString(val, typeLookup)
+		return pcfString(val, parentNamespace, typeLookup)
 	case float64:
 		// JSON numerical values are decoded as Go float64
 		return pcfNumber(val)
@@ -47,15 +86,23 @@ func pcfNumber(val float64) (string, error) {
 	return strconv.FormatFloat(val, 'g', -1, 64), nil
 }
 
-// pcfString returns the parsing canonical form for a string value.
-func pcfString(val string, typeLookup map[string]string) (string, error) {
+// pcfString returns the parsing canonical form for a string value, which is a
+// reference to a primitive or to a previously defined named type. References to
+// named types are replaced with their full names.
+func pcfString(val string, parentNamespace string, typeLookup map[string]string) (string, error) {
+	if parentNamespace != "" && !strings.ContainsRune(val, '.') {
+		if canonicalName, ok := typeLookup[parentNamespace+"."+val]; ok {
+			return `"` + canonicalName + `"`, nil
+		}
+	}
 	if canonicalName, ok := typeLookup[val]; ok {
 		return `"` + canonicalName + `"`, nil
 	}
 	return `"` + val + `"`, nil
 }
 
-// pcfArray returns the parsing canonical form for a JSON array.
+// pcfArray returns the parsing canonical form for a JSON array, which is a
+// union of schemas.
 func pcfArray(val []interface{}, parentNamespace string, typeLookup map[string]string) (string, error) {
 	items := make([]string, len(val))
 	for i, el := range val {
@@ -70,50 +117,84 @@ func pcfArray(val []interface{}, parentNamespace string, typeLookup map[string]s
 
 // pcfObject returns the parsing canonical form for a JSON object.
 func pcfObject(jsonMap map[string]interface{}, parentNamespace string, typeLookup map[string]string) (string, error) {
-	pairs := make(stringPairs, 0, len(jsonMap))
+	objectType, _ := jsonMap["type"].(string)
+
+	switch objectType {
+	case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+		// Reduce primitive schemas to their simple form, which also drops
+		// attributes such as logicalType.
+		return `"` + objectType + `"`, nil
+	}
 
-	// Remember the namespace to fully qualify names later
-	var namespace string
-	if namespaceJSON, ok := jsonMap["namespace"]; ok {
-		if namespaceStr, ok := namespaceJSON.(string); ok {
-			// and it's value is string (otherwise invalid schema)
-			if parentNamespace == "" {
+	// Named types have their name replaced with their full name, and provide
+	// the enclosing namespace for the schemas nested within them. A namespace
+	// attribute is never relative to the enclosing namespace, and does not
+	// qualify a name that is already qualified, although, matching the
+	// reference implementation, it remains the enclosing namespace of nested
+	// schemas.
+	var fullName string
+	if objectType == "record" || objectType == "error" || objectType == "enum" || objectType == "fixed" {
+		if name, ok := jsonMap["name"].(string); ok {
+			namespace := parentNamespace
+			namespaceStr, hasNamespace := jsonMap["namespace"].(string)
+			if hasNamespace {
 				namespace = namespaceStr
+			}
+			if index := strings.LastIndexByte(name, '.'); index >= 0 {
+				fullName = name
+				if !hasNamespace {
+					namespace = name[:index]
+				}
+			} else if namespace != "" {
+				fullName = namespace + "." + name
 			} else {
-				namespace = parentNamespace + "." + namespaceStr
+				fullName = name
 			}
+			typeLookup[fullName] = fullName
 			parentNamespace = namespace
 		}
-	} else if objectType, ok := jsonMap["type"]; ok && (objectType == "record" || objectType == "enum" || objectType == "fixed") {
-		namespace = parentNamespace
 	}
 
-	for k, v := range jsonMap {
-
-		// Reduce primitive schemas to their simple form.
-		if len(jsonMap) == 1 && k == "type" {
-			if t, ok := v.(string); ok {
-				return "\"" + t + "\"", nil
-			}
+	if objectType == "error" {
+		// Matching the reference implementation, an error has the same
+		// canonical form as a record with the same name and fields.
+		record := make(map[string]interface{}, len(jsonMap))
+		for k, v := range jsonMap {
+			record[k] = v
 		}
+		record["type"] = "record"
+		jsonMap = record
+	}
 
-		// Only keep relevant attributes (strip 'doc', 'alias', 'namespace')
-		if _, ok := fieldOrder[k]; !ok {
-			continue
-		}
+	return pcfAttributes(jsonMap, fullName, parentNamespace, typeLookup)
+}
 
-		// Add namespace to a non-qualified name.
-		if k == "name" && namespace != "" {
-			// Check if the name isn't already qualified.
-			if t, ok := v.(string); ok && !strings.ContainsRune(t, '.') {
-				v = namespace + "." + t
-				typeLookup[t] = v.(string)
-			}
-		}
+// pcfAttributes returns the parsing canonical form for the attributes of a JSON
+// object which are relevant to the canonical form, replacing its name with
+// fullName when provided.
+func pcfAttributes(jsonMap map[string]interface{}, fullName string, parentNamespace string, typeLookup map[string]string) (string, error) {
+	pairs := make(stringPairs, 0, len(jsonMap))
 
-		// Only fixed type allows size, and we must convert a string size to a
-		// float.
-		if k == "size" {
+	for k, v := range jsonMap {
+		var pv string
+		var err error
+
+		// Only keep relevant attributes (strip 'doc', 'alias', 'namespace')
+		switch k {
+		case "name":
+			if fullName != "" {
+				v = fullName
+			}
+			pv, err = pcfLiteral(v)
+		case "type", "items", "values":
+			pv, err = parsingCanonicalForm(v, parentNamespace, typeLookup)
+		case "fields":
+			pv, err = pcfFields(v, parentNamespace, typeLookup)
+		case "symbols":
+			pv, err = pcfLiterals(v)
+		case "size":
+			// Only fixed type allows size, and we must convert a string size
+			// to a float.
 			if s, ok := v.(string); ok {
 				s, err := strconv.ParseUint(s, 10, 0)
 				if err != nil {
@@ -122,18 +203,15 @@ func pcfObject(jsonMap map[string]interface{}, parentNamespace string, typeLooku
 				}
 				v = float64(s)
 			}
+			pv, err = parsingCanonicalForm(v, parentNamespace, typeLookup)
+		default:
+			continue
 		}
-
-		pk, err := parsingCanonicalForm(k, parentNamespace, typeLookup)
-		if err != nil {
-			return "", err
-		}
-		pv, err := parsingCanonicalForm(v, parentNamespace, typeLookup)
 		if err != nil {
 			return "", err
 		}
 
-		pairs = append(pairs, stringPair{k, pk + ":" + pv})
+		pairs = append(pairs, stringPair{k, `"` + k + `":` + pv})
 	}
 
 	// Sort keys by their order in specification.
@@ -141,6 +219,64 @@ func pcfObject(jsonMap map[string]interface{}, parentNamespace string, typeLooku
 	return "{" + strings.Join(pairs.Bs(), ",") + "}", nil
 }
 
+// pcfFields returns the parsing canonical form for the fields of a record. A
+// field's name is kept as is, unless the field is itself an inline named type,
+// in which case it is canonicalized like any other named type.
+func pcfFields(val interface{}, parentNamespace string, typeLookup map[string]string) (string, error) {
+	fields, ok := val.([]interface{})
+	if !ok {
+		return "", fmt.Errorf("cannot parse schema with invalid record fields; ought to be []interface{}; received: %T: %v", val, val)
+	}
+	items := make([]string, len(fields))
+	for i, field := range fields {
+		fieldMap, ok := field.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("cannot parse schema with invalid record field; ought to be map[string]interface{}; received: %T: %v", field, field)
+		}
+		var p string
+		var err error
+		switch fieldMap["type"] {
+		case "record", "error", "enum", "fixed":
+			p, err = pcfObject(fieldMap, parentNamespace, typeLookup)
+		default:
+			p, err = pcfAttributes(fieldMap, "", parentNamespace, typeLookup)
+		}
+		if err != nil {
+			return "", err
+		}
+		items[i] = p
+	}
+	return "[" + strings.Join(items, ",") + "]", nil
+}
+
+// pcfLiteral returns the parsing canonical form for a string value which is
+// not a reference to a type, such as a field name or an enum symbol.
+func pcfLiteral(val interface{}) (string, error) {
+	s, ok := val.(string)
+	if !ok {
+		return "", fmt.Errorf("cannot parse schema with invalid name; ought to be string; received: %T: %v", val, val)
+	}
+	return `"` + s + `"`, nil
+}
+
+// pcfLiterals returns the parsing canonical form for an array of string values
+// which are not references to types, such as enum symbols.
+func pcfLiterals(val interface{}) (string, error) {
+	values, ok := val.([]interface{})
+	if !ok {
+		return "", fmt.Errorf("cannot parse schema with invalid symbols; ought to be []interface{}; received: %T: %v", val, val)
+	}
+	items := make([]string, len(values))
+	for i, el := range values {
+		p, err := pcfLiteral(el)
+		if err != nil {
+			return "", err
+		}
+		items[i] = p
+	}
+	return "[" + strings.Join(items, ",") + "]", nil
+}
+
 // stringPair represents a pair of string values.
 type stringPair struct {
 	A string
@@ -268,6 +268,40 @@ func TestCanonicalSchema(t *testing.T) {
 			Schema:    `{"type":"record","name":"foo","namespace":"bar","fields":[{"type":"record","name":"baz","fields":[{"name":"hi","type":"int"}]},{"name":"bye", "type":["null","baz"]}]}`,
 			Canonical: `{"name":"bar.foo","type":"record","fields":[{"name":"bar.baz","type":"record","fields":[{"name":"hi","type":"int"}]},{"name":"bye","type":["null","bar.baz"]}]}`,
 		},
+		{
+			// nested namespace is not relative to the enclosing namespace
+			Schema:    `{"type":"record","name":"foo","namespace":"x.y","fields":[{"name":"f","type":{"type":"record","name":"bar","namespace":"z","fields":[]}}]}`,
+			Canonical: `{"name":"x.y.foo","type":"record","fields":[{"name":"f","type":{"name":"z.bar","type":"record","fields":[]}}]}`,
+		},
+		{
+			// qualified name provides the enclosing namespace
+			Schema:    `{"type":"record","name":"a.b.foo","fields":[{"name":"f","type":{"type":"enum","name":"e","symbols":["A"]}},{"name":"g","type":["null","e"]}]}`,
+			Canonical: `{"name":"a.b.foo","type":"record","fields":[{"name":"f","type":{"name":"a.b.e","type":"enum","symbols":["A"]}},{"name":"g","type":["null","a.b.e"]}]}`,
+		},
+		{
+			// field names and enum symbols are not type references
+			Schema:    `{"type":"record","name":"foo","namespace":"n","fields":[{"name":"e","type":{"type":"enum","name":"e","symbols":["foo","e"]}},{"name":"foo","type":"e"}]}`,
+			Canonical: `{"name":"n.foo","type":"record","fields":[{"name":"e","type":{"name":"n.e","type":"enum","symbols":["foo","e"]}},{"name":"foo","type":"n.e"}]}`,
+		},
+		{
+			// logical types reduce to their primitive type
+			Schema:    `{"type":"int","logicalType":"date"}`,
+			Canonical: `"int"`,
+		},
+		{
+			Schema:    `{"type":"record","name":"foo","fields":[{"name":"f","type":{"type":"long","logicalType":"timestamp-millis"}}]}`,
+			Canonical: `{"name":"foo","type":"record","fields":[{"name":"f","type":"long"}]}`,
+		},
+		{
+			// fixed nested in a union within an array
+			Schema:    `{"type":"array","items":["null",{"type":"fixed","name":"md5","namespace":"h","size":16,"doc":"hash"}]}`,
+			Canonical: `{"type":"array","items":["null",{"name":"h.md5","type":"fixed","size":16}]}`,
+		},
+		{
+			// an error has the canonical form of a record
+			Schema:    `{"type":"error","name":"Oops","namespace":"com.example","fields":[{"name":"message","type":"string"},{"name":"cause","type":["null","Oops"]}]}`,
+			Canonical: `{"name":"com.example.Oops","type":"record","fields":[{"name":"message","type":"string"},{"name":"cause","type":["null","com.example.Oops"]}]}`,
+		},
 	}
 
 	for _, c := range cases {
@@ -281,3 +315,44 @@ func TestCanonicalSchema(t *testing.T) {
 		}
 	}
 }
+
+func TestNormalizeSchema(t *testing.T) {
+	a := `{
+		"type": "record",
+		"name": "r1",
+		"doc": "café <1>",
+		"fields": [
+			{"name": "b", "type": ["null", "string"], "default": null},
+			{"name": "a", "type": {"type": "enum", "name": "e1", "symbols": ["Z", "A"]}, "custom": 12345678901234567890}
+		]
+	}`
+	b := `{"fields":[{"default":null,"type":["null","string"],"name":"b"},{"custom":12345678901234567890,"type":{"symbols":["Z","A"],"name":"e1","type":"enum"},"name":"a"}],"doc":"café <1>","name":"r1","type":"record"}`
+	expected := `{"doc":"café <1>","fields":[{"default":null,"name":"b","type":["null","string"]},{"custom":12345678901234567890,"name":"a","type":{"name":"e1","symbols":["Z","A"],"type":"enum"}}],"name":"r1","type":"record"}`
+
+	for _, schema := range []string{a, b} {
+		actual, err := NormalizeSchema(schema)
+		ensureError(t, err)
+		if actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	}
+
+	// union members and record fields are significant, so are not reordered
+	for _, pair := range [][2]string{
+		{`["null","int"]`, `["int","null"]`},
+		{`{"type":"record","name":"r1","fields":[{"name":"a","type":"int"},{"name":"b","type":"int"}]}`, `{"type":"record","name":"r1","fields":[{"name":"b","type":"int"},{"name":"a","type":"int"}]}`},
+	} {
+		first, err := NormalizeSchema(pair[0])
+		ensureError(t, err)
+		second, err := NormalizeSchema(pair[1])
+		ensureError(t, err)
+		if first == second {
+			t.Errorf("GOT: %v; WANT: different from %v", first, second)
+		}
+	}
+
+	_, err := NormalizeSchema(`{"type":"array"}`)
+	ensureError(t, err, "Array ought to have items key")
+	_, err = NormalizeSchema(`"int" "int"`)
+	ensureError(t, err, "cannot unmarshal schema JSON")
+}
@@ -11,11 +11,15 @@ package goavro
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 var (
@@ -28,7 +32,8 @@ var (
 	//
 	// If a particular application needs to decode binary Avro data that
 	// potentially has more data items in a single block, then this variable may
-	// be modified at your discretion.
+	// be modified at your discretion. A Codec created using the
+	// WithMaxBlockCount option uses its own limit for arrays and maps instead.
 	MaxBlockCount = int64(math.MaxInt32)
 
 	// MaxBlockSize is the maximum number of bytes that will be allocated for a
@@ -38,10 +43,16 @@ var (
 	//
 	// If a particular application needs to decode binary Avro data that
 	// potentially has more bytes in a single block, then this variable may be
-	// modified at your discretion.
+	// modified at your discretion. A Codec created using the WithMaxBlockSize
+	// option uses its own limit for arrays and maps instead.
 	MaxBlockSize = int64(math.MaxInt32)
 )
 
+// DefaultMaxDecodeDepth is the number of unions, arrays, maps, and records
+// which may be nested within one another in a value of a recursive schema
+// decoded from binary, unless the Codec was created using WithMaxDecodeDepth.
+const DefaultMaxDecodeDepth = 1000
+
 // Codec supports decoding binary and text Avro data to Go native data types,
 // and conversely encoding Go native data types to binary or text Avro data. A
 // Codec is created as a stateless structure that can be safely used in multiple
@@ -57,6 +68,86 @@ type Codec struct {
 	nativeFromBinary  func([]byte) (interface{}, []byte, error)
 	textualFromNative func([]byte, interface{}) ([]byte, error)
 
+	// recordFromBinary is only set for records, and decodes the fields of a
+	// binary record into the provided map.
+	recordFromBinary func([]byte, map[string]interface{}) ([]byte, error)
+
+	// resolvedFromBinary is only set for the codec returned by
+	// NewCodecForReaderWriter, and decodes binary data encoded using its
+	// writer schema.
+	resolvedFromBinary resolvedDecoder
+
+	// textualStandardFromNative and nativeFromTextualStandard are only set for
+	// unions, and for the arrays, maps, and records which may contain them,
+	// because those are the only types whose standard JSON differs from their
+	// textual Avro data.
+	textualStandardFromNative func([]byte, interface{}) ([]byte, error)
+	nativeFromTextualStandard func([]byte) (interface{}, []byte, error)
+
+	// The following describe the structure of the schema, and are used to
+	// resolve binary data encoded using a different writer schema. The
+	// schemaType is only set for the named types, and for logical types which
+	// use a name, because the type name of other codecs is their Avro type.
+	schemaType   string
+	aliases      []string       // fully qualified aliases of named types
+	itemCodec    *Codec         // array items and map values
+	recordFields []*recordField // record fields in schema order
+	enumSymbols  []string
+	enumDefault  string // empty when the enum declares no default symbol
+	fixedSize    uint
+	unionInfo    *codecInfo
+
+	// annotated is only set for the codec of a logical type registered using
+	// RegisterLogicalType, and is the codec of the type it annotates, whose
+	// values handler converts to and from those of the logical type.
+	annotated   *Codec
+	logicalType string
+	handler     *logicalTypeHandler
+
+	// decodeLimits bounds the arrays and maps decoded by array and map codecs.
+	decodeLimits decodeLimits
+
+	// strictStructFields makes BinaryFromStruct reject struct fields which do
+	// not match any record field.
+	strictStructFields bool
+
+	// strictRecordFields makes the Codec reject datum keys which do not name a
+	// record field, checking the datum before encoding it, and standardJSON
+	// makes the Codec decode and encode standard JSON rather than textual Avro
+	// data. Both only apply to the Codec itself, so Codecs differing only by
+	// them share the codecs of their schema.
+	strictRecordFields bool
+	standardJSON       bool
+
+	// enumValues makes enum decoders return Enum values rather than strings.
+	enumValues bool
+
+	// orderedRecords makes record decoders return Record values rather than
+	// maps.
+	orderedRecords bool
+
+	// sortMapKeys makes map encoders encode keys in lexicographic order.
+	sortMapKeys bool
+
+	// bytesAsString makes bytes decoders return strings rather than []byte.
+	bytesAsString bool
+
+	// recursive is set for the codec returned by NewCodecFrom when its schema
+	// contains a record which refers to itself, so values of the schema may be
+	// nested arbitrarily deep, and are decoded from binary while limiting their
+	// depth.
+	recursive bool
+
+	// options holds the options the codec was created with, and writerSchema
+	// the writer schema of a codec created by NewCodecForReaderWriter, so With
+	// may create the codec again using other options.
+	options      codecOptions
+	writerSchema string
+
+	// schema is the tree returned by ParsedSchema, which is only set for the
+	// codec returned by NewCodecFrom.
+	schema Schema
+
 	Rabin uint64
 }
 
@@ -82,7 +173,8 @@ type codecBuilder struct {
 // used in a program. Internally a `Codec` is merely a named tuple of
 // four function pointers, and maintains no runtime state that is mutated
 // after instantiation. In other words, `Codec`s may be safely used by
-// many go routines simultaneously, as your program requires.
+// many go routines simultaneously, as your program requires, and may likewise
+// be created from many go routines simultaneously.
 //
 //     codec, err := goavro.NewCodec(`
 //         {
@@ -103,21 +195,342 @@ func NewCodec(schemaSpecification string) (*Codec, error) {
 	})
 }
 
+// NewCodecForStandardJSON returns a Codec like NewCodec does, but whose
+// NativeFromTextual method decodes standard JSON rather than Avro JSON.
+//
+// The two differ only for unions. Avro JSON encodes a union value other than
+// null as a single key object naming the member type, such as
+// `{"string":"some string"}`, while standard JSON, as produced by most programs,
+// has only the bare value, such as `"some string"`. This Codec infers the union
+// member from the bare value, and decodes it to the same native form a Codec
+// created by NewCodec decodes Avro JSON to, so it may be encoded as binary.
+// Likewise its TextualFromNative method encodes standard JSON, so standard JSON
+// round trips through the Codec.
+//
+//     codec, err := goavro.NewCodecForStandardJSON(`["null","string","int"]`)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     native, _, err := codec.NativeFromTextual([]byte(`"some string"`))
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Println(native) // map[string:some string]
 func NewCodecForStandardJSON(schemaSpecification string) (*Codec, error) {
-	return NewCodecFrom(schemaSpecification, &codecBuilder{
-		buildCodecForTypeDescribedByMap,
-		buildCodecForTypeDescribedByString,
-		buildCodecForTypeDescribedBySliceJSON,
+	return newCodecWithOptions(schemaSpecification, codecOptions{standardJSON: true})
+}
+
+// CodecOption configures a Codec created by NewCodecWithOptions.
+type CodecOption func(*codecOptions)
+
+type codecOptions struct {
+	schemaOptions
+	unionResolver      func(datum interface{}) (string, bool)
+	maxDecodeDepth     int
+	strictStructFields bool
+	strictRecordFields bool
+	standardJSON       bool
+}
+
+// schemaOptions holds the options which configure the codecs of the types
+// within a schema, so a Codec whose schemaOptions change must be created from
+// its schema again. It only holds comparable fields, so the options of two
+// Codecs can be compared; the union resolver, which also configures those
+// codecs, is a function, so it is held by codecOptions instead.
+type schemaOptions struct {
+	decodeLimits      decodeLimits
+	enumValues        bool
+	orderedRecords    bool
+	sortMapKeys       bool
+	bytesAsString     bool
+	nonFiniteLiterals bool
+	floatToIntPolicy  FloatToIntPolicy
+}
+
+// decodeLimits bounds the binary arrays and maps decoded by a Codec. A zero
+// block limit defers to the corresponding package variable, so changes to those
+// still apply, while a zero maxItemCount does not limit the number of items.
+type decodeLimits struct {
+	maxBlockCount int64
+	maxBlockSize  int64
+	maxItemCount  int64 // of a single array or map, across all its blocks
+}
+
+func (l decodeLimits) blockCount() int64 {
+	if l.maxBlockCount > 0 {
+		return l.maxBlockCount
+	}
+	return MaxBlockCount
+}
+
+func (l decodeLimits) blockSize() int64 {
+	if l.maxBlockSize > 0 {
+		return l.maxBlockSize
+	}
+	return MaxBlockSize
+}
+
+// itemCountExceeded returns true when decoding another block of blockCount
+// items of an array or map which already has itemCount items exceeds the limit
+// on its number of items.
+func (l decodeLimits) itemCountExceeded(itemCount int, blockCount int64) bool {
+	return l.maxItemCount > 0 && blockCount > l.maxItemCount-int64(itemCount)
+}
+
+// WithUnionResolver returns a CodecOption which makes the union encoders of the
+// Codec consult resolver to select the union member used to encode a datum.
+// When resolver returns true, the datum is encoded as is using the union member
+// whose full name it returned. Otherwise the datum is encoded as if no resolver
+// were registered, which requires a null, a pointer, or a single key map naming
+// the union member, as returned by the Union function.
+//
+// This allows encoding values of a union that cannot be told apart from their
+// Go type, such as a union of two records.
+//
+//     codec, err := goavro.NewCodecWithOptions(`[{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]},{"type":"record","name":"r2","fields":[{"name":"b","type":"int"}]}]`,
+//         goavro.WithUnionResolver(func(datum interface{}) (string, bool) {
+//             if m, ok := datum.(map[string]interface{}); ok {
+//                 if _, ok = m["b"]; ok {
+//                     return "r2", true
+//                 }
+//                 return "r1", true
+//             }
+//             return "", false
+//         }))
+func WithUnionResolver(resolver func(datum interface{}) (branchName string, ok bool)) CodecOption {
+	return func(o *codecOptions) {
+		o.unionResolver = resolver
+	}
+}
+
+// WithMaxBlockCount returns a CodecOption which limits the number of items in
+// a single block of a binary array or map the Codec decodes to count, rather
+// than to MaxBlockCount. Decoding a block with more items returns an error
+// before any of them are allocated.
+//
+// This allows tightening the limit for a Codec decoding untrusted data, without
+// changing it for every other Codec in the program.
+func WithMaxBlockCount(count int64) CodecOption {
+	return func(o *codecOptions) {
+		o.decodeLimits.maxBlockCount = count
+	}
+}
+
+// WithMaxBlockSize returns a CodecOption which limits the number of bytes of a
+// single block of a binary array or map the Codec decodes to size, rather than
+// to MaxBlockSize, when the encoder wrote the block size. Decoding a block
+// declaring a larger size returns an error.
+func WithMaxBlockSize(size int64) CodecOption {
+	return func(o *codecOptions) {
+		o.decodeLimits.maxBlockSize = size
+	}
+}
+
+// WithMaxItemCount returns a CodecOption which limits the number of items of a
+// single binary array or map the Codec decodes to count, however many blocks
+// they are encoded in. The limit is checked as each block is read, so decoding
+// an array or map with more items returns an error before allocating the block
+// which exceeds it. By default the number of items is not limited.
+//
+// Together with WithMaxBlockCount, this protects a program decoding untrusted
+// data from payloads made of many blocks which each respect the block limits.
+func WithMaxItemCount(count int64) CodecOption {
+	return func(o *codecOptions) {
+		o.decodeLimits.maxItemCount = count
+	}
+}
+
+// WithMaxDecodeDepth returns a CodecOption which limits the number of unions,
+// arrays, maps, and records nested within one another in a binary value of a
+// recursive schema the Codec decodes to depth, returning an error for a value
+// nested more deeply rather than exhausting the stack. A depth of zero uses
+// DefaultMaxDecodeDepth. The values of schemas which do not refer to
+// themselves are nested no deeper than their schema, so are not limited.
+func WithMaxDecodeDepth(depth int) CodecOption {
+	return func(o *codecOptions) {
+		o.maxDecodeDepth = depth
+	}
+}
+
+// maxDecodeDepth returns the depth limit of the values the Codec decodes.
+func (c *Codec) maxDecodeDepth() int {
+	if c.options.maxDecodeDepth > 0 {
+		return c.options.maxDecodeDepth
+	}
+	return DefaultMaxDecodeDepth
+}
+
+// containsCycle returns true when the schema of the codec refers to a named
+// type from within its own definition. The visiting map holds true for the
+// codecs whose definitions enclose the codec, and false for the codecs already
+// found to contain no cycle, so each named type is only visited once.
+func (c *Codec) containsCycle(visiting map[*Codec]bool) bool {
+	if enclosing, ok := visiting[c]; ok {
+		return enclosing
+	}
+	visiting[c] = true
+	if c.itemCodec != nil && c.itemCodec.containsCycle(visiting) {
+		return true
+	}
+	for _, field := range c.recordFields {
+		if field.codec.containsCycle(visiting) {
+			return true
+		}
+	}
+	if c.unionInfo != nil {
+		for _, member := range c.unionInfo.codecFromIndex {
+			if member.containsCycle(visiting) {
+				return true
+			}
+		}
+	}
+	visiting[c] = false
+	return false
+}
+
+// WithStrictRecordFields returns a CodecOption which, when enabled is true,
+// makes the record encoders of the Codec return an error listing the keys of a
+// datum which do not name a field of the record, rather than ignoring them.
+// This catches misspelled field names, which would otherwise be encoded using
+// the default value of the field they were meant for. When enabled is false,
+// the option turns this off, such as for a Codec created by With from a strict
+// Codec.
+func WithStrictRecordFields(enabled bool) CodecOption {
+	return func(o *codecOptions) {
+		o.strictRecordFields = enabled
+	}
+}
+
+// WithStandardJSON returns a CodecOption which, when enabled is true, makes
+// the Codec decode and encode standard JSON rather than Avro JSON, like a Codec
+// created by NewCodecForStandardJSON. When enabled is false, the Codec decodes
+// and encodes Avro JSON.
+func WithStandardJSON(enabled bool) CodecOption {
+	return func(o *codecOptions) {
+		o.standardJSON = enabled
+	}
+}
+
+// NewCodecWithOptions returns a Codec like NewCodec does, configured using the
+// provided options.
+func NewCodecWithOptions(schemaSpecification string, options ...CodecOption) (*Codec, error) {
+	var o codecOptions
+	for _, option := range options {
+		option(&o)
+	}
+	return newCodecWithOptions(schemaSpecification, o)
+}
+
+func newCodecWithOptions(schemaSpecification string, o codecOptions) (*Codec, error) {
+	c, err := NewCodecFrom(schemaSpecification, &codecBuilder{
+		func(st map[string]*Codec, enclosingNamespace string, schemaMap map[string]interface{}, cb *codecBuilder) (*Codec, error) {
+			c, err := buildCodecForTypeDescribedByMap(st, enclosingNamespace, schemaMap, cb)
+			if err != nil {
+				return nil, err
+			}
+			switch c.schemaType {
+			case "record":
+				c.orderedRecords = o.orderedRecords
+			case "enum":
+				c.enumValues = o.enumValues
+			}
+			return c, nil
+		},
+		func(st map[string]*Codec, enclosingNamespace string, typeName string, schemaMap map[string]interface{}, cb *codecBuilder) (*Codec, error) {
+			c, err := buildCodecForTypeDescribedByString(st, enclosingNamespace, typeName, schemaMap, cb)
+			if err != nil {
+				return nil, err
+			}
+			if c.itemCodec != nil {
+				// NOTE: Arrays and maps are always built here.
+				c.decodeLimits = o.decodeLimits
+				c.sortMapKeys = o.sortMapKeys
+			}
+			if o.bytesAsString && c.typeName.fullName == "bytes" {
+				// NOTE: The bytes codec is shared by the symbol table, so
+				// each reference to it gets its own copy.
+				c = bytesAsStringCodec(c)
+			}
+			if o.nonFiniteLiterals && (c.typeName.fullName == "float" || c.typeName.fullName == "double") {
+				c = nonFiniteLiteralsCodec(c)
+			}
+			if o.floatToIntPolicy != FloatToIntReject && (c.typeName.fullName == "int" || c.typeName.fullName == "long") {
+				c = floatToIntCodec(c, o.floatToIntPolicy)
+			}
+			return c, nil
+		},
+		func(st map[string]*Codec, enclosingNamespace string, schemaArray []interface{}, cb *codecBuilder) (*Codec, error) {
+			c, err := buildCodecForTypeDescribedBySlice(st, enclosingNamespace, schemaArray, cb)
+			if err != nil {
+				return nil, err
+			}
+			c.unionInfo.resolver = o.unionResolver
+			return c, nil
+		},
 	})
+	if err != nil {
+		return nil, err
+	}
+	c.strictStructFields = o.strictStructFields
+	c.strictRecordFields = o.strictRecordFields
+	c.standardJSON = o.standardJSON
+	c.options = o
+	return c, nil
+}
+
+// With returns a copy of the Codec configured using its own options along with
+// the provided options, leaving the Codec unchanged. When the provided options
+// only configure the Codec itself, such as WithStrictStructFields,
+// WithStrictRecordFields, or WithStandardJSON, the copy shares the codecs of
+// the types within the schema. Other options configure
+// those codecs, so the copy is created from the schema again, as it would be
+// by NewCodecWithOptions, or by NewCodecForReaderWriter for a Codec created
+// by that function.
+//
+//     base, err := goavro.NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]}`)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     strict, err := base.With(goavro.WithStrictRecordFields(true))
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     _, err = strict.BinaryFromNative(nil, map[string]interface{}{"a": 1, "b": 2})
+//     fmt.Println(err) // cannot encode binary record "r1": fields ought to be defined in schema: ["b"]
+func (c *Codec) With(options ...CodecOption) (*Codec, error) {
+	o := c.options
+	var provided codecOptions
+	for _, option := range options {
+		option(&o)
+		option(&provided)
+	}
+	// NOTE: Functions cannot be compared, so providing a union resolver
+	// always creates the copy from the schema again.
+	if provided.unionResolver == nil && o.schemaOptions == c.options.schemaOptions {
+		clone := *c
+		clone.strictStructFields = o.strictStructFields
+		clone.strictRecordFields = o.strictRecordFields
+		clone.standardJSON = o.standardJSON
+		clone.options = o
+		return &clone, nil
+	}
+	if c.writerSchema != "" {
+		return newCodecForReaderWriter(c.schemaOriginal, c.writerSchema, o)
+	}
+	return newCodecWithOptions(c.schemaOriginal, o)
 }
 
 func NewCodecFrom(schemaSpecification string, cb *codecBuilder) (*Codec, error) {
 	var schema interface{}
 
 	if err := json.Unmarshal([]byte(schemaSpecification), &schema); err != nil {
-		return nil, fmt.Errorf("cannot unmarshal schema JSON: %s", err)
+		return nil, fmt.Errorf("cannot unmarshal schema JSON: %w", err)
 	}
 
+	// NOTE: Build the schema tree before building the codec, because the
+	// latter annotates the decoded schema with names for some logical types.
+	tree, treeErr := newSchemaTree(schema)
+
 	// bootstrap a symbol table with primitive type codecs for the new codec
 	st := newSymbolTable()
 
@@ -125,6 +538,11 @@ func NewCodecFrom(schemaSpecification string, cb *codecBuilder) (*Codec, error)
 	if err != nil {
 		return nil, err
 	}
+	if treeErr != nil {
+		return nil, treeErr // should not get here because schema was validated above
+	}
+	c.schema = tree
+	c.recursive = c.containsCycle(make(map[*Codec]bool))
 	c.schemaCanonical, err = parsingCanonicalForm(schema, "", make(map[string]string))
 	if err != nil {
 		return nil, err // should not get here because schema was validated above
@@ -259,6 +677,15 @@ func newSymbolTable() map[string]*Codec {
 			nativeFromBinary:  nativeFromDate(intNativeFromBinary),
 			textualFromNative: dateFromNative(intTextualFromNative),
 		},
+		"string.uuid": {
+			typeName:          &name{"string.uuid", nullNamespace},
+			schemaOriginal:    "string",
+			schemaCanonical:   "string",
+			nativeFromTextual: nativeFromUUID(stringNativeFromTextual),
+			binaryFromNative:  uuidFromNative(stringBinaryFromNative),
+			nativeFromBinary:  nativeFromUUID(stringNativeFromBinary),
+			textualFromNative: uuidFromNative(stringTextualFromNative),
+		},
 	}
 }
 
@@ -303,13 +730,136 @@ func newSymbolTable() map[string]*Codec {
 //         // Output: []byte{0x2, 0x2, 0x0}
 //     }
 func (c *Codec) BinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
-	newBuf, err := c.binaryFromNative(buf, datum)
+	newBuf, err := c.encodeBinary(buf, datum)
 	if err != nil {
 		return buf, err // if error, return original byte slice
 	}
 	return newBuf, nil
 }
 
+// scratchLimit is the largest scratch buffer returned to scratchBuffers, so
+// encoding an occasional large datum does not hold on to its memory.
+const scratchLimit = 64 * 1024
+
+// scratchBuffers holds the buffers used by Valid, and those passed to Release.
+var scratchBuffers = sync.Pool{
+	New: func() interface{} { return new([]byte) },
+}
+
+// scratchHeaders holds the emptied pointers of the buffers handed out by
+// BinaryFromNativePooled, so Release returns buffers to scratchBuffers without
+// allocating.
+var scratchHeaders = sync.Pool{
+	New: func() interface{} { return new([]byte) },
+}
+
+// Valid returns nil when datum conforms to the Avro schema of the Codec, and
+// otherwise returns the error BinaryFromNative would return for it.
+//
+// Valid performs the same checks as BinaryFromNative, including selecting the
+// union member, and checking enum symbols, fixed sizes, and numeric ranges,
+// because it encodes datum into a scratch buffer it then discards. The scratch
+// buffers are reused across calls, so validating values does not allocate
+// output.
+//
+//     if err := codec.Valid(datum); err != nil {
+//         return fmt.Errorf("invalid request: %w", err)
+//     }
+func (c *Codec) Valid(datum interface{}) error {
+	scratch := scratchBuffers.Get().(*[]byte)
+	buf, err := c.encodeBinary((*scratch)[:0], datum)
+	if err != nil {
+		scratchBuffers.Put(scratch) // encoders return nil on error
+		return err
+	}
+	if cap(buf) <= scratchLimit {
+		*scratch = buf
+		scratchBuffers.Put(scratch)
+	}
+	return nil
+}
+
+// BinaryFromNativePooled returns the binary encoded byte slice representation
+// of the provided datum, like BinaryFromNative does, but encodes it into a
+// buffer taken from a pool shared by every Codec, rather than into a newly
+// allocated one. This allows a program encoding many small values to reuse the
+// capacity of the buffers it is done with.
+//
+// The returned byte slice belongs to the caller until it is passed to Release,
+// after which neither it nor any slice of it, such as the bytes and fixed values
+// decoded from it, may be used, because it will be overwritten by a later
+// encoding. It ought to be released at most once. Byte slices which are not
+// released are simply collected like any other. On error, it returns nil and
+// the error BinaryFromNative would return.
+//
+//     buf, err := codec.BinaryFromNativePooled(datum)
+//     if err != nil {
+//         return err
+//     }
+//     _, err = conn.Write(buf)
+//     codec.Release(buf)
+//     return err
+func (c *Codec) BinaryFromNativePooled(datum interface{}) ([]byte, error) {
+	scratch := scratchBuffers.Get().(*[]byte)
+	buf, err := c.encodeBinary((*scratch)[:0], datum)
+	if err != nil {
+		scratchBuffers.Put(scratch) // encoders return nil on error
+		return nil, err
+	}
+	*scratch = nil
+	scratchHeaders.Put(scratch)
+	return buf, nil
+}
+
+// Release returns a byte slice returned by BinaryFromNativePooled to the pool,
+// for use by a later call. See BinaryFromNativePooled for the ownership rules.
+// Byte slices larger than 64 KiB are not pooled, so encoding an occasional
+// large value does not hold on to its memory.
+func (c *Codec) Release(buf []byte) {
+	if cap(buf) == 0 || cap(buf) > scratchLimit {
+		return
+	}
+	scratch := scratchHeaders.Get().(*[]byte)
+	*scratch = buf[:0]
+	scratchBuffers.Put(scratch)
+}
+
+// NativeFromNative returns datum normalized to the native form NativeFromBinary
+// returns for it, ready to be passed to BinaryFromNative or TextualFromNative.
+// On error, it returns nil for the datum value, and the error BinaryFromNative
+// would return for datum.
+//
+// This is useful to normalize values assembled from untyped sources, such as
+// data decoded using the encoding/json package. Numeric values are coerced to
+// the Go type of their schema, such as a float64 to the int32 of an Avro int,
+// record fields which were omitted are set to their default values, and union
+// values are wrapped, such as a string value of a union of null, string, and
+// int becoming a single key map naming its member type, as returned by the
+// Union function.
+//
+//     codec, err := goavro.NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":"int"},{"name":"f2","type":["null","string","int"],"default":null}]}`)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     native, err := codec.NativeFromNative(map[string]interface{}{"f1": float64(3)})
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Println(native) // map[f1:3 f2:<nil>]
+func (c *Codec) NativeFromNative(datum interface{}) (interface{}, error) {
+	// NOTE: Do not use the scratch buffers Valid uses, because decoded bytes
+	// and fixed values may refer to the encoded bytes.
+	buf, err := c.encodeBinary(nil, datum)
+	if err != nil {
+		return nil, err
+	}
+	native, _, err := c.nativeFromBinary(buf)
+	if err != nil {
+		return nil, err
+	}
+	return native, nil
+}
+
 // NativeFromBinary returns a native datum value from the binary encoded byte
 // slice in accordance with the Avro schema supplied when creating the Codec. On
 // success, it returns the decoded datum, a byte slice containing the remaining
@@ -341,13 +891,93 @@ func (c *Codec) BinaryFromNative(buf []byte, datum interface{}) ([]byte, error)
 //         // Output: map[next:map[LongList:map[next:map[LongList:map[next:<nil>]]]]]
 //     }
 func (c *Codec) NativeFromBinary(buf []byte) (interface{}, []byte, error) {
-	value, newBuf, err := c.nativeFromBinary(buf)
+	value, newBuf, err := c.boundedNativeFromBinary(buf)
 	if err != nil {
-		return nil, buf, err // if error, return original byte slice
+		return nil, buf, c.located(err, 0) // if error, return original byte slice
 	}
 	return value, newBuf, nil
 }
 
+// NativeFromBinaryComplete decodes a binary value like NativeFromBinary does,
+// for a byte slice which ought to hold exactly one value. It returns an error
+// when bytes remain after the value, as well as when the value is truncated,
+// which catches framing errors of the program as early as possible.
+//
+//     native, err := codec.NativeFromBinaryComplete(message)
+//     if err != nil {
+//         return err
+//     }
+func (c *Codec) NativeFromBinaryComplete(buf []byte) (interface{}, error) {
+	value, newBuf, err := c.NativeFromBinary(buf)
+	if err != nil {
+		return nil, err
+	}
+	if len(newBuf) > 0 {
+		return nil, fmt.Errorf("cannot decode binary %q: %d bytes ought not to remain after value; offset: %d", c.typeName, len(newBuf), len(buf)-len(newBuf))
+	}
+	return value, nil
+}
+
+// NativeFromBinaryInto decodes a binary record like NativeFromBinary does, but
+// stores its fields in dst, after removing all of its existing keys, rather than
+// in a newly allocated map. This allows a program decoding many records to
+// reuse the same map, although records nested in the decoded record are still
+// allocated. On success, it returns a byte slice containing the remaining
+// undecoded bytes, and a nil error value. On error, it returns the original
+// byte slice, and dst may hold some of the record fields.
+//
+// An error is returned when the schema of the Codec is not a record, or dst is
+// nil.
+//
+//     record := make(map[string]interface{})
+//     for _, buf := range messages {
+//         if _, err := codec.NativeFromBinaryInto(buf, record); err != nil {
+//             return err
+//         }
+//         process(record)
+//     }
+func (c *Codec) NativeFromBinaryInto(buf []byte, dst map[string]interface{}) ([]byte, error) {
+	if c.baseType() != "record" {
+		return buf, fmt.Errorf("cannot decode binary %q into map: schema ought to be a record", c.typeName)
+	}
+	if dst == nil {
+		return buf, fmt.Errorf("cannot decode binary record %q into nil map", c.typeName)
+	}
+	for key := range dst {
+		delete(dst, key)
+	}
+	if c.recordFromBinary == nil {
+		// NOTE: Codecs resolving a writer schema, and records annotated by a
+		// registered logical type, decode records using their own decoder, so
+		// copy the fields of the record it returns.
+		value, newBuf, err := c.boundedNativeFromBinary(buf)
+		if err != nil {
+			return buf, c.located(err, 0)
+		}
+		valueMap, ok := recordValueMap(value)
+		if !ok {
+			return buf, fmt.Errorf("cannot decode binary record %q into map: decoded value ought to be a record; received: %T", c.typeName, value)
+		}
+		for key, field := range valueMap {
+			dst[key] = field
+		}
+		return newBuf, nil
+	}
+	var newBuf []byte
+	var err error
+	if c.recursive {
+		// NOTE: The record itself is the first level of its depth.
+		cd := &contextDecoder{ctx: context.Background(), maxDepth: c.maxDecodeDepth(), depth: 1}
+		newBuf, err = cd.recordFromBinary(c, buf, dst)
+	} else {
+		newBuf, err = c.recordFromBinary(buf, dst)
+	}
+	if err != nil {
+		return buf, c.located(err, 0) // if error, return original byte slice
+	}
+	return newBuf, nil
+}
+
 // NativeFromSingle converts Avro data from Single-Object-Encoded format from
 // the provided byte slice to Go native data types in accordance with the Avro
 // schema supplied when creating the Codec.  On success, it returns the decoded
@@ -371,9 +1001,9 @@ func (c *Codec) NativeFromSingle(buf []byte) (interface{}, []byte, error) {
 	if !bytes.Equal(buf[:len(c.soeHeader)], c.soeHeader) {
 		return nil, buf, ErrWrongCodec(fingerprint)
 	}
-	value, newBuf, err := c.nativeFromBinary(newBuf)
+	value, newBuf, err := c.boundedNativeFromBinary(newBuf)
 	if err != nil {
-		return nil, buf, err // if error, return original byte slice
+		return nil, buf, c.located(err, len(c.soeHeader)) // if error, return original byte slice
 	}
 	return value, newBuf, nil
 }
@@ -410,7 +1040,7 @@ func (c *Codec) NativeFromSingle(buf []byte) (interface{}, []byte, error) {
 //         // Output: map[next:map[LongList:map[next:map[LongList:map[next:<nil>]]]]]
 //     }
 func (c *Codec) NativeFromTextual(buf []byte) (interface{}, []byte, error) {
-	value, newBuf, err := c.nativeFromTextual(buf)
+	value, newBuf, err := c.decodeTextual(buf)
 	if err != nil {
 		return nil, buf, err // if error, return original byte slice
 	}
@@ -425,7 +1055,7 @@ func (c *Codec) NativeFromTextual(buf []byte) (interface{}, []byte, error) {
 // encoded bytes appended, and a nil error value.  On error, it returns the
 // original byte slice, and the error message.
 //
-//     func ExampleSingleItemEncoding() {
+//     func ExampleCodec_SingleFromNative() {
 //         codec, err := goavro.NewCodec(`"int"`)
 //         if err != nil {
 //             fmt.Fprintf(os.Stderr, "%s\n", err)
@@ -442,7 +1072,7 @@ func (c *Codec) NativeFromTextual(buf []byte) (interface{}, []byte, error) {
 //         // Output: [195 1 143 92 57 63 26 213 117 114 6]
 //     }
 func (c *Codec) SingleFromNative(buf []byte, datum interface{}) ([]byte, error) {
-	newBuf, err := c.binaryFromNative(append(buf, c.soeHeader...), datum)
+	newBuf, err := c.encodeBinary(append(buf, c.soeHeader...), datum)
 	if err != nil {
 		return buf, err
 	}
@@ -489,13 +1119,128 @@ func (c *Codec) SingleFromNative(buf []byte, datum interface{}) ([]byte, error)
 //         // Output: {"next":{"LongList":{"next":{"LongList":{"next":null}}}}}
 //     }
 func (c *Codec) TextualFromNative(buf []byte, datum interface{}) ([]byte, error) {
-	newBuf, err := c.textualFromNative(buf, datum)
+	newBuf, err := c.encodeTextual(buf, datum)
 	if err != nil {
 		return buf, err // if error, return original byte slice
 	}
 	return newBuf, nil
 }
 
+// TextualFromNativeIndent is like TextualFromNative, but appends the Avro data
+// in indented JSON text format, in which each element of a record, array, map,
+// or union begins on a new line starting with prefix, followed by one or more
+// copies of indent according to its nesting, as done by json.Indent.
+//
+//     text, err := codec.TextualFromNativeIndent(nil, datum, "", "  ")
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Printf("%s\n", text)
+func (c *Codec) TextualFromNativeIndent(buf []byte, datum interface{}, prefix, indent string) ([]byte, error) {
+	compact, err := c.encodeTextual(nil, datum)
+	if err != nil {
+		return buf, err // if error, return original byte slice
+	}
+	bb := bytes.NewBuffer(buf)
+	if err = json.Indent(bb, compact, prefix, indent); err != nil {
+		return buf, fmt.Errorf("cannot indent textual datum: %w", err) // should not get here
+	}
+	return bb.Bytes(), nil
+}
+
+// TextualFromNativeStandard converts Go native data types to standard JSON
+// text in accordance with the Avro schema supplied when creating the Codec. It
+// differs from TextualFromNative only in how union values are encoded: rather
+// than wrapping each non-null union value in a single key JSON object naming
+// its member type, such as `{"string":"some string"}`, the bare value is
+// emitted, such as `"some string"`. Unions nested inside records, arrays, and
+// maps are likewise unwrapped, and null union values are emitted as `null`. On
+// success, it returns a new byte slice with the encoded bytes appended, and a
+// nil error value. On error, it returns the original byte slice, and the error
+// message.
+//
+//     func ExampleTextualFromNativeStandard() {
+//         codec, err := goavro.NewCodec(`["null","string","int"]`)
+//         if err != nil {
+//             fmt.Println(err)
+//         }
+//
+//         text, err := codec.TextualFromNativeStandard(nil, goavro.Union("string", "some string"))
+//         if err != nil {
+//             fmt.Println(err)
+//         }
+//
+//         fmt.Printf("%s", text)
+//         // Output: "some string"
+//     }
+func (c *Codec) TextualFromNativeStandard(buf []byte, datum interface{}) ([]byte, error) {
+	var newBuf []byte
+	var err error
+	if c.strictRecordFields {
+		err = c.checkRecordFields(datum, "textual")
+	}
+	if err == nil {
+		newBuf, err = c.textualStandard(buf, datum)
+	}
+	if err != nil {
+		return buf, err // if error, return original byte slice
+	}
+	return newBuf, nil
+}
+
+// textualStandard encodes datum as standard JSON, using the textual Avro
+// encoder for those types whose standard JSON is identical.
+func (c *Codec) textualStandard(buf []byte, datum interface{}) ([]byte, error) {
+	if c.textualStandardFromNative != nil {
+		return c.textualStandardFromNative(buf, datum)
+	}
+	return c.textualFromNative(buf, datum)
+}
+
+// nativeStandard decodes standard JSON, using the textual Avro decoder for
+// those types whose standard JSON is identical.
+func (c *Codec) nativeStandard(buf []byte) (interface{}, []byte, error) {
+	if c.nativeFromTextualStandard != nil {
+		return c.nativeFromTextualStandard(buf)
+	}
+	return c.nativeFromTextual(buf)
+}
+
+// encodeBinary encodes datum as binary, first checking the keys of its records
+// when the Codec was created using WithStrictRecordFields.
+func (c *Codec) encodeBinary(buf []byte, datum interface{}) ([]byte, error) {
+	if c.strictRecordFields {
+		if err := c.checkRecordFields(datum, "binary"); err != nil {
+			return nil, err
+		}
+	}
+	return c.binaryFromNative(buf, datum)
+}
+
+// encodeTextual encodes datum as textual Avro data, or as standard JSON when
+// the Codec was created using WithStandardJSON, first checking the keys of its
+// records when the Codec was created using WithStrictRecordFields.
+func (c *Codec) encodeTextual(buf []byte, datum interface{}) ([]byte, error) {
+	if c.strictRecordFields {
+		if err := c.checkRecordFields(datum, "textual"); err != nil {
+			return nil, err
+		}
+	}
+	if c.standardJSON {
+		return c.textualStandard(buf, datum)
+	}
+	return c.textualFromNative(buf, datum)
+}
+
+// decodeTextual decodes textual Avro data, or standard JSON when the Codec was
+// created using WithStandardJSON.
+func (c *Codec) decodeTextual(buf []byte) (interface{}, []byte, error) {
+	if c.standardJSON {
+		return c.nativeStandard(buf)
+	}
+	return c.nativeFromTextual(buf)
+}
+
 // Schema returns the original schema used to create the Codec.
 func (c *Codec) Schema() string {
 	return c.schemaOriginal
@@ -507,6 +1252,21 @@ func (c *Codec) CanonicalSchema() string {
 	return c.schemaCanonical
 }
 
+// Fingerprint returns the CRC-64-AVRO Rabin fingerprint of the Parsing
+// Canonical Form of the schema, as defined by the Avro specification. Because it
+// is computed from the canonical form, schemas which differ only in whitespace,
+// documentation, or other attributes stripped from the canonical form have the
+// same fingerprint. This is the same value as the Rabin structure Codec field.
+func (c *Codec) Fingerprint() uint64 {
+	return c.Rabin
+}
+
+// Fingerprint256 returns the SHA-256 fingerprint of the Parsing Canonical Form
+// of the schema, as defined by the Avro specification.
+func (c *Codec) Fingerprint256() [sha256.Size]byte {
+	return sha256.Sum256([]byte(c.schemaCanonical))
+}
+
 // SchemaCRC64Avro returns a signed 64-bit integer Rabin fingerprint for the
 // canonical schema.  This method returns the signed 64-bit cast of the unsigned
 // 64-bit schema Rabin fingerprint.
@@ -567,6 +1327,15 @@ func buildCodecForTypeDescribedByString(st map[string]*Codec, enclosingNamespace
 		searchType = fmt.Sprintf("%s.%s", typeName, lt)
 	}
 
+	// Avro specification allows abbreviation of type name inside a namespace,
+	// and a type in the enclosing namespace takes precedence over a type of
+	// the same name in the null namespace.
+	if enclosingNamespace != "" && !strings.ContainsRune(typeName, '.') {
+		if cd, ok := st[enclosingNamespace+"."+typeName]; ok {
+			return cd, nil
+		}
+	}
+
 	// NOTE: When codec already exists, return it. This includes both primitive and
 	// logicalType codecs added in NewCodec, and user-defined types, added while
 	// building the codec.
@@ -588,13 +1357,6 @@ func buildCodecForTypeDescribedByString(st map[string]*Codec, enclosingNamespace
 		}
 	}
 
-	// Avro specification allows abbreviation of type name inside a namespace.
-	if enclosingNamespace != "" {
-		if cd, ok := st[enclosingNamespace+"."+typeName]; ok {
-			return cd, nil
-		}
-	}
-
 	// There are only a small handful of complex Avro data types.
 	switch searchType {
 	case "array":
@@ -605,18 +1367,34 @@ func buildCodecForTypeDescribedByString(st map[string]*Codec, enclosingNamespace
 		return makeFixedCodec(st, enclosingNamespace, schemaMap)
 	case "map":
 		return makeMapCodec(st, enclosingNamespace, schemaMap, cb)
-	case "record":
+	case "record", "error":
+		// NOTE: An error is a record, which protocols use to declare the
+		// errors a message may return.
 		return makeRecordCodec(st, enclosingNamespace, schemaMap, cb)
 	case "bytes.decimal":
 		return makeDecimalBytesCodec(st, enclosingNamespace, schemaMap)
 	case "fixed.decimal":
 		return makeDecimalFixedCodec(st, enclosingNamespace, schemaMap)
+	case "fixed.duration":
+		return makeDurationFixedCodec(st, enclosingNamespace, schemaMap)
 	case "string.validated-string":
 		return makeValidatedStringCodec(st, enclosingNamespace, schemaMap)
 	default:
 		if isLogicalType {
+			// NOTE: The Avro specification requires an unknown logical type,
+			// or a known logical type annotating a type it does not support,
+			// to be ignored, and the data to be processed as its base type.
+			lt, _ := schemaMap["logicalType"].(string)
 			delete(schemaMap, "logicalType")
-			return buildCodecForTypeDescribedByString(st, enclosingNamespace, typeName, schemaMap, cb)
+			c, err := buildCodecForTypeDescribedByString(st, enclosingNamespace, typeName, schemaMap, cb)
+			if err != nil {
+				return nil, err
+			}
+			if h := registeredLogicalType(lt); h != nil {
+				definition := typeName == "enum" || typeName == "fixed" || typeName == "record" || typeName == "error"
+				return makeRegisteredLogicalTypeCodec(st, lt, c, h, definition), nil
+			}
+			return c, nil
 		}
 		return nil, fmt.Errorf("unknown type name: %q", searchType)
 	}
@@ -629,7 +1407,7 @@ func registerNewCodec(st map[string]*Codec, schemaMap map[string]interface{}, en
 	if err != nil {
 		return nil, err
 	}
-	c := &Codec{typeName: n}
+	c := &Codec{typeName: n, aliases: schemaAliases(schemaMap, n.namespace)}
 	st[n.fullName] = c
 	return c, nil
 }
@@ -648,3 +1426,54 @@ type ErrNotSingleObjectEncoded string
 func (e ErrNotSingleObjectEncoded) Error() string {
 	return "cannot decode buffer as single-object encoding: " + string(e)
 }
+
+// decodeError is returned by the binary decoders of records, arrays, maps, and
+// unions when a value they contain cannot be decoded. Each of those decoders it
+// is returned through adds where the value is located within its own value, so
+// by the time it is returned from the Codec it locates the value from the start
+// of the datum.
+type decodeError struct {
+	err    error    // message, including those of the enclosing values
+	cause  error    // error of the innermost value which could not be decoded
+	path   []string // segments locating the value, innermost first
+	offset int      // byte offset of the value
+}
+
+func (e *decodeError) Error() string { return e.err.Error() }
+
+// Unwrap returns the error of the innermost value rather than err, which itself
+// wraps the decodeError.
+func (e *decodeError) Unwrap() error { return e.cause }
+
+// newDecodeError returns the error of decoding a value which could not be
+// decoded because of err, the error of decoding a value it contains, which
+// starts offset bytes into the value, and whose location within the value is
+// described by segment, such as `.f1` for a record field, or `[3]` for an array
+// item. The error message is message.
+func newDecodeError(err, message error, segment string, offset int) error {
+	de, ok := err.(*decodeError)
+	if !ok {
+		de = &decodeError{cause: err}
+	}
+	de.err = message
+	if segment != "" {
+		de.path = append(de.path, segment)
+	}
+	de.offset += offset
+	return de
+}
+
+// located returns err, with the byte offset and path of the value which could
+// not be decoded appended to its message when known. The datum starts base
+// bytes into the buffer being decoded.
+func (c *Codec) located(err error, base int) error {
+	de, ok := err.(*decodeError)
+	if !ok {
+		return err
+	}
+	path := c.typeName.short()
+	for i := len(de.path) - 1; i >= 0; i-- {
+		path += de.path[i]
+	}
+	return fmt.Errorf("%w; offset: %d; path: %s", de.err, base+de.offset, path)
+}
@@ -11,12 +11,14 @@ package goavro
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
+	"math"
 	"os"
 	"testing"
 )
 
-func ExampleCodecCanonicalSchema() {
+func ExampleCodec_CanonicalSchema() {
 	schema := `{"type":"map","values":{"type":"enum","name":"foo","symbols":["alpha","bravo"]}}`
 	codec, err := NewCodec(schema)
 	if err != nil {
@@ -137,6 +139,99 @@ func TestCodecRabin(t *testing.T) {
 	}
 }
 
+func TestCodecFingerprint(t *testing.T) {
+	cases := []struct {
+		Schema         string
+		Fingerprint    uint64
+		Fingerprint256 string
+	}{
+		{
+			Schema:         `"null"`,
+			Fingerprint:    0x63dd24e7cc258f8a,
+			Fingerprint256: "f072cbec3bf8841871d4284230c5e983dc211a56837aed862487148f947d1a1f",
+		},
+		{
+			Schema:         `{"type":"int"}`,
+			Fingerprint:    0x7275d51a3f395c8f,
+			Fingerprint256: "3f2b87a9fe7cc9b13835598c3981cd45e3e355309e5090aa0933d7becb6fba45",
+		},
+		{
+			Schema:         ` "string" `,
+			Fingerprint:    0x8f014872634503c7,
+			Fingerprint256: "e9e5c1c9e4f6277339d1bcde0733a59bd42f8731f449da6dc13010a916930d48",
+		},
+		{
+			Schema:         `{"fields":[], "type":"record", "name":"foo", "doc":"foo", "aliases":["foo","bar"]}`,
+			Fingerprint:    0xbd0c50c84319be7e,
+			Fingerprint256: "ac35c07ecd88fe52d0a310135a01329b012c45ade36dd8b4880effb55b7f725c",
+		},
+		{
+			Schema:         `{"fields":[{"type":{"type":"boolean"}, "name":"f1"}], "type":"record", "name":"foo"}`,
+			Fingerprint:    0x6cd8eaf1c968a33b,
+			Fingerprint256: "87c762d52b47d4508fa3eaffb75040e83ce042e73d214a986319f3d58d3cc206",
+		},
+	}
+
+	for _, c := range cases {
+		codec, err := NewCodec(c.Schema)
+		if err != nil {
+			t.Fatalf("CASE: %s; cannot create code: %s", c.Schema, err)
+		}
+		if got, want := codec.Fingerprint(), c.Fingerprint; got != want {
+			t.Errorf("CASE: %s; GOT: %#x; WANT: %#x", c.Schema, got, want)
+		}
+		fp := codec.Fingerprint256()
+		if got, want := hex.EncodeToString(fp[:]), c.Fingerprint256; got != want {
+			t.Errorf("CASE: %s; GOT: %s; WANT: %s", c.Schema, got, want)
+		}
+	}
+}
+
+func TestSingleObjectDecodingErrors(t *testing.T) {
+	codec, err := NewCodec(`"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("short buffer", func(t *testing.T) {
+		buf := []byte("\xC3\x01\x8F\x5C")
+		_, newBuf, err := codec.NativeFromSingle(buf)
+		if _, ok := err.(ErrNotSingleObjectEncoded); !ok {
+			t.Fatalf("GOT: %#v; WANT: ErrNotSingleObjectEncoded", err)
+		}
+		if !bytes.Equal(newBuf, buf) {
+			t.Errorf("GOT: %v; WANT: %v", newBuf, buf)
+		}
+	})
+
+	t.Run("wrong magic", func(t *testing.T) {
+		buf := []byte("\xC3\x02\x8F\x5C\x39\x3F\x1A\xD5\x75\x72\x06")
+		_, _, err := codec.NativeFromSingle(buf)
+		if _, ok := err.(ErrNotSingleObjectEncoded); !ok {
+			t.Fatalf("GOT: %#v; WANT: ErrNotSingleObjectEncoded", err)
+		}
+		ensureError(t, err, "unknown SOE prefix")
+	})
+
+	t.Run("wrong codec", func(t *testing.T) {
+		other, err := NewCodec(`"long"`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf, err := other.SingleFromNative(nil, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, newBuf, err := codec.NativeFromSingle(buf)
+		if got, want := err, error(ErrWrongCodec(other.Rabin)); got != want {
+			t.Fatalf("GOT: %#v; WANT: %#v", got, want)
+		}
+		if !bytes.Equal(newBuf, buf) {
+			t.Errorf("GOT: %v; WANT: %v", newBuf, buf)
+		}
+	})
+}
+
 func TestSingleObjectEncoding(t *testing.T) {
 	t.Run("int", func(*testing.T) {
 		schema := `"int"`
@@ -237,7 +332,7 @@ func TestSingleObjectEncoding(t *testing.T) {
 	})
 }
 
-func ExampleSingleItemEncoding() {
+func ExampleCodec_SingleFromNative() {
 	codec, err := NewCodec(`"int"`)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err)
@@ -254,7 +349,7 @@ func ExampleSingleItemEncoding() {
 	// Output: [195 1 143 92 57 63 26 213 117 114 6]
 }
 
-func ExampleSingleItemDecoding() {
+func ExampleFingerprintFromSOE() {
 	codec1, err := NewCodec(`"int"`)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err)
@@ -323,3 +418,344 @@ func Test_buildCodecForTypeDescribedByString_CacheRespectsPrecisionScale(t *test
 		t.Errorf("GOT: %v; WANT: %v", cacheMiss.schemaOriginal, "!= "+cachedCodecIdentifier)
 	}
 }
+
+func TestCodecValid(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[
+		{"name":"f1","type":"int"},
+		{"name":"f2","type":{"type":"enum","name":"e1","symbols":["alpha","bravo"]}},
+		{"name":"f3","type":{"type":"fixed","name":"x1","size":2}},
+		{"name":"f4","type":["null","string"],"default":null}
+	]}`)
+	ensureError(t, err)
+
+	valid := map[string]interface{}{"f1": 13, "f2": "bravo", "f3": []byte("ab"), "f4": "some string"}
+	ensureError(t, codec.Valid(valid))
+
+	cases := []map[string]interface{}{
+		{"f1": int64(math.MaxInt32) + 1, "f2": "bravo", "f3": []byte("ab")},
+		{"f1": 13, "f2": "charlie", "f3": []byte("ab")},
+		{"f1": 13, "f2": "bravo", "f3": []byte("abc")},
+		{"f1": 13, "f2": "bravo", "f3": []byte("ab"), "f4": 13},
+		{"f2": "bravo", "f3": []byte("ab")},
+	}
+	for _, datum := range cases {
+		// Valid returns the same error as encoding the datum would
+		_, expected := codec.BinaryFromNative(nil, datum)
+		if expected == nil {
+			t.Fatalf("GOT: %v; WANT: error", expected)
+		}
+		ensureError(t, codec.Valid(datum), expected.Error())
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { _ = codec.Valid(valid) }); allocs != 0 {
+		t.Errorf("GOT: %v allocations; WANT: 0", allocs)
+	}
+}
+
+func TestCodecNativeFromNative(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[
+		{"name":"f1","type":"int"},
+		{"name":"f2","type":"long"},
+		{"name":"f3","type":["null","string","int"],"default":null},
+		{"name":"f4","type":{"type":"array","items":"float"},"default":[]}
+	]}`)
+	ensureError(t, err)
+
+	// as decoded by encoding/json
+	datum := map[string]interface{}{"f1": float64(3), "f2": float64(13), "f3": "x"}
+	native, err := codec.NativeFromNative(datum)
+	ensureError(t, err)
+	record := native.(map[string]interface{})
+	if actual, expected := record["f1"], int32(3); actual != expected {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+	}
+	if actual, expected := record["f2"], int64(13); actual != expected {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+	}
+	if actual, expected := fmt.Sprintf("%v", record["f3"]), "map[string:x]"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := fmt.Sprintf("%#v", record["f4"]), "[]interface {}{}"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	// the normalized value encodes like the original one
+	expected, err := codec.BinaryFromNative(nil, datum)
+	ensureError(t, err)
+	actual, err := codec.BinaryFromNative(nil, native)
+	ensureError(t, err)
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+	}
+
+	_, err = codec.NativeFromNative(map[string]interface{}{"f1": float64(3)})
+	ensureError(t, err, "field \"f2\"")
+	_, err = codec.NativeFromNative(map[string]interface{}{"f1": 3.5, "f2": 13})
+	ensureError(t, err, "field \"f1\"")
+}
+
+func TestCodecNativeFromBinaryComplete(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":"int"},{"name":"f2","type":"string"}]}`)
+	ensureError(t, err)
+	buf, err := codec.BinaryFromNative(nil, map[string]interface{}{"f1": 3, "f2": "xy"})
+	ensureError(t, err)
+
+	native, err := codec.NativeFromBinaryComplete(buf)
+	ensureError(t, err)
+	if actual, expected := fmt.Sprintf("%v", native), "map[f1:3 f2:xy]"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	native, err = codec.NativeFromBinaryComplete(buf[:len(buf)-1])
+	ensureError(t, err, `cannot decode binary record "r1" field "f2"`)
+	if native != nil {
+		t.Errorf("GOT: %v; WANT: %v", native, nil)
+	}
+
+	native, err = codec.NativeFromBinaryComplete(append(buf, 0x00, 0x02))
+	ensureError(t, err, `cannot decode binary "r1": 2 bytes ought not to remain after value; offset: 4`)
+	if native != nil {
+		t.Errorf("GOT: %v; WANT: %v", native, nil)
+	}
+}
+
+func TestCodecNativeFromBinaryInto(t *testing.T) {
+	const schema = `{"type":"record","name":"r1","fields":[{"name":"f1","type":"int"},{"name":"f2","type":"string"}]}`
+	codec, err := NewCodec(schema)
+	ensureError(t, err)
+
+	dst := map[string]interface{}{"stale": true}
+	for _, datum := range []map[string]interface{}{{"f1": 3, "f2": "x"}, {"f1": 13, "f2": "yz"}} {
+		buf, err := codec.BinaryFromNative(nil, datum)
+		ensureError(t, err)
+		remaining, err := codec.NativeFromBinaryInto(append(buf, 0xff), dst)
+		ensureError(t, err)
+		if actual, expected := string(remaining), "\xff"; actual != expected {
+			t.Errorf("GOT: %q; WANT: %q", actual, expected)
+		}
+		if actual, expected := fmt.Sprintf("%v", dst), fmt.Sprintf("map[f1:%v f2:%v]", datum["f1"], datum["f2"]); actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	}
+
+	buf := []byte{0x06, 0x04, 'x'}
+	remaining, err := codec.NativeFromBinaryInto(buf, dst)
+	ensureError(t, err, `cannot decode binary record "r1" field "f2"`, "offset: 1; path: r1.f2")
+	if !bytes.Equal(remaining, buf) {
+		t.Errorf("GOT: %#v; WANT: %#v", remaining, buf)
+	}
+	_, err = codec.NativeFromBinaryInto([]byte{0x06, 0x02, 'x'}, nil)
+	ensureError(t, err, `cannot decode binary record "r1" into nil map`)
+
+	primitive, err := NewCodec(`"int"`)
+	ensureError(t, err)
+	_, err = primitive.NativeFromBinaryInto([]byte{0x06}, dst)
+	ensureError(t, err, `cannot decode binary "int" into map: schema ought to be a record`)
+
+	// codecs resolving a writer schema fill in reader fields
+	reader, err := NewCodecForReaderWriter(`{"type":"record","name":"r1","fields":[{"name":"f1","type":"long"},{"name":"f3","type":"int","default":7}]}`, schema)
+	ensureError(t, err)
+	_, err = reader.NativeFromBinaryInto([]byte{0x06, 0x02, 'x'}, dst)
+	ensureError(t, err)
+	if actual, expected := fmt.Sprintf("%v", dst), "map[f1:3 f3:7]"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	// including those decoding records as Record values
+	ordered, err := NewCodecForReaderWriter(schema, schema)
+	ensureError(t, err)
+	ordered, err = ordered.With(WithOrderedRecords())
+	ensureError(t, err)
+	_, err = ordered.NativeFromBinaryInto([]byte{0x06, 0x02, 'x'}, dst)
+	ensureError(t, err)
+	if actual, expected := fmt.Sprintf("%v", dst), "map[f1:3 f2:x]"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
+
+func TestCodecBinaryFromNativePooled(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":"int"},{"name":"f2","type":"string"}]}`)
+	ensureError(t, err)
+
+	first := map[string]interface{}{"f1": 3, "f2": "some string"}
+	expected, err := codec.BinaryFromNative(nil, first)
+	ensureError(t, err)
+	actual, err := codec.BinaryFromNativePooled(first)
+	ensureError(t, err)
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+	}
+
+	// a buffer which is not released is not overwritten by later encodings
+	second := map[string]interface{}{"f1": 13, "f2": "other string"}
+	other, err := codec.BinaryFromNativePooled(second)
+	ensureError(t, err)
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+	}
+	codec.Release(actual)
+	codec.Release(other)
+
+	buf, err := codec.BinaryFromNativePooled(map[string]interface{}{"f1": 3})
+	ensureError(t, err, `field "f2"`)
+	if buf != nil {
+		t.Errorf("GOT: %#v; WANT: nil", buf)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		buf, _ := codec.BinaryFromNativePooled(first)
+		codec.Release(buf)
+	}); allocs != 0 {
+		t.Errorf("GOT: %v allocations; WANT: 0", allocs)
+	}
+}
+
+func TestCodecWith(t *testing.T) {
+	base, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":{"type":"array","items":"int"}},{"name":"u","type":["null","string","int"]}]}`)
+	ensureError(t, err)
+	datum := map[string]interface{}{"a": []interface{}{1, 2}, "u": Union("string", "x"), "extra": true}
+	buf, err := base.BinaryFromNative(nil, datum)
+	ensureError(t, err)
+
+	// options of the Codec itself share the codecs within the schema
+	strictStruct, err := base.With(WithStrictStructFields(true))
+	ensureError(t, err)
+	if !strictStruct.strictStructFields || base.strictStructFields {
+		t.Errorf("GOT: %v, %v; WANT: true, false", strictStruct.strictStructFields, base.strictStructFields)
+	}
+	if strictStruct.recordFields[0] != base.recordFields[0] {
+		t.Errorf("GOT: %p; WANT: %p", strictStruct.recordFields[0], base.recordFields[0])
+	}
+
+	strict, err := base.With(WithStrictRecordFields(true))
+	ensureError(t, err)
+	if strict.recordFields[0] != base.recordFields[0] {
+		t.Errorf("GOT: %p; WANT: %p", strict.recordFields[0], base.recordFields[0])
+	}
+	_, err = strict.BinaryFromNative(nil, datum)
+	ensureError(t, err, `fields ought to be defined in schema: ["extra"]`)
+	_, err = strict.TextualFromNative(nil, datum)
+	ensureError(t, err, `cannot encode textual record "r1": fields ought to be defined in schema: ["extra"]`)
+
+	// options accumulate, and the original Codec is unchanged
+	limited, err := strict.With(WithMaxItemCount(1))
+	ensureError(t, err)
+	_, _, err = limited.NativeFromBinary(buf)
+	ensureError(t, err, "item count exceeds limit: 2 > 1")
+	_, err = limited.BinaryFromNative(nil, datum)
+	ensureError(t, err, `fields ought to be defined in schema: ["extra"]`)
+	_, _, err = strict.NativeFromBinary(buf)
+	ensureError(t, err)
+	_, err = base.BinaryFromNative(nil, datum)
+	ensureError(t, err)
+
+	standard, err := base.With(WithStandardJSON(true))
+	ensureError(t, err)
+	if standard.recordFields[1] != base.recordFields[1] {
+		t.Errorf("GOT: %p; WANT: %p", standard.recordFields[1], base.recordFields[1])
+	}
+	native, _, err := standard.NativeFromTextual([]byte(`{"a":[1],"u":"x"}`))
+	ensureError(t, err)
+	actual, err := standard.BinaryFromNative(nil, native)
+	ensureError(t, err)
+	expected, err := base.BinaryFromNative(nil, map[string]interface{}{"a": []interface{}{1}, "u": Union("string", "x")})
+	ensureError(t, err)
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+	}
+	text, err := standard.TextualFromNative(nil, native)
+	ensureError(t, err)
+	if actual, expected := string(text), `{"a":[1],"u":"x"}`; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	_, _, err = base.NativeFromTextual([]byte(`{"a":[1],"u":"x"}`))
+	ensureError(t, err, "cannot decode textual union")
+
+	// options of the Codec itself may also be turned off
+	lenient, err := strict.With(WithStrictRecordFields(false))
+	ensureError(t, err)
+	_, err = lenient.BinaryFromNative(nil, datum)
+	ensureError(t, err)
+	avro, err := standard.With(WithStandardJSON(false))
+	ensureError(t, err)
+	_, _, err = avro.NativeFromTextual([]byte(`{"a":[1],"u":{"string":"x"}}`))
+	ensureError(t, err)
+	_, _, err = avro.NativeFromTextual([]byte(`{"a":[1],"u":"x"}`))
+	ensureError(t, err, "cannot decode textual union")
+
+	// a Codec resolving a writer schema keeps doing so
+	resolved, err := NewCodecForReaderWriter(`{"type":"array","items":"long"}`, `{"type":"array","items":"int"}`)
+	ensureError(t, err)
+	resolvedLimited, err := resolved.With(WithMaxItemCount(1))
+	ensureError(t, err)
+	native, _, err = resolvedLimited.NativeFromBinary([]byte{0x02, 0x06, 0x00})
+	ensureError(t, err)
+	if actual, expected := fmt.Sprintf("%#v", native), "[]interface {}{3}"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	_, _, err = resolvedLimited.NativeFromBinary([]byte{0x04, 0x06, 0x08, 0x00})
+	ensureError(t, err, "item count exceeds limit: 2 > 1")
+}
+
+func TestCodecMaxDecodeDepth(t *testing.T) {
+	// LongList values nested n deep: each union index 1 is another LongList,
+	// and the final 0 is the null ending the list
+	longList := func(n int) []byte {
+		return append(bytes.Repeat([]byte{2}, n), 0)
+	}
+
+	codec, err := NewCodecWithOptions(testSafeLinkedList, WithMaxDecodeDepth(10))
+	ensureError(t, err)
+	// each LongList is a record and a union, so 4 nested lists are 10 deep
+	_, _, err = codec.NativeFromBinary(longList(4))
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinary(longList(5))
+	ensureError(t, err, "nesting depth exceeds limit: 10")
+	_, err = codec.NativeFromBinaryInto(longList(5), make(map[string]interface{}))
+	ensureError(t, err, "nesting depth exceeds limit: 10")
+	_, _, err = codec.NativeFromSingle(append(append([]byte(nil), codec.soeHeader...), longList(5)...))
+	ensureError(t, err, "nesting depth exceeds limit: 10")
+	_, err = codec.NewBinaryReader(bytes.NewReader(longList(5))).Next()
+	ensureError(t, err, "nesting depth exceeds limit: 10")
+
+	// the limit ought to apply to the value within any enclosing type
+	codec, err = NewCodecWithOptions(`{"type":"array","items":`+testSafeLinkedList+`}`, WithMaxDecodeDepth(10))
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinary(append(append([]byte{2}, longList(4)...), 0))
+	ensureError(t, err, "nesting depth exceeds limit: 10")
+
+	// the default limit ought to stop deeply nested values before exhausting
+	// the stack, while allowing values nested less deeply
+	codec, err = NewCodec(testSafeLinkedList)
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinary(longList(DefaultMaxDecodeDepth/2 - 1))
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinary(longList(10000000))
+	ensureError(t, err, fmt.Sprintf("nesting depth exceeds limit: %d", DefaultMaxDecodeDepth))
+
+	relaxed, err := codec.With(WithMaxDecodeDepth(2 * DefaultMaxDecodeDepth))
+	ensureError(t, err)
+	_, _, err = relaxed.NativeFromBinary(longList(DefaultMaxDecodeDepth - 1))
+	ensureError(t, err)
+}
+
+func TestCodecRecursive(t *testing.T) {
+	cases := []struct {
+		schema    string
+		recursive bool
+	}{
+		{`"long"`, false},
+		{`{"type":"array","items":{"type":"map","values":["null","string"]}}`, false},
+		{`{"type":"record","name":"r1","fields":[{"name":"a","type":{"type":"fixed","name":"f1","size":1}},{"name":"b","type":"f1"}]}`, false},
+		{testSafeLinkedList, true},
+		{`{"type":"record","name":"Tree","fields":[{"name":"children","type":{"type":"array","items":"Tree"}}]}`, true},
+		{`{"type":"record","name":"A","fields":[{"name":"b","type":{"type":"record","name":"B","fields":[{"name":"a","type":["null","A"]}]}}]}`, true},
+	}
+	for _, c := range cases {
+		codec, err := NewCodec(c.schema)
+		ensureError(t, err)
+		if actual, expected := codec.recursive, c.recursive; actual != expected {
+			t.Errorf("%s: GOT: %v; WANT: %v", c.schema, actual, expected)
+		}
+	}
+}
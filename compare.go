@@ -0,0 +1,314 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// CompareBinary compares two binary values of the schema of the Codec using the
+// sort order of the Avro specification, without decoding them into native
+// values. It returns a negative number when a sorts before b, zero when they
+// sort the same, and a positive number when a sorts after b.
+//
+// Numbers compare by value, booleans sort false before true, bytes, fixed, and
+// strings compare their bytes, enums compare the ordinal of their symbols, and
+// arrays compare item by item, with the shorter sorting first when it is a
+// prefix of the other. Unions sort by the index of their member before its
+// value. Records compare field by field in schema order, honoring the order
+// attribute of each field: a descending field reverses its comparison, and an
+// ignored field is skipped. Values of logical types compare as their
+// underlying types. An error is returned for data which cannot be decoded, for
+// maps, which the specification does not allow to be compared, and for a
+// Codec created by NewCodecForReaderWriter.
+//
+//     codec, err := goavro.NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":"int","order":"descending"}]}`)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     result, err := codec.CompareBinary([]byte{0x02}, []byte{0x04})
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Println(result) // 1
+func (c *Codec) CompareBinary(a, b []byte) (int, error) {
+	if c.writerSchema != "" {
+		return 0, fmt.Errorf("cannot compare binary data written using a different schema than the reader schema")
+	}
+	bc := &binaryComparer{maxDepth: c.maxDecodeDepth()}
+	result, _, _, err := bc.compare(c, a, b)
+	if err != nil {
+		return 0, fmt.Errorf("cannot compare binary: %w", err)
+	}
+	return result, nil
+}
+
+// Compare compares two native values of the schema of the Codec using the sort
+// order of the Avro specification, as CompareBinary does for binary values. It
+// returns a negative number when a sorts before b, zero when they sort the
+// same, and a positive number when a sorts after b. Values are accepted in any
+// native form the Codec encodes, so enums sort by the ordinal of their symbols
+// rather than alphabetically, union values sort by the index of their member,
+// and record fields honor their order attribute. An error is returned when
+// either value cannot be encoded using the Codec, or the values cannot be
+// compared, such as maps.
+//
+//     sort.Slice(records, func(i, j int) bool {
+//         result, err := codec.Compare(records[i], records[j])
+//         if err != nil {
+//             panic(err)
+//         }
+//         return result < 0
+//     })
+func (c *Codec) Compare(a, b interface{}) (int, error) {
+	bufA, err := c.binaryFromNative(nil, a)
+	if err != nil {
+		return 0, fmt.Errorf("cannot compare: %w", err)
+	}
+	bufB, err := c.binaryFromNative(nil, b)
+	if err != nil {
+		return 0, fmt.Errorf("cannot compare: %w", err)
+	}
+	// NOTE: Values encoded by a Codec created by NewCodecForReaderWriter use
+	// the reader schema, so are compared as such.
+	bc := &binaryComparer{maxDepth: c.maxDecodeDepth()}
+	result, _, _, err := bc.compare(c, bufA, bufB)
+	if err != nil {
+		return 0, fmt.Errorf("cannot compare: %w", err)
+	}
+	return result, nil
+}
+
+// binaryComparer compares binary values while limiting the depth of the values
+// of recursive schemas.
+type binaryComparer struct {
+	maxDepth int
+	depth    int
+}
+
+// compare compares the binary values of c at the start of a and b, returning
+// the bytes remaining after them once they compare the same.
+func (bc *binaryComparer) compare(c *Codec, a, b []byte) (int, []byte, []byte, error) {
+	if bc.depth >= bc.maxDepth {
+		return 0, nil, nil, fmt.Errorf("nesting depth exceeds limit: %d", bc.maxDepth)
+	}
+	bc.depth++
+	defer func() { bc.depth-- }()
+
+	if cr := c.unionInfo; cr != nil {
+		indexA, a, err := unionIndexFromBinary(cr, a)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		indexB, b, err := unionIndexFromBinary(cr, b)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		if indexA != indexB {
+			return compareInt64(indexA, indexB), nil, nil, nil
+		}
+		return bc.compare(cr.codecFromIndex[indexA], a, b)
+	}
+
+	switch baseType := c.baseType(); baseType {
+	case "null":
+		return 0, a, b, nil
+	case "boolean":
+		return comparePrimitives(booleanNativeFromBinary, a, b, func(x, y interface{}) int {
+			return boolToInt(x.(bool)) - boolToInt(y.(bool))
+		})
+	case "int":
+		return comparePrimitives(intNativeFromBinary, a, b, func(x, y interface{}) int {
+			return compareInt64(int64(x.(int32)), int64(y.(int32)))
+		})
+	case "long", "enum":
+		// NOTE: Enums are encoded as the ordinal of their symbol.
+		return comparePrimitives(longNativeFromBinary, a, b, func(x, y interface{}) int {
+			return compareInt64(x.(int64), y.(int64))
+		})
+	case "float":
+		return comparePrimitives(floatNativeFromBinary, a, b, func(x, y interface{}) int {
+			return compareFloat64(float64(x.(float32)), float64(y.(float32)))
+		})
+	case "double":
+		return comparePrimitives(doubleNativeFromBinary, a, b, func(x, y interface{}) int {
+			return compareFloat64(x.(float64), y.(float64))
+		})
+	case "bytes", "string":
+		return comparePrimitives(bytesNativeFromBinary, a, b, func(x, y interface{}) int {
+			return bytes.Compare(x.([]byte), y.([]byte))
+		})
+	case "fixed":
+		size := int(c.fixedSize)
+		if len(a) < size || len(b) < size {
+			return 0, nil, nil, fmt.Errorf("cannot decode binary fixed %q: %w", c.typeName, ErrShortBuffer{})
+		}
+		return bytes.Compare(a[:size], b[:size]), a[size:], b[size:], nil
+	case "array":
+		return bc.compareArrays(c, a, b)
+	case "record":
+		return bc.compareRecords(c, a, b)
+	default:
+		return 0, nil, nil, fmt.Errorf("cannot compare values of %s %q", baseType, c.typeName)
+	}
+}
+
+func (bc *binaryComparer) compareArrays(c *Codec, a, b []byte) (int, []byte, []byte, error) {
+	itemsA := &binaryBlockItems{buf: a, limits: c.decodeLimits}
+	itemsB := &binaryBlockItems{buf: b, limits: c.decodeLimits}
+	for {
+		moreA, err := itemsA.next()
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		moreB, err := itemsB.next()
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		if !moreA || !moreB {
+			return boolToInt(moreA) - boolToInt(moreB), itemsA.buf, itemsB.buf, nil
+		}
+		result, newA, newB, err := bc.compare(c.itemCodec, itemsA.buf, itemsB.buf)
+		if err != nil || result != 0 {
+			return result, nil, nil, err
+		}
+		itemsA.buf, itemsB.buf = newA, newB
+	}
+}
+
+func (bc *binaryComparer) compareRecords(c *Codec, a, b []byte) (int, []byte, []byte, error) {
+	for _, field := range c.recordFields {
+		if field.order == "ignore" {
+			var err error
+			if _, a, err = field.codec.nativeFromBinary(a); err != nil {
+				return 0, nil, nil, err
+			}
+			if _, b, err = field.codec.nativeFromBinary(b); err != nil {
+				return 0, nil, nil, err
+			}
+			continue
+		}
+		result, newA, newB, err := bc.compare(field.codec, a, b)
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("record %q field %q: %w", c.typeName, field.name, err)
+		}
+		if result != 0 {
+			if field.order == "descending" {
+				result = -result
+			}
+			return result, nil, nil, nil
+		}
+		a, b = newA, newB
+	}
+	return 0, a, b, nil
+}
+
+// binaryBlockItems iterates over the items of a binary array, however they are
+// split into blocks.
+type binaryBlockItems struct {
+	buf       []byte
+	limits    decodeLimits
+	remaining int64 // items remaining in the current block
+}
+
+// next reads the block header when the current block has no items remaining,
+// and returns false once the array ends.
+func (bi *binaryBlockItems) next() (bool, error) {
+	for bi.remaining == 0 {
+		value, buf, err := longNativeFromBinary(bi.buf)
+		if err != nil {
+			return false, fmt.Errorf("cannot decode binary array block count: %w", err)
+		}
+		count := value.(int64) // longDecoder always returns int64, so elide error checking
+		if count == 0 {
+			bi.buf = buf
+			return false, nil
+		}
+		if count < 0 {
+			if count == math.MinInt64 {
+				return false, fmt.Errorf("cannot decode binary array with block count: %d", count)
+			}
+			count = -count
+			// NOTE: The block size is only useful for skipping the block.
+			if _, buf, err = longNativeFromBinary(buf); err != nil {
+				return false, fmt.Errorf("cannot decode binary array block size: %w", err)
+			}
+		}
+		if maxBlockCount := bi.limits.blockCount(); count > maxBlockCount {
+			return false, fmt.Errorf("cannot decode binary array when block count exceeds MaxBlockCount: %d > %d", count, maxBlockCount)
+		}
+		bi.buf, bi.remaining = buf, count
+	}
+	bi.remaining--
+	return true, nil
+}
+
+// unionIndexFromBinary decodes the index of the member of a binary union.
+func unionIndexFromBinary(cr *codecInfo, buf []byte) (int64, []byte, error) {
+	value, buf, err := longNativeFromBinary(buf)
+	if err != nil {
+		return 0, nil, err
+	}
+	index := value.(int64) // longDecoder always returns int64, so elide error checking
+	if index < 0 || index >= int64(len(cr.codecFromIndex)) {
+		return 0, nil, fmt.Errorf("cannot decode binary union: index ought to be between 0 and %d; read index: %d", len(cr.codecFromIndex)-1, index)
+	}
+	return index, buf, nil
+}
+
+// comparePrimitives decodes a primitive value from both a and b, and compares
+// them using compare.
+func comparePrimitives(nativeFromBinary func([]byte) (interface{}, []byte, error), a, b []byte, compare func(x, y interface{}) int) (int, []byte, []byte, error) {
+	x, a, err := nativeFromBinary(a)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	y, b, err := nativeFromBinary(b)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return compare(x, y), a, b, nil
+}
+
+func compareInt64(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// compareFloat64 compares floating point numbers like other Avro
+// implementations do, sorting negative zero before positive zero, and NaN after
+// every other number, and the same as itself.
+func compareFloat64(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	nanX, nanY := math.IsNaN(x), math.IsNaN(y)
+	if nanX || nanY {
+		return boolToInt(nanX) - boolToInt(nanY)
+	}
+	return boolToInt(math.Signbit(y)) - boolToInt(math.Signbit(x))
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
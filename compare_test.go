@@ -0,0 +1,191 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"math"
+	"testing"
+)
+
+// testCompareBinary encodes a and b using schema, and ensures they compare as
+// expected, in both directions.
+func testCompareBinary(t *testing.T, schema string, a, b interface{}, expected int) {
+	t.Helper()
+	codec, err := NewCodec(schema)
+	ensureError(t, err)
+	bufA, err := codec.BinaryFromNative(nil, a)
+	ensureError(t, err)
+	bufB, err := codec.BinaryFromNative(nil, b)
+	ensureError(t, err)
+	for _, c := range []struct {
+		x, y     []byte
+		expected int
+	}{{bufA, bufB, expected}, {bufB, bufA, -expected}} {
+		actual, err := codec.CompareBinary(c.x, c.y)
+		ensureError(t, err)
+		if sign(actual) != c.expected {
+			t.Errorf("schema: %s; %v <=> %v; GOT: %v; WANT: %v", schema, a, b, actual, c.expected)
+		}
+	}
+}
+
+func sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
+
+func TestCompareBinaryPrimitives(t *testing.T) {
+	testCompareBinary(t, `"null"`, nil, nil, 0)
+	testCompareBinary(t, `"boolean"`, false, true, -1)
+	testCompareBinary(t, `"int"`, -3, 2, -1)
+	testCompareBinary(t, `"long"`, int64(math.MaxInt64), int64(math.MinInt64), 1)
+	testCompareBinary(t, `"float"`, float32(-1.5), float32(1.5), -1)
+	testCompareBinary(t, `"double"`, math.Copysign(0, -1), 0.0, -1)
+	testCompareBinary(t, `"double"`, math.NaN(), math.Inf(1), 1)
+	testCompareBinary(t, `"double"`, math.NaN(), math.NaN(), 0)
+	testCompareBinary(t, `"string"`, "ab", "b", -1)
+	testCompareBinary(t, `"string"`, "a", "ab", -1)
+	testCompareBinary(t, `"string"`, "é", "z", 1)
+	testCompareBinary(t, `"bytes"`, []byte{0xff}, []byte{0x01, 0x02}, 1)
+	testCompareBinary(t, `{"type":"fixed","name":"f1","size":2}`, []byte("ab"), []byte("ab"), 0)
+	// enums compare by ordinal rather than by symbol
+	testCompareBinary(t, `{"type":"enum","name":"e1","symbols":["z","a"]}`, "z", "a", -1)
+	// logical types compare as their underlying type
+	testCompareBinary(t, `{"type":"int","logicalType":"date"}`, 3, 200, -1)
+}
+
+func TestCompareBinaryArraysAndUnions(t *testing.T) {
+	testCompareBinary(t, `{"type":"array","items":"int"}`, []interface{}{1, 2}, []interface{}{1, 2}, 0)
+	testCompareBinary(t, `{"type":"array","items":"int"}`, []interface{}{1, 2}, []interface{}{1, 3}, -1)
+	testCompareBinary(t, `{"type":"array","items":"int"}`, []interface{}{1}, []interface{}{1, 0}, -1)
+	testCompareBinary(t, `{"type":"array","items":"int"}`, []interface{}{}, []interface{}{-1}, -1)
+
+	// unions compare by member index before value
+	testCompareBinary(t, `["null","int","string"]`, nil, Union("int", 3), -1)
+	testCompareBinary(t, `["null","int","string"]`, Union("string", "a"), Union("int", 3), 1)
+	testCompareBinary(t, `["null","int","string"]`, Union("int", 4), Union("int", 3), 1)
+
+	// arrays compare the same however their items are split into blocks
+	codec, err := NewCodec(`{"type":"array","items":"int"}`)
+	ensureError(t, err)
+	oneBlock := []byte{0x06, 0x02, 0x04, 0x06, 0x00}
+	twoBlocks := []byte{0x02, 0x02, 0x03, 0x04, 0x04, 0x06, 0x00} // second block has negative count and size
+	actual, err := codec.CompareBinary(oneBlock, twoBlocks)
+	ensureError(t, err)
+	if actual != 0 {
+		t.Errorf("GOT: %v; WANT: %v", actual, 0)
+	}
+}
+
+func TestCompareBinaryRecordOrder(t *testing.T) {
+	schema := `{"type":"record","name":"r1","fields":[
+		{"name":"skipped","type":"string","order":"ignore"},
+		{"name":"up","type":"int","order":"ascending"},
+		{"name":"down","type":"long","order":"descending"},
+		{"name":"last","type":"int"}
+	]}`
+	record := func(skipped string, up, down, last int) map[string]interface{} {
+		return map[string]interface{}{"skipped": skipped, "up": up, "down": int64(down), "last": last}
+	}
+
+	testCompareBinary(t, schema, record("a", 1, 1, 1), record("zzz", 1, 1, 1), 0)
+	testCompareBinary(t, schema, record("a", 1, 9, 9), record("a", 2, 0, 0), -1)
+	testCompareBinary(t, schema, record("a", 1, 5, 0), record("a", 1, 4, 9), -1)
+	testCompareBinary(t, schema, record("a", 1, 4, 0), record("a", 1, 4, 9), -1)
+
+	// fields of nested records use their own order
+	testCompareBinary(t, `{"type":"record","name":"outer","fields":[{"name":"inner","type":`+schema+`,"order":"descending"}]}`,
+		map[string]interface{}{"inner": record("a", 1, 1, 1)},
+		map[string]interface{}{"inner": record("a", 2, 1, 1)}, 1)
+}
+
+func TestCompareBinaryFail(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":"int"},{"name":"b","type":{"type":"map","values":"int"}}]}`)
+	ensureError(t, err)
+	_, err = codec.CompareBinary([]byte{0x02, 0x00}, []byte{0x02, 0x00})
+	ensureError(t, err, `cannot compare binary: record "r1" field "b": cannot compare values of map`)
+	// a difference before the map does not require comparing the map
+	actual, err := codec.CompareBinary([]byte{0x02, 0x00}, []byte{0x04, 0x00})
+	ensureError(t, err)
+	if actual >= 0 {
+		t.Errorf("GOT: %v; WANT: negative", actual)
+	}
+
+	codec, err = NewCodec(`"string"`)
+	ensureError(t, err)
+	_, err = codec.CompareBinary([]byte{0x04, 'a'}, []byte{0x02, 'a'})
+	ensureError(t, err, "cannot compare binary: cannot decode binary bytes")
+
+	_, err = NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":"int","order":"sideways"}]}`)
+	ensureError(t, err, `Record "r1" field "a": order ought to be ascending, descending, or ignore: sideways`)
+
+	// the order is matched regardless of case
+	codec, err = NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":"int","order":"DESCENDING"}]}`)
+	ensureError(t, err)
+	actual, err = codec.CompareBinary([]byte{0x02}, []byte{0x04})
+	ensureError(t, err)
+	if actual <= 0 {
+		t.Errorf("GOT: %v; WANT: positive", actual)
+	}
+
+	reader, err := NewCodecForReaderWriter(`"long"`, `"int"`)
+	ensureError(t, err)
+	_, err = reader.CompareBinary([]byte{0x02}, []byte{0x02})
+	ensureError(t, err, "cannot compare binary data written using a different schema")
+}
+
+func TestCompare(t *testing.T) {
+	for _, c := range []struct {
+		schema   string
+		a, b     interface{}
+		expected int
+	}{
+		{`"boolean"`, true, false, 1},
+		{`"int"`, int8(-1), int64(0), -1},
+		{`"long"`, 3, 3.0, 0},
+		{`"double"`, float32(1.5), 1.25, 1},
+		{`"string"`, "apple", "banana", -1},
+		{`"bytes"`, []byte("b"), "a", 1},
+		// enums sort by ordinal rather than alphabetically
+		{`{"type":"enum","name":"e1","symbols":["low","high"]}`, "high", "low", 1},
+		{`{"type":"enum","name":"e1","symbols":["low","high"]}`, Enum{Symbol: "low", Ordinal: 0}, "low", 0},
+		{`["null","string"]`, nil, "x", -1},
+		{`{"type":"array","items":"int"}`, []int{1, 2}, []interface{}{1, 2, 0}, -1},
+		{`{"type":"record","name":"r1","fields":[{"name":"a","type":"int","order":"descending"},{"name":"b","type":"string"}]}`,
+			map[string]interface{}{"a": 1, "b": "x"}, map[string]interface{}{"a": 2, "b": "a"}, 1},
+		{`{"type":"record","name":"r1","fields":[{"name":"a","type":"int","order":"ignore"},{"name":"b","type":"string"}]}`,
+			map[string]interface{}{"a": 1, "b": "x"}, map[string]interface{}{"a": 2, "b": "x"}, 0},
+	} {
+		codec, err := NewCodec(c.schema)
+		ensureError(t, err)
+		actual, err := codec.Compare(c.a, c.b)
+		ensureError(t, err)
+		if sign(actual) != c.expected {
+			t.Errorf("schema: %s; %v <=> %v; GOT: %v; WANT: %v", c.schema, c.a, c.b, actual, c.expected)
+		}
+	}
+}
+
+func TestCompareFail(t *testing.T) {
+	codec, err := NewCodec(`{"type":"enum","name":"e1","symbols":["low","high"]}`)
+	ensureError(t, err)
+	_, err = codec.Compare("low", "medium")
+	ensureError(t, err, "cannot compare: cannot encode binary enum")
+
+	codec, err = NewCodec(`{"type":"map","values":"int"}`)
+	ensureError(t, err)
+	_, err = codec.Compare(map[string]interface{}{}, map[string]interface{}{})
+	ensureError(t, err, "cannot compare: cannot compare values of map")
+}
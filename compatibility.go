@@ -0,0 +1,145 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"fmt"
+)
+
+// Compatible returns true when data encoded using the writer schema can always
+// be read using the reader schema, applying the same Avro schema resolution
+// rules as NewCodecForReaderWriter. When the schemas are not compatible, it
+// also returns the reasons why, each prefixed by where in the reader schema it
+// was found.
+//
+// Unlike NewCodecForReaderWriter, which only returns an error involving a
+// writer union member or enum symbol when data using it is decoded, Compatible
+// reports every writer union member and enum symbol the reader cannot read.
+//
+// A new schema is backward compatible with an old schema when
+// Compatible(newSchema, oldSchema) returns true, and forward compatible when
+// Compatible(oldSchema, newSchema) returns true. An error is returned when
+// either schema is invalid.
+//
+//     ok, reasons, err := goavro.Compatible(
+//         `{"type":"record","name":"r","fields":[{"name":"a","type":"long"},{"name":"b","type":"string"}]}`,
+//         `{"type":"record","name":"r","fields":[{"name":"a","type":"int"}]}`)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Println(ok, reasons)
+//     // Output: false [record "r" field "b": reader field is missing from writer and has no default value]
+func Compatible(readerSchema, writerSchema string) (bool, []string, error) {
+	reader, err := NewCodec(readerSchema)
+	if err != nil {
+		return false, nil, fmt.Errorf("cannot create reader codec: %w", err)
+	}
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		return false, nil, fmt.Errorf("cannot create writer codec: %w", err)
+	}
+	cc := &compatibilityChecker{records: make(map[[2]*Codec]struct{})}
+	cc.check(reader, writer, "")
+	return len(cc.reasons) == 0, cc.reasons, nil
+}
+
+// compatibilityChecker collects the reasons a writer schema cannot be resolved
+// with a reader schema.
+type compatibilityChecker struct {
+	// records holds the record pairs already checked, so recursive schemas are
+	// checked once rather than without end.
+	records map[[2]*Codec]struct{}
+
+	reasons []string
+}
+
+func (cc *compatibilityChecker) addReason(prefix, format string, a ...interface{}) {
+	cc.reasons = append(cc.reasons, prefix+fmt.Sprintf(format, a...))
+}
+
+func (cc *compatibilityChecker) check(reader, writer *Codec, prefix string) {
+	if writer.unionInfo != nil {
+		// every writer union member may be written, so each one must be
+		// readable
+		for i, memberCodec := range writer.unionInfo.codecFromIndex {
+			cc.check(reader, memberCodec, fmt.Sprintf("%swriter union item %d: ", prefix, i+1))
+		}
+		return
+	}
+	if reader.unionInfo != nil {
+		cr := reader.unionInfo
+		index := readerUnionIndex(cr, writer)
+		if index == -1 {
+			cc.addReason(prefix, "writer type %q does not match any reader union member: %v", writer.typeName, cr.allowedTypes)
+			return
+		}
+		cc.check(cr.codecFromIndex[index], writer, fmt.Sprintf("%sreader union item %d: ", prefix, index+1))
+		return
+	}
+
+	readerType, writerType := reader.baseType(), writer.baseType()
+	if !matches(reader, writer) {
+		if promotion(readerType, writerType) != nil {
+			return
+		}
+		if readerType == writerType {
+			cc.addReason(prefix, "writer %s %q does not match reader %s %q", writerType, writer.typeName, readerType, reader.typeName)
+			return
+		}
+		cc.addReason(prefix, "writer type %q cannot be resolved with reader type %q", writerType, readerType)
+		return
+	}
+
+	switch readerType {
+	case "array":
+		cc.check(reader.itemCodec, writer.itemCodec, prefix+"array items: ")
+	case "map":
+		cc.check(reader.itemCodec, writer.itemCodec, prefix+"map values: ")
+	case "enum":
+		if reader.enumDefault != "" {
+			return
+		}
+		readerSymbols := make(map[string]struct{}, len(reader.enumSymbols))
+		for _, symbol := range reader.enumSymbols {
+			readerSymbols[symbol] = struct{}{}
+		}
+		for _, symbol := range writer.enumSymbols {
+			if _, ok := readerSymbols[symbol]; !ok {
+				cc.addReason(prefix, "writer enum %q symbol ought to be member of reader symbols: %v; %q", writer.typeName, reader.enumSymbols, symbol)
+			}
+		}
+	case "fixed":
+		if reader.fixedSize != writer.fixedSize {
+			cc.addReason(prefix, "writer fixed %q size does not match reader size: %d != %d", writer.typeName, writer.fixedSize, reader.fixedSize)
+		}
+	case "record":
+		cc.checkRecord(reader, writer, prefix)
+	}
+}
+
+func (cc *compatibilityChecker) checkRecord(reader, writer *Codec, prefix string) {
+	key := [2]*Codec{reader, writer}
+	if _, ok := cc.records[key]; ok {
+		return
+	}
+	cc.records[key] = struct{}{}
+
+	readerFields, matchedFields := matchFields(reader, writer)
+	for i, writerField := range writer.recordFields {
+		if readerField := readerFields[i]; readerField != nil {
+			cc.check(readerField.codec, writerField.codec, fmt.Sprintf("%srecord %q field %q: ", prefix, reader.typeName, readerField.name))
+		}
+	}
+	for _, readerField := range reader.recordFields {
+		if _, ok := matchedFields[readerField]; !ok && !readerField.hasDefault {
+			cc.addReason(prefix, "record %q field %q: reader field is missing from writer and has no default value", reader.typeName, readerField.name)
+		}
+	}
+}
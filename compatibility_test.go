@@ -0,0 +1,118 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"fmt"
+	"testing"
+)
+
+func testCompatible(t *testing.T, readerSchema, writerSchema string, expected ...string) {
+	t.Helper()
+	ok, reasons, err := Compatible(readerSchema, writerSchema)
+	ensureError(t, err)
+	if actual, expected := fmt.Sprintf("%v %q", ok, reasons), fmt.Sprintf("%v %q", len(expected) == 0, expected); actual != expected {
+		t.Errorf("reader: %s; writer: %s; GOT: %v; WANT: %v", readerSchema, writerSchema, actual, expected)
+	}
+}
+
+func TestCompatiblePrimitives(t *testing.T) {
+	for _, primitive := range []string{"null", "boolean", "int", "long", "float", "double", "bytes", "string"} {
+		testCompatible(t, `"`+primitive+`"`, `"`+primitive+`"`)
+	}
+
+	// promotions
+	testCompatible(t, `"long"`, `"int"`)
+	testCompatible(t, `"float"`, `"int"`)
+	testCompatible(t, `"double"`, `"long"`)
+	testCompatible(t, `"double"`, `"float"`)
+	testCompatible(t, `"bytes"`, `"string"`)
+	testCompatible(t, `"string"`, `"bytes"`)
+	testCompatible(t, `{"type":"long","logicalType":"timestamp-millis"}`, `"int"`)
+
+	testCompatible(t, `"int"`, `"long"`, `writer type "long" cannot be resolved with reader type "int"`)
+	testCompatible(t, `"float"`, `"double"`, `writer type "double" cannot be resolved with reader type "float"`)
+	testCompatible(t, `"boolean"`, `"string"`, `writer type "string" cannot be resolved with reader type "boolean"`)
+}
+
+func TestCompatibleNamedTypes(t *testing.T) {
+	testCompatible(t, `{"type":"fixed","name":"f1","size":4}`, `{"type":"fixed","name":"f1","size":4}`)
+	testCompatible(t, `{"type":"fixed","name":"com.example.f1","size":4}`, `{"type":"fixed","name":"org.example.f1","size":4}`)
+	testCompatible(t, `{"type":"fixed","name":"f2","aliases":["f1"],"size":4}`, `{"type":"fixed","name":"f1","size":4}`)
+
+	testCompatible(t, `{"type":"fixed","name":"f1","size":4}`, `{"type":"fixed","name":"f1","size":8}`, `writer fixed "f1" size does not match reader size: 8 != 4`)
+	testCompatible(t, `{"type":"fixed","name":"f2","size":4}`, `{"type":"fixed","name":"f1","size":4}`, `writer fixed "f1" does not match reader fixed "f2"`)
+
+	testCompatible(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo","charlie"]}`, `{"type":"enum","name":"e1","symbols":["bravo","alpha"]}`)
+	testCompatible(t, `{"type":"enum","name":"e1","symbols":["alpha","other"],"default":"other"}`, `{"type":"enum","name":"e1","symbols":["alpha","bravo"]}`)
+
+	testCompatible(t, `{"type":"enum","name":"e1","symbols":["alpha"]}`, `{"type":"enum","name":"e1","symbols":["alpha","bravo","charlie"]}`,
+		`writer enum "e1" symbol ought to be member of reader symbols: [alpha]; "bravo"`,
+		`writer enum "e1" symbol ought to be member of reader symbols: [alpha]; "charlie"`)
+}
+
+func TestCompatibleRecord(t *testing.T) {
+	testCompatible(t,
+		`{"type":"record","name":"r1","fields":[{"name":"a","type":"long"},{"name":"b","type":"string","default":"none"}]}`,
+		`{"type":"record","name":"r1","fields":[{"name":"c","type":"boolean"},{"name":"a","type":"int"}]}`)
+
+	// fields match by alias
+	testCompatible(t,
+		`{"type":"record","name":"r2","aliases":["r1"],"fields":[{"name":"b","aliases":["a"],"type":"long"}]}`,
+		`{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]}`)
+
+	testCompatible(t,
+		`{"type":"record","name":"r1","fields":[{"name":"a","type":"int"},{"name":"b","type":"string"},{"name":"c","type":{"type":"array","items":"int"}}]}`,
+		`{"type":"record","name":"r1","fields":[{"name":"a","type":"long"},{"name":"c","type":{"type":"array","items":"string"}}]}`,
+		`record "r1" field "a": writer type "long" cannot be resolved with reader type "int"`,
+		`record "r1" field "c": array items: writer type "string" cannot be resolved with reader type "int"`,
+		`record "r1" field "b": reader field is missing from writer and has no default value`)
+
+	// recursive records are checked once
+	testCompatible(t,
+		`{"type":"record","name":"list","fields":[{"name":"value","type":"long"},{"name":"next","type":["null","list"],"default":null}]}`,
+		`{"type":"record","name":"list","fields":[{"name":"value","type":"int"},{"name":"next","type":["null","list"]}]}`)
+	testCompatible(t,
+		`{"type":"record","name":"list","fields":[{"name":"value","type":"int"},{"name":"next","type":["null","list"]}]}`,
+		`{"type":"record","name":"list","fields":[{"name":"value","type":"long"},{"name":"next","type":["null","list"]}]}`,
+		`record "list" field "value": writer type "long" cannot be resolved with reader type "int"`)
+}
+
+func TestCompatibleUnion(t *testing.T) {
+	testCompatible(t, `["null","string","long"]`, `["long","null"]`)
+	testCompatible(t, `["null","double"]`, `"int"`)
+	testCompatible(t, `"long"`, `["int"]`)
+
+	// every writer member is checked, not only those data happens to use
+	testCompatible(t, `["null","string"]`, `["null","string","boolean"]`,
+		`writer union item 3: writer type "boolean" does not match any reader union member: [null string]`)
+	testCompatible(t, `"string"`, `["null","string"]`,
+		`writer union item 1: writer type "null" cannot be resolved with reader type "string"`)
+	testCompatible(t, `{"type":"map","values":["null",{"type":"enum","name":"e1","symbols":["alpha"]}]}`, `{"type":"map","values":["null",{"type":"enum","name":"e1","symbols":["alpha","bravo"]}]}`,
+		`map values: writer union item 2: reader union item 2: writer enum "e1" symbol ought to be member of reader symbols: [alpha]; "bravo"`)
+}
+
+func TestCompatibleInvalidSchema(t *testing.T) {
+	_, _, err := Compatible(`"long"`, `"nonexistent"`)
+	ensureError(t, err, "cannot create writer codec")
+	_, _, err = Compatible(`{"type":"array"}`, `"long"`)
+	ensureError(t, err, "cannot create reader codec")
+}
+
+func ExampleCompatible() {
+	ok, reasons, err := Compatible(
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"long"},{"name":"b","type":"string"}]}`,
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"int"}]}`)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(ok, reasons)
+	// Output: false [record "r" field "b": reader field is missing from writer and has no default value]
+}
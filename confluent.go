@@ -0,0 +1,227 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const confluentMagicByte = 0     // leading byte of Confluent wire format data
+const confluentHeaderLen = 1 + 4 // magic byte plus 4-byte big-endian schema ID
+
+// ErrNotConfluentEncoded is returned when an attempt is made to decode a
+// Confluent wire format value from a buffer that does not have the correct
+// header.
+type ErrNotConfluentEncoded string
+
+func (e ErrNotConfluentEncoded) Error() string {
+	return "cannot decode buffer as Confluent wire format: " + string(e)
+}
+
+// BinaryFromNativeConfluent appends the Confluent wire format representation of
+// the provided native datum value to the provided byte slice in accordance with
+// the Avro schema supplied when creating the Codec. The Confluent wire format,
+// used by Kafka clients backed by the Confluent Schema Registry, is a zero
+// magic byte, followed by the 4-byte big-endian schema ID assigned by the
+// registry, followed by the binary Avro encoded data. On success, it returns a
+// new byte slice with the encoded bytes appended, and a nil error value. On
+// error, it returns the original byte slice, and the error message.
+//
+//     func ExampleCodec_BinaryFromNativeConfluent() {
+//         codec, err := goavro.NewCodec(`"int"`)
+//         if err != nil {
+//             fmt.Fprintf(os.Stderr, "%s\n", err)
+//             return
+//         }
+//
+//         buf, err := codec.BinaryFromNativeConfluent(nil, 42, 3)
+//         if err != nil {
+//             fmt.Fprintf(os.Stderr, "%s\n", err)
+//             return
+//         }
+//
+//         fmt.Println(buf)
+//         // Output: [0 0 0 0 42 6]
+//     }
+func (c *Codec) BinaryFromNativeConfluent(buf []byte, schemaID int32, datum interface{}) ([]byte, error) {
+	header := [confluentHeaderLen]byte{confluentMagicByte}
+	binary.BigEndian.PutUint32(header[1:], uint32(schemaID))
+	newBuf, err := c.encodeBinary(append(buf, header[:]...), datum)
+	if err != nil {
+		return buf, err // if error, return original byte slice
+	}
+	return newBuf, nil
+}
+
+// ConfluentSchemaID returns the schema ID from the header of the Confluent wire
+// format data in buf, along with the remaining binary Avro encoded bytes after
+// the header. The schema ID is designed to be used to fetch the schema from a
+// Confluent Schema Registry, and to lookup or create a Codec for that schema,
+// whose NativeFromBinary method may be used to decode the remaining bytes
+// returned as the second return value. On failure this function returns an
+// ErrNotConfluentEncoded error.
+//
+//     func decode(codex map[int32]*goavro.Codec, buf []byte) error {
+//         schemaID, newBuf, err := goavro.ConfluentSchemaID(buf)
+//         if err != nil {
+//             return err
+//         }
+//
+//         // Get a previously stored Codec from the codex map.
+//         codec, ok := codex[schemaID]
+//         if !ok {
+//             return fmt.Errorf("unknown schema ID: %d", schemaID)
+//         }
+//
+//         datum, _, err := codec.NativeFromBinary(newBuf)
+//         if err != nil {
+//             return err
+//         }
+//
+//         _, err = fmt.Println(datum)
+//         return err
+//     }
+func ConfluentSchemaID(buf []byte) (int32, []byte, error) {
+	if len(buf) < confluentHeaderLen {
+		// Not enough bytes to encode schema ID.
+		return 0, nil, ErrNotConfluentEncoded(io.ErrShortBuffer.Error())
+	}
+
+	if buf[0] != confluentMagicByte {
+		return 0, nil, ErrNotConfluentEncoded(fmt.Sprintf("unknown magic byte: %#x", buf[0]))
+	}
+
+	return int32(binary.BigEndian.Uint32(buf[1:confluentHeaderLen])), buf[confluentHeaderLen:], nil
+}
+
+// Registry assigns schema IDs to schemas, like a Confluent Schema Registry, and
+// returns the Codec of the schema assigned a schema ID. It is used to encode and
+// decode Confluent wire format data without maintaining a separate mapping of
+// schema IDs to Codecs.
+type Registry interface {
+	// Register returns the schema ID assigned to schema, assigning one when
+	// the schema was not registered yet.
+	Register(schema string) (id int32, err error)
+
+	// Lookup returns the Codec of the schema assigned the schema ID.
+	Lookup(id int32) (*Codec, error)
+}
+
+// MemoryRegistry is a Registry which keeps its schemas in memory, and may be
+// used by many go routines simultaneously. It assigns schema IDs in the order
+// schemas are registered, starting from 1. Schemas having the same Parsing
+// Canonical Form are assigned the same schema ID.
+type MemoryRegistry struct {
+	mu             sync.RWMutex
+	codecFromID    map[int32]*Codec
+	idFromSchema   map[string]int32 // schemas as they were registered
+	idFromRabin    map[uint64]int32 // fingerprints of their canonical form
+	lastAssignedID int32
+}
+
+// NewMemoryRegistry returns an empty MemoryRegistry.
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{
+		codecFromID:  make(map[int32]*Codec),
+		idFromSchema: make(map[string]int32),
+		idFromRabin:  make(map[uint64]int32),
+	}
+}
+
+// Register returns the schema ID assigned to schema, assigning the next schema
+// ID when neither schema nor a schema having the same Parsing Canonical Form was
+// registered yet. An error is returned when schema is not a valid Avro schema.
+func (r *MemoryRegistry) Register(schema string) (int32, error) {
+	r.mu.RLock()
+	id, ok := r.idFromSchema[schema]
+	r.mu.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	codec, err := NewCodec(schema)
+	if err != nil {
+		return 0, fmt.Errorf("cannot register schema: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id, ok = r.idFromRabin[codec.Rabin]; !ok {
+		r.lastAssignedID++
+		id = r.lastAssignedID
+		r.codecFromID[id] = codec
+		r.idFromRabin[codec.Rabin] = id
+	}
+	r.idFromSchema[schema] = id
+	return id, nil
+}
+
+// Lookup returns the Codec of the schema assigned the schema ID, or an error
+// when no schema was assigned that schema ID.
+func (r *MemoryRegistry) Lookup(id int32) (*Codec, error) {
+	r.mu.RLock()
+	codec, ok := r.codecFromID[id]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown schema ID: %d", id)
+	}
+	return codec, nil
+}
+
+// SchemaID returns the schema ID the registry assigns to the schema of the
+// Codec, registering the schema when needed.
+func (c *Codec) SchemaID(registry Registry) (int32, error) {
+	return registry.Register(c.schemaOriginal)
+}
+
+// BinaryFromNativeRegistry appends the Confluent wire format representation of
+// the provided native datum value to the provided byte slice, like
+// BinaryFromNativeConfluent does, using the schema ID the registry assigns to
+// the schema of the Codec. On error, it returns the original byte slice, and
+// the error message.
+//
+//     registry := goavro.NewMemoryRegistry()
+//     buf, err := codec.BinaryFromNativeRegistry(nil, registry, datum)
+//     if err != nil {
+//         return err
+//     }
+//     datum, _, err = goavro.NativeFromConfluent(registry, buf)
+func (c *Codec) BinaryFromNativeRegistry(buf []byte, registry Registry, datum interface{}) ([]byte, error) {
+	schemaID, err := c.SchemaID(registry)
+	if err != nil {
+		return buf, err
+	}
+	return c.BinaryFromNativeConfluent(buf, schemaID, datum)
+}
+
+// NativeFromConfluent decodes the Confluent wire format data in buf using the
+// Codec the registry returns for its schema ID. On success, it returns the
+// decoded datum, a byte slice containing the remaining undecoded bytes, and a
+// nil error value. On error, it returns nil for the datum value, the original
+// byte slice, and the error message, which is an ErrNotConfluentEncoded error
+// when buf does not start with a Confluent wire format header.
+func NativeFromConfluent(registry Registry, buf []byte) (interface{}, []byte, error) {
+	schemaID, newBuf, err := ConfluentSchemaID(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+	codec, err := registry.Lookup(schemaID)
+	if err != nil {
+		return nil, buf, err
+	}
+	datum, newBuf, err := codec.NativeFromBinary(newBuf)
+	if err != nil {
+		return nil, buf, err
+	}
+	return datum, newBuf, nil
+}
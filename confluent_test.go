@@ -0,0 +1,167 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func ExampleCodec_BinaryFromNativeConfluent() {
+	codec, err := NewCodec(`"int"`)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		return
+	}
+
+	buf, err := codec.BinaryFromNativeConfluent(nil, 42, 3)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		return
+	}
+
+	fmt.Println(buf)
+	// Output: [0 0 0 0 42 6]
+}
+
+func TestConfluentEncoding(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":"string"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("does not modify source buf when cannot encode", func(t *testing.T) {
+		buf := []byte{0xDE, 0xAD, 0xBE, 0xEF}
+
+		buf, err := codec.BinaryFromNativeConfluent(buf, 1, "records cannot be encoded from strings")
+		ensureError(t, err, "cannot encode binary record")
+
+		if got, want := buf, []byte("\xDE\xAD\xBE\xEF"); !bytes.Equal(got, want) {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		buf, err := codec.BinaryFromNativeConfluent([]byte("\x01\x02"), 0x01020304, map[string]interface{}{"f1": "hi"})
+		ensureError(t, err)
+
+		if got, want := buf, []byte("\x01\x02\x00\x01\x02\x03\x04\x04hi"); !bytes.Equal(got, want) {
+			t.Fatalf("GOT: %v; WANT: %v", got, want)
+		}
+
+		schemaID, newBuf, err := ConfluentSchemaID(append(buf[2:], "\xDE\xAD"...))
+		ensureError(t, err)
+
+		if got, want := schemaID, int32(0x01020304); got != want {
+			t.Errorf("GOT: %#x; WANT: %#x", got, want)
+		}
+
+		datum, newBuf, err := codec.NativeFromBinary(newBuf)
+		ensureError(t, err)
+
+		if got, want := datum.(map[string]interface{})["f1"], "hi"; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+
+		// ensure junk is not disturbed
+		if got, want := newBuf, []byte("\xDE\xAD"); !bytes.Equal(got, want) {
+			t.Errorf("GOT: %q; WANT: %q", got, want)
+		}
+	})
+}
+
+func TestConfluentSchemaIDErrors(t *testing.T) {
+	_, _, err := ConfluentSchemaID([]byte("\x00\x00\x00"))
+	if _, ok := err.(ErrNotConfluentEncoded); !ok {
+		t.Fatalf("GOT: %#v; WANT: ErrNotConfluentEncoded", err)
+	}
+	ensureError(t, err, "short buffer")
+
+	_, _, err = ConfluentSchemaID([]byte("\xC3\x01\x00\x00\x00\x06"))
+	if _, ok := err.(ErrNotConfluentEncoded); !ok {
+		t.Fatalf("GOT: %#v; WANT: ErrNotConfluentEncoded", err)
+	}
+	ensureError(t, err, "unknown magic byte: 0xc3")
+}
+
+func TestMemoryRegistry(t *testing.T) {
+	registry := NewMemoryRegistry()
+
+	schemas := []string{`"int"`, `{"type":"record","name":"r1","fields":[{"name":"f1","type":"string"}]}`}
+	for i, schema := range schemas {
+		id, err := registry.Register(schema)
+		ensureError(t, err)
+		if actual, expected := id, int32(i+1); actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+		codec, err := registry.Lookup(id)
+		ensureError(t, err)
+		if actual, expected := codec.Schema(), schema; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	}
+
+	// schemas having the same canonical form are assigned the same schema ID
+	for _, c := range []struct {
+		schema string
+		id     int32
+	}{
+		{`{"type":"int"}`, 1},
+		{` { "type" : "record", "name" : "r1", "doc" : "some doc", "fields" : [ { "name" : "f1", "type" : "string" } ] }`, 2},
+		{`"long"`, 3},
+	} {
+		id, err := registry.Register(c.schema)
+		ensureError(t, err)
+		if actual, expected := id, c.id; actual != expected {
+			t.Errorf("schema: %s; GOT: %v; WANT: %v", c.schema, actual, expected)
+		}
+	}
+
+	_, err := registry.Register(`"nonexistent"`)
+	ensureError(t, err, "cannot register schema")
+	_, err = registry.Lookup(4)
+	ensureError(t, err, "unknown schema ID: 4")
+}
+
+func TestConfluentRegistryRoundTrip(t *testing.T) {
+	registry := NewMemoryRegistry()
+	if _, err := registry.Register(`"string"`); err != nil {
+		t.Fatal(err)
+	}
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":"long"}]}`)
+	ensureError(t, err)
+
+	id, err := codec.SchemaID(registry)
+	ensureError(t, err)
+	if actual, expected := id, int32(2); actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	buf, err := codec.BinaryFromNativeRegistry([]byte("prefix"), registry, map[string]interface{}{"f1": 13})
+	ensureError(t, err)
+	if actual, expected := buf, []byte("prefix\x00\x00\x00\x00\x02\x1a"); !bytes.Equal(actual, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+	}
+
+	datum, remaining, err := NativeFromConfluent(registry, append(buf[len("prefix"):], "trailing"...))
+	ensureError(t, err)
+	if actual, expected := fmt.Sprintf("%v %s", datum, remaining), "map[f1:13] trailing"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	_, _, err = NativeFromConfluent(registry, []byte("\x00\x00\x00\x00\x07\x1a"))
+	ensureError(t, err, "unknown schema ID: 7")
+	_, _, err = NativeFromConfluent(registry, []byte("\x01\x00\x00\x00\x02\x1a"))
+	if _, ok := err.(ErrNotConfluentEncoded); !ok {
+		t.Errorf("GOT: %#v; WANT: ErrNotConfluentEncoded", err)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"context"
+	"fmt"
+)
+
+// contextCheckInterval is the number of array items, map values, and record
+// fields decoded between checks of whether the context is done.
+const contextCheckInterval = 256
+
+// NativeFromBinaryContext decodes a binary value like NativeFromBinary does, but
+// stops decoding once ctx is done, returning the error of the context. The
+// context is checked before decoding starts, and periodically while decoding the
+// items of arrays, the values of maps, and the fields of records, so a
+// cancellation or deadline stops the decoding of a large value.
+//
+//     ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//     defer cancel()
+//     native, _, err := codec.NativeFromBinaryContext(ctx, buf)
+//     if err == context.DeadlineExceeded {
+//         // decoding took too long
+//     }
+func (c *Codec) NativeFromBinaryContext(ctx context.Context, buf []byte) (interface{}, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, buf, err
+	}
+	cd := &contextDecoder{ctx: ctx}
+	if c.recursive {
+		cd.maxDepth = c.maxDecodeDepth()
+	}
+	value, newBuf, err := cd.nativeFromBinary(c, buf)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, buf, ctxErr
+		}
+		return nil, buf, c.located(err, 0) // if error, return original byte slice
+	}
+	return value, newBuf, nil
+}
+
+// boundedNativeFromBinary decodes a binary value like nativeFromBinary does,
+// but when the schema of the Codec is recursive, it limits the depth of the
+// decoded value, because the decoders of codecs do not track their depth.
+func (c *Codec) boundedNativeFromBinary(buf []byte) (interface{}, []byte, error) {
+	if !c.recursive {
+		return c.nativeFromBinary(buf)
+	}
+	cd := &contextDecoder{ctx: context.Background(), maxDepth: c.maxDecodeDepth()}
+	return cd.nativeFromBinary(c, buf)
+}
+
+// contextDecoder decodes binary values like the nativeFromBinary functions of
+// codecs do, while periodically checking whether its context is done.
+type contextDecoder struct {
+	ctx    context.Context
+	checks int
+
+	// limits, when not nil, replaces the decode limits of the codecs of arrays
+	// and maps. maxItems, when greater than zero, limits the number of array
+	// items and map values decoded in total, and maxDepth, when greater than
+	// zero, limits the number of unions, arrays, maps, and records nested
+	// within one another.
+	limits   *decodeLimits
+	maxItems int64
+	items    int64
+	maxDepth int
+	depth    int
+}
+
+// item returns an error when decoding another array item or map value exceeds
+// the limit on the total number of items.
+func (cd *contextDecoder) item() error {
+	cd.items++
+	if cd.maxItems > 0 && cd.items > cd.maxItems {
+		return fmt.Errorf("cannot decode binary when total item count exceeds limit: %d", cd.maxItems)
+	}
+	return nil
+}
+
+// itemDecoder returns a decoder of array items or map values, which counts each
+// item, and periodically checks the context, before decoding it using decode.
+func (cd *contextDecoder) itemDecoder(decode resolvedDecoder) func([]byte) (interface{}, []byte, error) {
+	return func(buf []byte) (interface{}, []byte, error) {
+		if err := cd.item(); err != nil {
+			return nil, nil, err
+		}
+		if err := cd.err(); err != nil {
+			return nil, nil, err
+		}
+		return decode(cd, buf)
+	}
+}
+
+// itemLimits returns the decode limits of the items of the array or map codec.
+func (cd *contextDecoder) itemLimits(c *Codec) decodeLimits {
+	if cd.limits != nil {
+		return *cd.limits
+	}
+	return c.decodeLimits
+}
+
+// enter returns an error when decoding another nested value exceeds the depth
+// limit, and otherwise descends one level, which the caller ought to leave.
+func (cd *contextDecoder) enter() error {
+	if cd.maxDepth > 0 && cd.depth >= cd.maxDepth {
+		return fmt.Errorf("cannot decode binary when nesting depth exceeds limit: %d", cd.maxDepth)
+	}
+	cd.depth++
+	return nil
+}
+
+func (cd *contextDecoder) leave() { cd.depth-- }
+
+// err returns the error of the context when it is done, checking the context
+// only once every contextCheckInterval invocations.
+func (cd *contextDecoder) err() error {
+	cd.checks++
+	if cd.checks%contextCheckInterval != 0 {
+		return nil
+	}
+	return cd.ctx.Err()
+}
+
+func (cd *contextDecoder) nativeFromBinary(c *Codec, buf []byte) (interface{}, []byte, error) {
+	if c.resolvedFromBinary != nil {
+		return c.resolvedFromBinary(cd, buf)
+	}
+
+	if c.annotated != nil {
+		// NOTE: The decoders of a registered logical type wrap those of the
+		// type it annotates, so decode the annotated value here, and only then
+		// convert it.
+		value, newBuf, err := cd.nativeFromBinary(c.annotated, buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		if value, err = c.registeredNative("binary", value); err != nil {
+			return nil, nil, err
+		}
+		return value, newBuf, nil
+	}
+
+	if c.unionInfo != nil || c.recordFromBinary != nil || (c.itemCodec != nil && (c.typeName.fullName == "array" || c.typeName.fullName == "map")) {
+		if err := cd.enter(); err != nil {
+			return nil, nil, err
+		}
+		defer cd.leave()
+	}
+
+	if cr := c.unionInfo; cr != nil {
+		decoded, remaining, err := longNativeFromBinary(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		index := decoded.(int64) // longDecoder always returns int64, so elide error checking
+		if index < 0 || index >= int64(len(cr.codecFromIndex)) {
+			return nil, nil, fmt.Errorf("cannot decode binary union: index ought to be between 0 and %d; read index: %d", len(cr.codecFromIndex)-1, index)
+		}
+		if cr.allowedTypes[index] == "null" {
+			return nil, remaining, nil
+		}
+		decoded, newBuf, err := cd.nativeFromBinary(cr.codecFromIndex[index], remaining)
+		if err != nil {
+			return nil, nil, newDecodeError(err, fmt.Errorf("cannot decode binary union item %d: %w", index+1, err), "", len(buf)-len(remaining))
+		}
+		return unionNativeFromMember(cr, int(index), decoded), newBuf, nil
+	}
+
+	switch {
+	case c.itemCodec != nil && c.typeName.fullName == "array":
+		return genericArrayBinaryDecoder(buf, func(buf []byte) (interface{}, []byte, error) {
+			if err := cd.item(); err != nil {
+				return nil, nil, err
+			}
+			if err := cd.err(); err != nil {
+				return nil, nil, err
+			}
+			return cd.nativeFromBinary(c.itemCodec, buf)
+		}, cd.itemLimits(c))
+	case c.itemCodec != nil && c.typeName.fullName == "map":
+		return genericMapBinaryDecoder(buf, func(buf []byte) (interface{}, []byte, error) {
+			if err := cd.item(); err != nil {
+				return nil, nil, err
+			}
+			if err := cd.err(); err != nil {
+				return nil, nil, err
+			}
+			return cd.nativeFromBinary(c.itemCodec, buf)
+		}, cd.itemLimits(c))
+	case c.recordFromBinary != nil:
+		recordMap := make(map[string]interface{}, len(c.recordFields))
+		remaining, err := cd.recordFromBinary(c, buf, recordMap)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c.recordNative(recordMap), remaining, nil
+	default:
+		return c.nativeFromBinary(buf)
+	}
+}
+
+// recordFromBinary decodes the fields of a binary record into recordMap, like
+// the recordFromBinary function of the record codec does.
+func (cd *contextDecoder) recordFromBinary(c *Codec, buf []byte, recordMap map[string]interface{}) ([]byte, error) {
+	remaining := buf
+	for _, field := range c.recordFields {
+		if err := cd.err(); err != nil {
+			return nil, err
+		}
+		value, newBuf, err := cd.nativeFromBinary(field.codec, remaining)
+		if err != nil {
+			return nil, newDecodeError(err, fmt.Errorf("cannot decode binary record %q field %q: %w", c.typeName, field.name, err), "."+field.name, len(buf)-len(remaining))
+		}
+		recordMap[field.name] = value
+		remaining = newBuf
+	}
+	return remaining, nil
+}
@@ -0,0 +1,125 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+)
+
+// countdownContext is a context which is cancelled once its Err method has
+// been invoked a number of times, so tests may cancel it partway through a
+// decode.
+type countdownContext struct {
+	context.Context
+	remaining int
+}
+
+func (ctx *countdownContext) Err() error {
+	if ctx.remaining--; ctx.remaining < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestNativeFromBinaryContext(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"items","type":{"type":"array","items":{"type":"map","values":["null","long","string"]}}}]}`)
+	ensureError(t, err)
+	items := make([]interface{}, 10000)
+	for i := range items {
+		items[i] = map[string]interface{}{"a": Union("long", int64(i)), "b": nil}
+	}
+	datum := map[string]interface{}{"items": items}
+	buf, err := codec.BinaryFromNative(nil, datum)
+	ensureError(t, err)
+
+	// decodes the same as NativeFromBinary
+	expected, _, err := codec.NativeFromBinary(buf)
+	ensureError(t, err)
+	actual, remaining, err := codec.NativeFromBinaryContext(context.Background(), append(buf, 0xff))
+	ensureError(t, err)
+	if fmt.Sprint(actual) != fmt.Sprint(expected) || !bytes.Equal(remaining, []byte{0xff}) {
+		t.Errorf("GOT: %v, %v; WANT: %v, [255]", actual, remaining, expected)
+	}
+
+	// cancelled before decoding starts
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, remaining, err = codec.NativeFromBinaryContext(ctx, buf)
+	if err != context.Canceled || !bytes.Equal(remaining, buf) {
+		t.Errorf("GOT: %v; WANT: %v", err, context.Canceled)
+	}
+
+	// cancelled partway through decoding the array
+	countdown := &countdownContext{Context: context.Background(), remaining: 3}
+	_, _, err = codec.NativeFromBinaryContext(countdown, buf)
+	if err != context.Canceled {
+		t.Errorf("GOT: %v; WANT: %v", err, context.Canceled)
+	}
+
+	// other errors are located as they are by NativeFromBinary
+	_, _, err = codec.NativeFromBinaryContext(context.Background(), buf[:len(buf)/2])
+	ensureError(t, err, "cannot decode binary record", "path: r1.items[")
+}
+
+func TestNativeFromBinaryContextReaderWriter(t *testing.T) {
+	codec, err := NewCodecForReaderWriter(`{"type":"array","items":"long"}`, `{"type":"array","items":"int"}`)
+	ensureError(t, err)
+	native, _, err := codec.NativeFromBinaryContext(context.Background(), []byte{0x02, 0x06, 0x00})
+	ensureError(t, err)
+	if actual, expected := fmt.Sprintf("%#v", native), "[]interface {}{3}"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err = codec.NativeFromBinaryContext(ctx, []byte{0x02, 0x06, 0x00}); err != context.Canceled {
+		t.Errorf("GOT: %v; WANT: %v", err, context.Canceled)
+	}
+}
+
+func TestNativeFromBinaryContextRegisteredLogicalType(t *testing.T) {
+	RegisterLogicalType("test-context-count", nil, func(native interface{}) (interface{}, error) {
+		return len(native.([]interface{})), nil
+	})
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":{"type":"array","items":"int","logicalType":"test-context-count"}}]}`)
+	ensureError(t, err)
+	buf := []byte{0x04, 0x02, 0x04, 0x00}
+	expected, _, err := codec.NativeFromBinary(buf)
+	ensureError(t, err)
+	actual, _, err := codec.NativeFromBinaryContext(context.Background(), buf)
+	ensureError(t, err)
+	if fmt.Sprint(actual) != fmt.Sprint(expected) || fmt.Sprint(actual) != "map[a:2]" {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
+
+func TestNativeFromBinaryRecursiveRegisteredLogicalType(t *testing.T) {
+	RegisterLogicalType("test-recursive-count", nil, func(native interface{}) (interface{}, error) {
+		return len(native.([]interface{})), nil
+	})
+	schema := `{"type":"record","name":"LongList","fields":[{"name":"a","type":{"type":"array","items":"int","logicalType":"test-recursive-count"}},{"name":"next","type":["null","LongList"]}]}`
+	buf := []byte{0x04, 0x02, 0x04, 0x00, 0x00}
+	codec, err := NewCodec(schema)
+	ensureError(t, err)
+	for _, decode := range []func([]byte) (interface{}, []byte, error){
+		codec.NativeFromBinary,
+		func(buf []byte) (interface{}, []byte, error) {
+			return codec.NativeFromBinaryContext(context.Background(), buf)
+		},
+	} {
+		native, _, err := decode(buf)
+		ensureError(t, err)
+		if actual, expected := fmt.Sprint(native), "map[a:2 next:<nil>]"; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	}
+}
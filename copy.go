@@ -0,0 +1,79 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"math/big"
+	"reflect"
+)
+
+// CopyNative returns a deep copy of a native Avro value, as returned by the
+// decoders of a Codec, which shares no memory with v. The copy may be modified
+// without changing v, and v may be modified without changing the copy.
+//
+// Maps, including the single key maps of union values, slices, byte slices,
+// *big.Rat values of decimal logical types, and the pointers of nullable union
+// values are copied. Values which cannot be modified, such as strings, numbers,
+// time.Time and Enum values, are returned as is, as are values of any other
+// type.
+//
+//     native, _, err := codec.NativeFromBinary(buf)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     snapshot := goavro.CopyNative(native)
+func CopyNative(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = CopyNative(value)
+		}
+		return m
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		s := make([]interface{}, len(v))
+		for i, value := range v {
+			s[i] = CopyNative(value)
+		}
+		return s
+	case []byte:
+		if v == nil {
+			return v
+		}
+		return append(make([]byte, 0, len(v)), v...)
+	case *big.Rat:
+		if v == nil {
+			return v
+		}
+		return new(big.Rat).Set(v)
+	}
+
+	// NOTE: Nullable union values decoded from binary are pointers to the value
+	// of their member, and union record fields decoded from textual data are
+	// pointers to an empty interface.
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return v
+	}
+	elem := CopyNative(rv.Elem().Interface())
+	p := reflect.New(rv.Type().Elem())
+	if elem != nil {
+		p.Elem().Set(reflect.ValueOf(elem))
+	}
+	return p.Interface()
+}
@@ -0,0 +1,110 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testCopySchema = `{"type":"record","name":"r1","fields":[
+	{"name":"amount","type":{"type":"bytes","logicalType":"decimal","precision":6,"scale":2}},
+	{"name":"maybeAmount","type":["null",{"type":"bytes","logicalType":"decimal","precision":6,"scale":2}]},
+	{"name":"maybeName","type":["null","string"]},
+	{"name":"at","type":{"type":"long","logicalType":"timestamp-millis"}},
+	{"name":"hash","type":{"type":"fixed","name":"f1","size":2}},
+	{"name":"blob","type":"bytes"},
+	{"name":"labels","type":{"type":"array","items":{"type":"map","values":"int"}}},
+	{"name":"either","type":["null","int","string"]}
+]}`
+
+func TestCopyNative(t *testing.T) {
+	codec, err := NewCodec(testCopySchema)
+	ensureError(t, err)
+
+	at := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+	buf, err := codec.BinaryFromNative(nil, map[string]interface{}{
+		"amount":      big.NewRat(1234, 100),
+		"maybeAmount": Union("bytes.decimal", big.NewRat(-5, 2)),
+		"maybeName":   Union("string", "Ann"),
+		"at":          at,
+		"hash":        []byte("ab"),
+		"blob":        []byte("blob"),
+		"labels":      []interface{}{map[string]interface{}{"a": 1}},
+		"either":      Union("string", "x"),
+	})
+	ensureError(t, err)
+	native, _, err := codec.NativeFromBinary(buf)
+	ensureError(t, err)
+	original, _, err := codec.NativeFromBinary(buf)
+	ensureError(t, err)
+
+	copied := CopyNative(native)
+	if !reflect.DeepEqual(copied, original) {
+		t.Fatalf("GOT: %#v; WANT: %#v", copied, original)
+	}
+
+	// modify every mutable part of the copy, which ought not to modify the
+	// value it was copied from
+	m := copied.(map[string]interface{})
+	m["amount"].(*big.Rat).SetInt64(7)
+	(*m["maybeAmount"].(**big.Rat)).SetInt64(7)
+	*m["maybeName"].(*string) = "Bob"
+	m["hash"].([]byte)[0] = 'z'
+	m["blob"].([]byte)[0] = 'z'
+	m["labels"].([]interface{})[0].(map[string]interface{})["a"] = int32(2)
+	m["either"].(map[string]interface{})["string"] = "y"
+	m["at"] = time.Time{}
+	if !reflect.DeepEqual(native, original) {
+		t.Errorf("GOT: %#v; WANT: %#v", native, original)
+	}
+	if actual, expected := native.(map[string]interface{})["at"].(time.Time), at; !actual.Equal(expected) {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
+
+func TestCopyNativeTextualUnion(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":["null","string"]}]}`)
+	ensureError(t, err)
+	native, _, err := codec.NativeFromTextual([]byte(`{"f1":{"string":"x"}}`))
+	ensureError(t, err)
+
+	copied := CopyNative(native).(map[string]interface{})
+	field := copied["f1"].(*interface{})
+	if field == native.(map[string]interface{})["f1"].(*interface{}) {
+		t.Fatal("GOT: shared pointer; WANT: copied pointer")
+	}
+	(*field).(map[string]interface{})["string"] = "y"
+	if actual, expected := (*native.(map[string]interface{})["f1"].(*interface{})).(map[string]interface{})["string"], "x"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
+
+func TestCopyNativeUnchanged(t *testing.T) {
+	var nilPointer *string
+	for _, v := range []interface{}{
+		nil,
+		true,
+		int32(3),
+		3.5,
+		"x",
+		Enum{Symbol: "alpha", Ordinal: 0},
+		time.Duration(5),
+		nilPointer,
+		[]byte(nil),
+		map[string]interface{}(nil),
+	} {
+		if actual := CopyNative(v); !reflect.DeepEqual(actual, v) {
+			t.Errorf("GOT: %#v; WANT: %#v", actual, v)
+		}
+	}
+}
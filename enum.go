@@ -11,19 +11,59 @@ package goavro
 
 import (
 	"fmt"
-	"io"
 )
 
 type avroEnum interface {
 	Str() string
 }
 
+// Enum is the native form of an enum value decoded by a Codec created using
+// WithEnumValues, which holds both its symbol and its ordinal, the index of the
+// symbol in the schema. Like any type implementing a Str method returning the
+// symbol, it may also be encoded by any Codec.
+type Enum struct {
+	Symbol  string
+	Ordinal int
+}
+
+// Str returns the symbol of the enum value.
+func (e Enum) Str() string { return e.Symbol }
+
+// String returns the symbol of the enum value.
+func (e Enum) String() string { return e.Symbol }
+
+// WithEnumValues returns a CodecOption which makes the enum decoders of the
+// Codec return Enum values rather than strings.
+//
+//     codec, err := goavro.NewCodecWithOptions(`{"type":"enum","name":"e1","symbols":["alpha","bravo"]}`, goavro.WithEnumValues())
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     native, _, err := codec.NativeFromBinary([]byte{0x02})
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Printf("%#v", native) // goavro.Enum{Symbol:"bravo", Ordinal:1}
+func WithEnumValues() CodecOption {
+	return func(o *codecOptions) {
+		o.enumValues = true
+	}
+}
+
+// enumNative returns the native form of the symbol of an enum Codec at index.
+func (c *Codec) enumNative(index int) interface{} {
+	if c.enumValues {
+		return Enum{Symbol: c.enumSymbols[index], Ordinal: index}
+	}
+	return c.enumSymbols[index]
+}
+
 // enum does not have child objects, therefore whatever namespace it defines is
 // just to store its name in the symbol table.
 func makeEnumCodec(st map[string]*Codec, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
 	c, err := registerNewCodec(st, schemaMap, enclosingNamespace)
 	if err != nil {
-		return nil, fmt.Errorf("Enum ought to have valid name: %s", err)
+		return nil, fmt.Errorf("Enum ought to have valid name: %w", err)
 	}
 
 	// enum type must have symbols
@@ -36,16 +76,36 @@ func makeEnumCodec(st map[string]*Codec, enclosingNamespace string, schemaMap ma
 		return nil, fmt.Errorf("Enum %q symbols ought to be non-empty array of strings: %v", c.typeName, s1)
 	}
 	symbols := make([]string, len(s2))
+	seen := make(map[string]struct{}, len(s2))
 	for i, s := range s2 {
 		symbol, ok := s.(string)
 		if !ok {
-			return nil, fmt.Errorf("Enum %q symbol %d ought to be non-empty string; received: %T", c.typeName, i+1, symbol)
+			return nil, fmt.Errorf("Enum %q symbol %d ought to be non-empty string; received: %T", c.typeName, i+1, s)
 		}
 		if err := checkString(symbol); err != nil {
-			return nil, fmt.Errorf("Enum %q symbol %d ought to %s", c.typeName, i+1, err)
+			return nil, fmt.Errorf("Enum %q symbol %d ought to %w", c.typeName, i+1, err)
+		}
+		if _, ok := seen[symbol]; ok {
+			return nil, fmt.Errorf("Enum %q symbol %d ought to be unique: %q", c.typeName, i+1, symbol)
 		}
+		seen[symbol] = struct{}{}
 		symbols[i] = symbol
 	}
+	c.schemaType = "enum"
+	c.enumSymbols = symbols
+
+	// NOTE: The default symbol is only used when resolving data encoded using
+	// a writer schema whose symbol is not a member of this reader schema.
+	if d, ok := schemaMap["default"]; ok {
+		symbol, ok := d.(string)
+		if !ok {
+			return nil, fmt.Errorf("Enum %q default ought to be string; received: %T", c.typeName, d)
+		}
+		if !isEnumSymbol(symbols, symbol) {
+			return nil, fmt.Errorf("Enum %q default ought to be member of symbols: %v; %q", c.typeName, symbols, symbol)
+		}
+		c.enumDefault = symbol
+	}
 
 	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
 		var value interface{}
@@ -53,13 +113,13 @@ func makeEnumCodec(st map[string]*Codec, enclosingNamespace string, schemaMap ma
 		var index int64
 
 		if value, buf, err = longNativeFromBinary(buf); err != nil {
-			return nil, nil, fmt.Errorf("cannot decode binary enum %q index: %s", c.typeName, err)
+			return nil, nil, fmt.Errorf("cannot decode binary enum %q index: %w", c.typeName, err)
 		}
 		index = value.(int64)
 		if index < 0 || index >= int64(len(symbols)) {
 			return nil, nil, fmt.Errorf("cannot decode binary enum %q: index ought to be between 0 and %d; read index: %d", c.typeName, len(symbols)-1, index)
 		}
-		return symbols[index], buf, nil
+		return c.enumNative(int(index)), buf, nil
 	}
 	c.binaryFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
 
@@ -76,26 +136,26 @@ func makeEnumCodec(st map[string]*Codec, enclosingNamespace string, schemaMap ma
 				return longBinaryFromNative(buf, i)
 			}
 		}
-		return nil, fmt.Errorf("cannot encode binary enum %q: value ought to be member of symbols: %v; %q", c.typeName, symbols, someString)
+		return nil, fmt.Errorf("cannot encode binary enum %q: %w", c.typeName, newErrEnumSymbol(symbols, someString))
 	}
 	c.nativeFromTextual = func(buf []byte) (interface{}, []byte, error) {
 		if buf, _ = advanceToNonWhitespace(buf); len(buf) == 0 {
-			return nil, nil, fmt.Errorf("cannot decode textual enum: %s", io.ErrShortBuffer)
+			return nil, nil, fmt.Errorf("cannot decode textual enum: %w", ErrShortBuffer{})
 		}
 		// decode enum string
 		var value interface{}
 		var err error
 		value, buf, err = stringNativeFromTextual(buf)
 		if err != nil {
-			return nil, nil, fmt.Errorf("cannot decode textual enum: expected key: %s", err)
+			return nil, nil, fmt.Errorf("cannot decode textual enum: expected key: %w", err)
 		}
 		someString := value.(string)
-		for _, symbol := range symbols {
+		for i, symbol := range symbols {
 			if symbol == someString {
-				return someString, buf, nil
+				return c.enumNative(i), buf, nil
 			}
 		}
-		return nil, nil, fmt.Errorf("cannot decode textual enum %q: value ought to be member of symbols: %v; %q", c.typeName, symbols, someString)
+		return nil, nil, fmt.Errorf("cannot decode textual enum %q: %w", c.typeName, newErrEnumSymbol(symbols, someString))
 	}
 	c.textualFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
 		someString := ""
@@ -112,8 +172,64 @@ func makeEnumCodec(st map[string]*Codec, enclosingNamespace string, schemaMap ma
 				return stringTextualFromNative(buf, someString)
 			}
 		}
-		return nil, fmt.Errorf("cannot encode textual enum %q: value ought to be member of symbols: %v; %q", c.typeName, symbols, someString)
+		return nil, fmt.Errorf("cannot encode textual enum %q: %w", c.typeName, newErrEnumSymbol(symbols, someString))
 	}
 
 	return c, nil
 }
+
+func isEnumSymbol(symbols []string, symbol string) bool {
+	for _, s := range symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// EnumSymbols returns the symbols of an enum Codec, in schema order, so the
+// index of a symbol is its ordinal, which is how it is encoded as binary. The
+// second return value is false when the Codec is not an enum. The returned
+// slice is a copy, which the caller may modify.
+func (c *Codec) EnumSymbols() ([]string, bool) {
+	if c.schemaType != "enum" {
+		return nil, false
+	}
+	return append([]string(nil), c.enumSymbols...), true
+}
+
+// EnumOrdinal returns the ordinal of the symbol of an enum Codec. An error is
+// returned when the Codec is not an enum, or the symbol is not one of its
+// symbols.
+func (c *Codec) EnumOrdinal(symbol string) (int, error) {
+	if c.schemaType != "enum" {
+		return 0, fmt.Errorf("cannot find enum ordinal: codec ought to be enum; received: %q", c.typeName)
+	}
+	for i, s := range c.enumSymbols {
+		if s == symbol {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("cannot find enum %q ordinal: %w", c.typeName, newErrEnumSymbol(c.enumSymbols, symbol))
+}
+
+// EnumSymbol returns the symbol of an enum Codec with the ordinal. An error is
+// returned when the Codec is not an enum, or the ordinal is out of range.
+//
+//     codec, err := goavro.NewCodec(`{"type":"enum","name":"e1","symbols":["alpha","bravo","charlie"]}`)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     symbol, err := codec.EnumSymbol(stored)
+//     if err != nil {
+//         fmt.Println(err) // stored ordinal is not valid for the schema
+//     }
+func (c *Codec) EnumSymbol(ordinal int) (string, error) {
+	if c.schemaType != "enum" {
+		return "", fmt.Errorf("cannot find enum symbol: codec ought to be enum; received: %q", c.typeName)
+	}
+	if ordinal < 0 || ordinal >= len(c.enumSymbols) {
+		return "", fmt.Errorf("cannot find enum %q symbol: ordinal ought to be between 0 and %d; received: %d", c.typeName, len(c.enumSymbols)-1, ordinal)
+	}
+	return c.enumSymbols[ordinal], nil
+}
@@ -10,6 +10,7 @@
 package goavro
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -33,10 +34,21 @@ func TestEnumSymbols(t *testing.T) {
 	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":[]}`, `Enum "e1" symbols ought to be non-empty array of strings`)
 }
 
+func TestEnumDefault(t *testing.T) {
+	testSchemaValid(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"],"default":"bravo"}`)
+	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"],"default":3}`, `Enum "e1" default ought to be string; received: float64`)
+	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":["alpha","bravo"],"default":"charlie"}`, `Enum "e1" default ought to be member of symbols: [alpha bravo]; "charlie"`)
+}
+
 func TestEnumSymbolInvalid(t *testing.T) {
-	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":[3]}`, `Enum "e1" symbol 1 ought to be non-empty string`)
+	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":[3]}`, `Enum "e1" symbol 1 ought to be non-empty string; received: float64`)
 	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":[""]}`, `Enum "e1" symbol 1 ought to be non-empty string`)
 	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":["string-with-invalid-characters"]}`, `Enum "e1" symbol 1 ought to have second and remaining`)
+	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":["alpha","bra-vo"]}`, `Enum "e1" symbol 2 ought to have second and remaining characters contain only [A-Za-z0-9_]: bra-vo`)
+	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":["-alpha"]}`, `Enum "e1" symbol 1 ought to start with [A-Za-z_]: -alpha`)
+	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":["9lives"]}`, `Enum "e1" symbol 1 ought to start with [A-Za-z_]: 9lives`)
+	testSchemaValid(t, `{"type":"enum","name":"e1","symbols":["_alpha","Bravo9"]}`)
+	testSchemaInvalid(t, `{"type":"enum","name":"e1","symbols":["a","b","a"]}`, `Enum "e1" symbol 3 ought to be unique: "a"`)
 }
 
 func TestEnumDecodeError(t *testing.T) {
@@ -92,10 +104,9 @@ func TestGH233(t *testing.T) {
 	// conclusion, union is not handling namespaces correctly
 	// try union with record instead of enum (records and enums both have namespaces)
 	// get a basic record going
-	//testTextCodecPass(t, `{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`, map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": nil})}, []byte(`{"next":{"LongList":{"next":null}}}`))
-	// add a namespace to the record
-	// fails in the same way cannot determine codec: "LongList" for key: "next"
-	// testTextCodecPass(t, `{"type":"record","name":"LongList","namespace":"com.foo.bar","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`, map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": nil})}, []byte(`{"next":{"LongList":{"next":null}}}`))
+	testTextCodecPass(t, `{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`, map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": nil})}, []byte(`{"next":{"LongList":{"next":null}}}`))
+	// add a namespace to the record, which the union member is named by
+	testTextCodecPass(t, `{"type":"record","name":"LongList","namespace":"com.foo.bar","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`, map[string]interface{}{"next": Union("com.foo.bar.LongList", map[string]interface{}{"next": nil})}, []byte(`{"next":{"com.foo.bar.LongList":{"next":null}}}`))
 	//
 	// experiments on syntax solutions
 	// testTextCodecPass(t, `["null",{"type":"enum","name":"com.foo.bar.FooBarEvent","symbols":["CREATED","UPDATED"]}]`, Union("com.foo.bar.FooBarEvent", "CREATED"), []byte(`{"FooBarEvent":"CREATED"}`))
@@ -109,6 +120,131 @@ func TestGH233(t *testing.T) {
 
 }
 
+func TestEnumSymbolsAndOrdinals(t *testing.T) {
+	codec, err := NewCodec(`{"type":"enum","name":"e1","symbols":["alpha","bravo","charlie"]}`)
+	ensureError(t, err)
+
+	symbols, ok := codec.EnumSymbols()
+	if actual, expected := fmt.Sprint(symbols, ok), "[alpha bravo charlie] true"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	for ordinal, symbol := range symbols {
+		actualOrdinal, err := codec.EnumOrdinal(symbol)
+		ensureError(t, err)
+		actualSymbol, err := codec.EnumSymbol(ordinal)
+		ensureError(t, err)
+		if actualOrdinal != ordinal || actualSymbol != symbol {
+			t.Errorf("GOT: %d, %q; WANT: %d, %q", actualOrdinal, actualSymbol, ordinal, symbol)
+		}
+		// the ordinal is the binary encoding of the symbol
+		buf, err := codec.BinaryFromNative(nil, symbol)
+		ensureError(t, err)
+		if expected := []byte{byte(ordinal * 2)}; !bytes.Equal(buf, expected) {
+			t.Errorf("GOT: %#v; WANT: %#v", buf, expected)
+		}
+	}
+
+	// modifying the returned slice does not modify the codec
+	symbols[0] = "delta"
+	if buf, err := codec.BinaryFromNative(nil, "alpha"); err != nil || !bytes.Equal(buf, []byte{0}) {
+		t.Errorf("GOT: %#v, %v; WANT: %#v", buf, err, []byte{0})
+	}
+	if symbols, _ = codec.EnumSymbols(); symbols[0] != "alpha" {
+		t.Errorf("GOT: %q; WANT: %q", symbols[0], "alpha")
+	}
+
+	_, err = codec.EnumOrdinal("delta")
+	ensureError(t, err, `cannot find enum "e1" ordinal: value ought to be member of symbols: [alpha bravo charlie]; "delta"`)
+	_, err = codec.EnumSymbol(3)
+	ensureError(t, err, `cannot find enum "e1" symbol: ordinal ought to be between 0 and 2; received: 3`)
+	_, err = codec.EnumSymbol(-1)
+	ensureError(t, err, "received: -1")
+
+	codec, err = NewCodec(`"string"`)
+	ensureError(t, err)
+	if symbols, ok := codec.EnumSymbols(); ok || symbols != nil {
+		t.Errorf("GOT: %v, %v; WANT: [], false", symbols, ok)
+	}
+	_, err = codec.EnumOrdinal("alpha")
+	ensureError(t, err, `codec ought to be enum; received: "string"`)
+	_, err = codec.EnumSymbol(0)
+	ensureError(t, err, `codec ought to be enum; received: "string"`)
+}
+
+func TestEnumValues(t *testing.T) {
+	const schema = `{"type":"record","name":"r1","fields":[{"name":"e","type":{"type":"enum","name":"e1","symbols":["alpha","bravo","charlie"]}}]}`
+
+	// enums decode as strings by default
+	codec, err := NewCodec(schema)
+	ensureError(t, err)
+	native, _, err := codec.NativeFromBinary([]byte{0x02})
+	ensureError(t, err)
+	if actual, ok := native.(map[string]interface{})["e"].(string); !ok || actual != "bravo" {
+		t.Errorf("GOT: %#v; WANT: %q", native, "bravo")
+	}
+
+	codec, err = NewCodecWithOptions(schema, WithEnumValues())
+	ensureError(t, err)
+	native, _, err = codec.NativeFromBinary([]byte{0x02})
+	ensureError(t, err)
+	value, ok := native.(map[string]interface{})["e"].(Enum)
+	if !ok {
+		t.Fatalf("GOT: %T; WANT: goavro.Enum", native.(map[string]interface{})["e"])
+	}
+	if actual, expected := value.Str(), "bravo"; actual != expected {
+		t.Errorf("GOT: %q; WANT: %q", actual, expected)
+	}
+	if actual, expected := value.Ordinal, 1; actual != expected {
+		t.Errorf("GOT: %d; WANT: %d", actual, expected)
+	}
+
+	// decoded values encode back to the same data
+	buf, err := codec.BinaryFromNative(nil, native)
+	ensureError(t, err)
+	if expected := []byte{0x02}; !bytes.Equal(buf, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", buf, expected)
+	}
+	text, err := codec.TextualFromNative(nil, native)
+	ensureError(t, err)
+	if actual, expected := string(text), `{"e":"bravo"}`; actual != expected {
+		t.Errorf("GOT: %s; WANT: %s", actual, expected)
+	}
+	native, _, err = codec.NativeFromTextual([]byte(`{"e":"charlie"}`))
+	ensureError(t, err)
+	if actual, expected := native.(map[string]interface{})["e"], (Enum{Symbol: "charlie", Ordinal: 2}); actual != expected {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+	}
+
+	// they also set string struct fields
+	var out struct {
+		E string `avro:"e"`
+	}
+	ensureError(t, codec.NativeToStruct(native, &out))
+	if actual, expected := out.E, "charlie"; actual != expected {
+		t.Errorf("GOT: %q; WANT: %q", actual, expected)
+	}
+
+	// resolved values use the ordinal of the reader symbol
+	resolved, err := NewCodecForReaderWriter(`{"type":"enum","name":"e1","symbols":["bravo","alpha"],"default":"alpha"}`, `{"type":"enum","name":"e1","symbols":["alpha","bravo","charlie"]}`)
+	ensureError(t, err)
+	resolved, err = resolved.With(WithEnumValues())
+	ensureError(t, err)
+	for _, tc := range []struct {
+		buf      []byte
+		expected Enum
+	}{
+		{[]byte{0x00}, Enum{Symbol: "alpha", Ordinal: 1}},
+		{[]byte{0x02}, Enum{Symbol: "bravo", Ordinal: 0}},
+		{[]byte{0x04}, Enum{Symbol: "alpha", Ordinal: 1}},
+	} {
+		native, _, err := resolved.NativeFromBinary(tc.buf)
+		ensureError(t, err)
+		if native != tc.expected {
+			t.Errorf("GOT: %#v; WANT: %#v", native, tc.expected)
+		}
+	}
+}
+
 func ExampleCheckSolutionGH233() {
 	const avroSchema = `
 	{
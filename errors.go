@@ -0,0 +1,75 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"fmt"
+	"io"
+)
+
+// The errors below are returned wrapped in errors describing the value which
+// could not be encoded or decoded, so callers can branch on the kind of error
+// using errors.As rather than by matching its message.
+//
+//     _, _, err := codec.NativeFromBinary(buf)
+//     var errShort goavro.ErrShortBuffer
+//     if errors.As(err, &errShort) {
+//         // wait for more bytes
+//     }
+
+// ErrShortBuffer is returned when a buffer ends before the value being decoded
+// from it. It unwraps to io.ErrShortBuffer, so errors.Is(err,
+// io.ErrShortBuffer) also holds.
+type ErrShortBuffer struct{}
+
+func (e ErrShortBuffer) Error() string { return io.ErrShortBuffer.Error() }
+
+// Unwrap returns io.ErrShortBuffer.
+func (e ErrShortBuffer) Unwrap() error { return io.ErrShortBuffer }
+
+// ErrUnionNoMatch is returned when a union cannot encode a datum because none of
+// its member schema types support it.
+type ErrUnionNoMatch struct {
+	AllowedTypes []string    // names of the member schema types
+	Datum        interface{} // the datum which could not be encoded
+}
+
+// newErrUnionNoMatch returns an ErrUnionNoMatch holding a copy of the member
+// names of the union, so the caller cannot modify those of the union codec.
+func newErrUnionNoMatch(allowedTypes []string, datum interface{}) ErrUnionNoMatch {
+	return ErrUnionNoMatch{AllowedTypes: append([]string(nil), allowedTypes...), Datum: datum}
+}
+
+func (e ErrUnionNoMatch) Error() string {
+	return fmt.Sprintf("no member schema types support datum: allowed types: %v; received: %T", e.AllowedTypes, e.Datum)
+}
+
+// ErrEnumSymbol is returned when a value is not one of the symbols of an enum.
+type ErrEnumSymbol struct {
+	Symbols []string // symbols of the enum
+	Symbol  string   // the value which is not one of them
+}
+
+// newErrEnumSymbol returns an ErrEnumSymbol holding a copy of the symbols of
+// the enum, so the caller cannot modify those of the enum codec.
+func newErrEnumSymbol(symbols []string, symbol string) ErrEnumSymbol {
+	return ErrEnumSymbol{Symbols: append([]string(nil), symbols...), Symbol: symbol}
+}
+
+func (e ErrEnumSymbol) Error() string {
+	return fmt.Sprintf("value ought to be member of symbols: %v; %q", e.Symbols, e.Symbol)
+}
+
+// ErrRange is returned when a number cannot be converted to the type of the
+// schema, or to the Go type of the native value, without losing precision or
+// overflowing, or when a value is outside the range its logical type allows.
+type ErrRange string
+
+func (e ErrRange) Error() string { return string(e) }
@@ -0,0 +1,116 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestErrShortBuffer(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":{"type":"array","items":"string"}}]}`)
+	ensureError(t, err)
+	buf, err := codec.BinaryFromNative(nil, map[string]interface{}{"a": []interface{}{"some string"}})
+	ensureError(t, err)
+
+	for _, buf := range [][]byte{buf[:0], buf[:3], buf[:len(buf)-1]} {
+		_, _, err = codec.NativeFromBinary(buf)
+		ensureError(t, err, "short buffer")
+		if !errors.As(err, new(ErrShortBuffer)) {
+			t.Errorf("GOT: %v; WANT: ErrShortBuffer", err)
+		}
+		if !errors.Is(err, io.ErrShortBuffer) {
+			t.Errorf("GOT: %v; WANT: io.ErrShortBuffer", err)
+		}
+	}
+
+	_, _, err = codec.NativeFromTextual([]byte(`{"a":[`))
+	if !errors.As(err, new(ErrShortBuffer)) {
+		t.Errorf("GOT: %v; WANT: ErrShortBuffer", err)
+	}
+}
+
+func TestErrUnionNoMatch(t *testing.T) {
+	codec, err := NewCodec(`{"type":"map","values":["int","string"]}`)
+	ensureError(t, err)
+
+	_, err = codec.BinaryFromNative(nil, map[string]interface{}{"a": nil})
+	ensureError(t, err, "cannot encode binary union: no member schema types support datum: allowed types: [int string]; received: <nil>")
+	var errUnion ErrUnionNoMatch
+	if !errors.As(err, &errUnion) {
+		t.Fatalf("GOT: %v; WANT: ErrUnionNoMatch", err)
+	}
+	if actual, expected := errUnion.AllowedTypes, []string{"int", "string"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := errUnion.Datum, interface{}(nil); actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	_, err = codec.TextualFromNative(nil, map[string]interface{}{"a": nil})
+	if !errors.As(err, new(ErrUnionNoMatch)) {
+		t.Errorf("GOT: %v; WANT: ErrUnionNoMatch", err)
+	}
+
+	// modifying the member names of the error does not modify the codec
+	errUnion.AllowedTypes[1] = "long"
+	_, err = codec.BinaryFromNative(nil, map[string]interface{}{"a": nil})
+	ensureError(t, err, "allowed types: [int string]")
+}
+
+func TestErrEnumSymbol(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"e","type":{"type":"enum","name":"e1","symbols":["alpha","bravo"]}}]}`)
+	ensureError(t, err)
+
+	_, err = codec.BinaryFromNative(nil, map[string]interface{}{"e": "charlie"})
+	ensureError(t, err, `value ought to be member of symbols: [alpha bravo]; "charlie"`)
+	var errEnum ErrEnumSymbol
+	if !errors.As(err, &errEnum) {
+		t.Fatalf("GOT: %v; WANT: ErrEnumSymbol", err)
+	}
+	if actual, expected := errEnum.Symbol, "charlie"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	_, _, err = codec.NativeFromTextual([]byte(`{"e":"charlie"}`))
+	if !errors.As(err, new(ErrEnumSymbol)) {
+		t.Errorf("GOT: %v; WANT: ErrEnumSymbol", err)
+	}
+
+	// modifying the symbols of the error does not modify the codec
+	errEnum.Symbols[0] = "charlie"
+	_, err = codec.BinaryFromNative(nil, map[string]interface{}{"e": "charlie"})
+	ensureError(t, err, `value ought to be member of symbols: [alpha bravo]; "charlie"`)
+}
+
+func TestErrRange(t *testing.T) {
+	codec, err := NewCodec(`{"type":"array","items":"int"}`)
+	ensureError(t, err)
+
+	_, err = codec.BinaryFromNative(nil, []interface{}{int64(1) << 40})
+	ensureError(t, err, "cannot encode binary int: provided Go int64 would lose precision: 1099511627776")
+	if !errors.As(err, new(ErrRange)) {
+		t.Errorf("GOT: %v; WANT: ErrRange", err)
+	}
+
+	_, err = codec.TextualFromNative(nil, []interface{}{1.5})
+	if !errors.As(err, new(ErrRange)) {
+		t.Errorf("GOT: %v; WANT: ErrRange", err)
+	}
+
+	// other errors are not ErrRange
+	_, err = codec.BinaryFromNative(nil, []interface{}{"some string"})
+	ensureError(t, err, "cannot encode binary")
+	if errors.As(err, new(ErrRange)) {
+		t.Errorf("GOT: %v; WANT: not ErrRange", err)
+	}
+}
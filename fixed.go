@@ -19,12 +19,14 @@ import (
 func makeFixedCodec(st map[string]*Codec, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
 	c, err := registerNewCodec(st, schemaMap, enclosingNamespace)
 	if err != nil {
-		return nil, fmt.Errorf("Fixed ought to have valid name: %s", err)
+		return nil, fmt.Errorf("Fixed ought to have valid name: %w", err)
 	}
 	size, err := sizeFromSchemaMap(c.typeName, schemaMap)
 	if err != nil {
 		return nil, err
 	}
+	c.schemaType = "fixed"
+	c.fixedSize = size
 
 	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
 		if buflen := uint(len(buf)); size > buflen {
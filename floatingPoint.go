@@ -12,8 +12,8 @@ package goavro
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
-	"io"
 	"math"
 	"strconv"
 )
@@ -23,20 +23,69 @@ const (
 	floatEncodedLength  = 4 // float requires 4 bytes
 )
 
+// WithNonFiniteLiterals returns a CodecOption which makes the textual encoders
+// of float and double values of the Codec write NaN and infinite values as the
+// literals NaN, Infinity, and -Infinity, which some other Avro implementations
+// write, rather than as the JSON values null, 1e999, and -1e999. The literals
+// are not valid JSON, but textual decoders accept either form, as well as the
+// literals written as JSON strings.
+//
+//     codec, err := goavro.NewCodecWithOptions(`"double"`, goavro.WithNonFiniteLiterals())
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     buf, err := codec.TextualFromNative(nil, math.Inf(-1))
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Println(string(buf)) // -Infinity
+func WithNonFiniteLiterals() CodecOption {
+	return func(o *codecOptions) {
+		o.nonFiniteLiterals = true
+	}
+}
+
+// nonFiniteLiteralsCodec returns a copy of the float or double codec c whose
+// textual encoder writes NaN and infinite values as literals.
+func nonFiniteLiteralsCodec(c *Codec) *Codec {
+	cp := *c
+	cp.textualFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
+		var someFloat64 float64
+		switch v := datum.(type) {
+		case float32:
+			someFloat64 = float64(v)
+		case float64:
+			someFloat64 = v
+		default:
+			return c.textualFromNative(buf, datum)
+		}
+		switch {
+		case math.IsNaN(someFloat64):
+			return append(buf, "NaN"...), nil
+		case math.IsInf(someFloat64, 1):
+			return append(buf, "Infinity"...), nil
+		case math.IsInf(someFloat64, -1):
+			return append(buf, "-Infinity"...), nil
+		}
+		return c.textualFromNative(buf, datum)
+	}
+	return &cp
+}
+
 ////////////////////////////////////////
 // Binary Decode
 ////////////////////////////////////////
 
 func doubleNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 	if len(buf) < doubleEncodedLength {
-		return nil, nil, fmt.Errorf("cannot decode binary double: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode binary double: %w", ErrShortBuffer{})
 	}
 	return math.Float64frombits(binary.LittleEndian.Uint64(buf[:doubleEncodedLength])), buf[doubleEncodedLength:], nil
 }
 
 func floatNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 	if len(buf) < floatEncodedLength {
-		return nil, nil, fmt.Errorf("cannot decode binary float: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode binary float: %w", ErrShortBuffer{})
 	}
 	return math.Float32frombits(binary.LittleEndian.Uint32(buf[:floatEncodedLength])), buf[floatEncodedLength:], nil
 }
@@ -54,15 +103,20 @@ func doubleBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 		value = float64(v)
 	case int:
 		if value = float64(v); int(value) != v {
-			return nil, fmt.Errorf("cannot encode binary double: provided Go int would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary double: %w", ErrRange(fmt.Sprintf("provided Go int would lose precision: %d", v)))
 		}
 	case int64:
 		if value = float64(v); int64(value) != v {
-			return nil, fmt.Errorf("cannot encode binary double: provided Go int64 would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary double: %w", ErrRange(fmt.Sprintf("provided Go int64 would lose precision: %d", v)))
 		}
 	case int32:
 		if value = float64(v); int32(value) != v {
-			return nil, fmt.Errorf("cannot encode binary double: provided Go int32 would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary double: %w", ErrRange(fmt.Sprintf("provided Go int32 would lose precision: %d", v)))
+		}
+	case json.Number:
+		var err error
+		if value, err = v.Float64(); err != nil {
+			return nil, fmt.Errorf("cannot encode binary double: %w", err)
 		}
 	default:
 		return nil, fmt.Errorf("cannot encode binary double: expected: Go numeric; received: %T", datum)
@@ -84,16 +138,22 @@ func floatBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 		value = float32(v)
 	case int:
 		if value = float32(v); int(value) != v {
-			return nil, fmt.Errorf("cannot encode binary float: provided Go int would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary float: %w", ErrRange(fmt.Sprintf("provided Go int would lose precision: %d", v)))
 		}
 	case int64:
 		if value = float32(v); int64(value) != v {
-			return nil, fmt.Errorf("cannot encode binary float: provided Go int64 would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary float: %w", ErrRange(fmt.Sprintf("provided Go int64 would lose precision: %d", v)))
 		}
 	case int32:
 		if value = float32(v); int32(value) != v {
-			return nil, fmt.Errorf("cannot encode binary float: provided Go int32 would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary float: %w", ErrRange(fmt.Sprintf("provided Go int32 would lose precision: %d", v)))
 		}
+	case json.Number:
+		someFloat64, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode binary float: %w", err)
+		}
+		value = float32(someFloat64)
 	default:
 		return nil, fmt.Errorf("cannot encode binary float: expected: Go numeric; received: %T", datum)
 	}
@@ -115,21 +175,31 @@ func floatNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 	return floatingTextDecoder(buf, 32)
 }
 
+// nonFiniteTextual holds the textual forms of NaN and infinite values, both the
+// JSON values this package encodes them as by default, and the literals, also
+// as JSON strings, which other implementations encode them as.
+var nonFiniteTextual = []struct {
+	text  []byte
+	value float64
+}{
+	{[]byte("null"), math.NaN()},
+	{[]byte("1e999"), math.Inf(1)},
+	{[]byte("-1e999"), math.Inf(-1)},
+	{[]byte("NaN"), math.NaN()},
+	{[]byte("Infinity"), math.Inf(1)},
+	{[]byte("-Infinity"), math.Inf(-1)},
+	{[]byte(`"NaN"`), math.NaN()},
+	{[]byte(`"Infinity"`), math.Inf(1)},
+	{[]byte(`"-Infinity"`), math.Inf(-1)},
+}
+
 func floatingTextDecoder(buf []byte, bitSize int) (interface{}, []byte, error) {
-	buflen := len(buf)
-	if buflen >= 4 {
-		if bytes.Equal(buf[:4], []byte("null")) {
-			return math.NaN(), buf[4:], nil
-		}
-		if buflen >= 5 {
-			if bytes.Equal(buf[:5], []byte("1e999")) {
-				return math.Inf(1), buf[5:], nil
-			}
-			if buflen >= 6 {
-				if bytes.Equal(buf[:6], []byte("-1e999")) {
-					return math.Inf(-1), buf[6:], nil
-				}
+	for _, nonFinite := range nonFiniteTextual {
+		if bytes.HasPrefix(buf, nonFinite.text) {
+			if bitSize == 32 {
+				return float32(nonFinite.value), buf[len(nonFinite.text):], nil
 			}
+			return nonFinite.value, buf[len(nonFinite.text):], nil
 		}
 	}
 	index, err := numberLength(buf, true) // NOTE: floatAllowed = true
@@ -153,11 +223,11 @@ func numberLength(buf []byte, floatAllowed bool) (int, error) {
 
 	// STATE 0: begin, optional: -
 	if buflen = len(buf); index == buflen {
-		return 0, io.ErrShortBuffer
+		return 0, ErrShortBuffer{}
 	}
 	if buf[index] == '-' {
 		if index++; index == buflen {
-			return 0, io.ErrShortBuffer
+			return 0, ErrShortBuffer{}
 		}
 	}
 	// STATE 1: if 0, goto 2; otherwise if 1-9, goto 3; otherwise bail
@@ -185,7 +255,7 @@ func numberLength(buf []byte, floatAllowed bool) (int, error) {
 		// STATE 2: if ., goto 4; otherwise goto 5
 		if buf[index] == '.' {
 			if index++; index == buflen {
-				return 0, io.ErrShortBuffer
+				return 0, ErrShortBuffer{}
 			}
 			// STATE 4: absorb one or more digits
 			for {
@@ -205,12 +275,12 @@ func numberLength(buf []byte, floatAllowed bool) (int, error) {
 		// STATE 5: if e|e, goto 6; otherwise goto 7
 		if b = buf[index]; b == 'e' || b == 'E' {
 			if index++; index == buflen {
-				return 0, io.ErrShortBuffer
+				return 0, ErrShortBuffer{}
 			}
 			// STATE 6: if -|+, goto 8; otherwise goto 8
 			if b = buf[index]; b == '+' || b == '-' {
 				if index++; index == buflen {
-					return 0, io.ErrShortBuffer
+					return 0, ErrShortBuffer{}
 				}
 			}
 			// STATE 8: absorb one or more digits
@@ -260,18 +330,27 @@ func floatingTextEncoder(buf []byte, datum interface{}, bitSize int) ([]byte, er
 	case int:
 		if someInt64 = int64(v); int(someInt64) != v {
 			if bitSize == 64 {
-				return nil, fmt.Errorf("cannot encode textual double: provided Go int would lose precision: %d", v)
+				return nil, fmt.Errorf("cannot encode textual double: %w", ErrRange(fmt.Sprintf("provided Go int would lose precision: %d", v)))
 			}
-			return nil, fmt.Errorf("cannot encode textual float: provided Go int would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode textual float: %w", ErrRange(fmt.Sprintf("provided Go int would lose precision: %d", v)))
 		}
 	case int64:
 		someInt64 = v
+	case json.Number:
+		var err error
+		isFloat = true
+		if someFloat64, err = v.Float64(); err != nil {
+			if bitSize == 64 {
+				return nil, fmt.Errorf("cannot encode textual double: %w", err)
+			}
+			return nil, fmt.Errorf("cannot encode textual float: %w", err)
+		}
 	case int32:
 		if someInt64 = int64(v); int32(someInt64) != v {
 			if bitSize == 64 {
-				return nil, fmt.Errorf("cannot encode textual double: provided Go int32 would lose precision: %d", v)
+				return nil, fmt.Errorf("cannot encode textual double: %w", ErrRange(fmt.Sprintf("provided Go int32 would lose precision: %d", v)))
 			}
-			return nil, fmt.Errorf("cannot encode textual float: provided Go int32 would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode textual float: %w", ErrRange(fmt.Sprintf("provided Go int32 would lose precision: %d", v)))
 		}
 	default:
 		if bitSize == 64 {
@@ -10,6 +10,9 @@
 package goavro
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"math"
 	"testing"
 )
@@ -73,3 +76,89 @@ func TestPrimitiveFloatText(t *testing.T) {
 	testTextCodecPass(t, `"float"`, math.NaN(), []byte("null"))
 	testTextDecodePass(t, `"float"`, math.Copysign(0, -1), []byte("-0"))
 }
+
+func TestPrimitiveFloatingPointNonFiniteText(t *testing.T) {
+	for _, schema := range []string{`"double"`, `"float"`} {
+		// literals, also as JSON strings, decode by default
+		testTextDecodePass(t, schema, math.NaN(), []byte("NaN"))
+		testTextDecodePass(t, schema, math.Inf(1), []byte("Infinity"))
+		testTextDecodePass(t, schema, math.Inf(-1), []byte("-Infinity"))
+		testTextDecodePass(t, schema, math.NaN(), []byte(`"NaN"`))
+		testTextDecodePass(t, schema, math.Inf(1), []byte(`"Infinity"`))
+		testTextDecodePass(t, schema, math.Inf(-1), []byte(`"-Infinity"`))
+
+		codec, err := NewCodecWithOptions(schema, WithNonFiniteLiterals())
+		ensureError(t, err)
+		for _, tc := range []struct {
+			datum    interface{}
+			expected string
+		}{
+			{math.NaN(), "NaN"},
+			{float32(math.Inf(1)), "Infinity"},
+			{math.Inf(-1), "-Infinity"},
+			{1.5, "1.5"},
+			{3, "3"},
+		} {
+			buf, err := codec.TextualFromNative(nil, tc.datum)
+			ensureError(t, err)
+			if actual := string(buf); actual != tc.expected {
+				t.Errorf("schema: %s; GOT: %s; WANT: %s", schema, actual, tc.expected)
+			}
+			native, _, err := codec.NativeFromTextual(buf)
+			ensureError(t, err)
+			if actual, expected := fmt.Sprint(native), fmt.Sprint(tc.datum); actual != expected {
+				t.Errorf("schema: %s; GOT: %s; WANT: %s", schema, actual, expected)
+			}
+		}
+		native, _, err := codec.NativeFromTextual([]byte("NaN"))
+		ensureError(t, err)
+		if _, ok := native.(float32); ok != (schema == `"float"`) {
+			t.Errorf("schema: %s; GOT: %T", schema, native)
+		}
+	}
+
+	// the literals are written and read within records and unions
+	codec, err := NewCodecWithOptions(`{"type":"record","name":"r1","fields":[{"name":"a","type":"double"},{"name":"b","type":["null","float"]},{"name":"c","type":{"type":"array","items":"double"}}]}`, WithNonFiniteLiterals())
+	ensureError(t, err)
+	buf, err := codec.TextualFromNative(nil, map[string]interface{}{"a": math.Inf(1), "b": Union("float", math.NaN()), "c": []interface{}{math.Inf(-1), 0.5}})
+	ensureError(t, err)
+	if actual, expected := string(buf), `{"a":Infinity,"b":{"float":NaN},"c":[-Infinity,0.5]}`; actual != expected {
+		t.Errorf("GOT: %s; WANT: %s", actual, expected)
+	}
+	native, _, err := codec.NativeFromTextual(buf)
+	ensureError(t, err)
+	binary, err := codec.BinaryFromNative(nil, native)
+	ensureError(t, err)
+	if expected := []byte("\x00\x00\x00\x00\x00\x00\xf0\x7f\x02\x00\x00\xc0\x7f\x04\x00\x00\x00\x00\x00\x00\xf0\xff\x00\x00\x00\x00\x00\x00\xe0?\x00"); !bytes.Equal(binary, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", binary, expected)
+	}
+
+	// a JSON string is decoded by a string member of a standard JSON union,
+	// even when a double member is declared before it
+	for _, schema := range []string{`["null","double","string"]`, `["null","string","double"]`} {
+		codec, err = NewCodecForStandardJSON(schema)
+		ensureError(t, err)
+		for _, text := range []string{"NaN", "Infinity", "-Infinity"} {
+			native, _, err = codec.NativeFromTextual([]byte(`"` + text + `"`))
+			ensureError(t, err)
+			if actual, expected := fmt.Sprintf("%#v", native), fmt.Sprintf("%#v", map[string]interface{}{"string": text}); actual != expected {
+				t.Errorf("schema: %s; GOT: %s; WANT: %s", schema, actual, expected)
+			}
+		}
+	}
+	codec, err = NewCodecForStandardJSON(`["null","double"]`)
+	ensureError(t, err)
+	native, _, err = codec.NativeFromTextual([]byte(`"NaN"`))
+	ensureError(t, err)
+	if value, ok := native.(map[string]interface{})["double"].(float64); !ok || !math.IsNaN(value) {
+		t.Errorf("GOT: %#v; WANT: %#v", native, map[string]interface{}{"double": math.NaN()})
+	}
+}
+
+func TestPrimitiveFloatingPointJSONNumber(t *testing.T) {
+	testBinaryEncodePass(t, `"double"`, json.Number("3.5"), []byte("\x00\x00\x00\x00\x00\x00\f@"))
+	testBinaryEncodePass(t, `"float"`, json.Number("3.5"), []byte("\x00\x00\x60\x40"))
+	testTextEncodePass(t, `"double"`, json.Number("-12.3"), []byte("-12.3"))
+	testTextEncodePass(t, `"float"`, json.Number("19.7"), []byte("19.7"))
+	testBinaryEncodeFail(t, `"double"`, json.Number("three"), "cannot encode binary double: ")
+}
@@ -414,3 +414,46 @@ func TestCrashers_OCF_e2e(t *testing.T) {
 		})
 	}
 }
+
+func FuzzNativeFromBinary(f *testing.F) {
+	var codecs []*Codec
+	for _, schema := range []string{
+		`{"type":"record","name":"r1","fields":[{"name":"s","type":"string"},{"name":"b","type":"bytes"},{"name":"f","type":{"type":"fixed","name":"f1","size":3}},{"name":"e","type":{"type":"enum","name":"e1","symbols":["a","b"]}},{"name":"d","type":"double"},{"name":"n","type":["null","int","float","boolean"]}]}`,
+		`{"type":"array","items":{"type":"map","values":["null","long","string"]}}`,
+		`{"type":"array","items":"null"}`,
+		`{"type":"map","values":{"type":"array","items":"null"}}`,
+		`{"type":"bytes","logicalType":"decimal","precision":9,"scale":2}`,
+		`{"type":"long","logicalType":"timestamp-micros"}`,
+		testSafeLinkedList,
+	} {
+		codec, err := NewCodec(schema)
+		if err != nil {
+			f.Fatal(err)
+		}
+		codecs = append(codecs, codec)
+	}
+
+	f.Add([]byte{})
+	f.Add([]byte{0})
+	f.Add([]byte{2, 2, 2, 2, 0})
+	f.Add([]byte{0xfe, 0xff, 0xff, 0xff, 0x0f, 0})
+	f.Add([]byte{1, 0x7f, 2, 'a', 0})
+	f.Add([]byte("\x0ahello\x04hiabc\x02\x00\x00\x00\x00\x00\x00\x0c\x40\x02\x04"))
+
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		for _, codec := range codecs {
+			ensureNoPanic(t, codec.Schema(), func() {
+				value, remaining, err := codec.NativeFromBinarySafe(buf, DecodeLimits{})
+				if err != nil {
+					if value != nil || len(remaining) != len(buf) {
+						t.Fatalf("GOT: %v, %d bytes remaining; WANT: nil, %d bytes remaining", value, len(remaining), len(buf))
+					}
+					return
+				}
+				if len(remaining) > len(buf) {
+					t.Fatalf("GOT: %d bytes remaining; WANT: at most %d", len(remaining), len(buf))
+				}
+			})
+		}
+	})
+}
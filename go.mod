@@ -2,8 +2,4 @@ module github.com/cpoole/goavro/v2
 
 go 1.18
 
-require (
-	github.com/golang/snappy v0.0.1
-	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826
-	golang.org/x/exp v0.0.0-20220613132600-b0d781184e0d
-)
+require github.com/golang/snappy v0.0.1
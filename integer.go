@@ -10,9 +10,10 @@
 package goavro
 
 import (
+	"encoding/json"
 	"fmt"
-	"io"
 	"math"
+	"reflect"
 	"strconv"
 )
 
@@ -23,6 +24,77 @@ const (
 	longDownShift = uint32(63)
 )
 
+// FloatToIntPolicy selects how the encoders of int and long values of a Codec
+// handle floating point values which are not whole numbers.
+type FloatToIntPolicy int
+
+const (
+	// FloatToIntReject returns an error when encoding a floating point value
+	// which would lose precision. It is the default policy.
+	FloatToIntReject FloatToIntPolicy = iota
+
+	// FloatToIntTruncate encodes a floating point value without its
+	// fractional part.
+	FloatToIntTruncate
+
+	// FloatToIntRound encodes a floating point value rounded to the nearest
+	// integer, rounding half away from zero.
+	FloatToIntRound
+)
+
+// WithFloatToIntPolicy returns a CodecOption which selects how the encoders of
+// int and long values of the Codec handle floating point values, including
+// json.Number values, which are not whole numbers. Values which are not finite,
+// or which are out of range once made whole, are always rejected.
+//
+//     codec, err := goavro.NewCodecWithOptions(`"int"`, goavro.WithFloatToIntPolicy(goavro.FloatToIntRound))
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     buf, err := codec.BinaryFromNative(nil, 3.5)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Printf("%#v", buf) // []byte{0x8}
+func WithFloatToIntPolicy(policy FloatToIntPolicy) CodecOption {
+	return func(o *codecOptions) {
+		o.floatToIntPolicy = policy
+	}
+}
+
+// floatToIntCodec returns a copy of the int or long codec c whose encoders make
+// floating point values whole using policy before encoding them.
+func floatToIntCodec(c *Codec, policy FloatToIntPolicy) *Codec {
+	whole := math.Trunc
+	if policy == FloatToIntRound {
+		whole = math.Round
+	}
+	convert := func(datum interface{}) interface{} {
+		switch v := datum.(type) {
+		case float64:
+			return whole(v)
+		case float32:
+			return whole(float64(v))
+		case json.Number:
+			if _, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+				return v // keep the precision of integers beyond 2^53
+			}
+			if f, err := v.Float64(); err == nil {
+				return whole(f)
+			}
+		}
+		return datum
+	}
+	cp := *c
+	cp.binaryFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
+		return c.binaryFromNative(buf, convert(datum))
+	}
+	cp.textualFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
+		return c.textualFromNative(buf, convert(datum))
+	}
+	return &cp
+}
+
 ////////////////////////////////////////
 // Binary Decode
 ////////////////////////////////////////
@@ -38,7 +110,7 @@ func intNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 		}
 		shift += 7
 	}
-	return nil, nil, io.ErrShortBuffer
+	return nil, nil, ErrShortBuffer{}
 }
 
 func longNativeFromBinary(buf []byte) (interface{}, []byte, error) {
@@ -53,7 +125,7 @@ func longNativeFromBinary(buf []byte) (interface{}, []byte, error) {
 		}
 		shift += 7
 	}
-	return nil, nil, io.ErrShortBuffer
+	return nil, nil, ErrShortBuffer{}
 }
 
 ////////////////////////////////////////
@@ -67,19 +139,41 @@ func intBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 		value = v
 	case int:
 		if value = int32(v); int(value) != v {
-			return nil, fmt.Errorf("cannot encode binary int: provided Go int would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary int: %w", ErrRange(fmt.Sprintf("provided Go int would lose precision: %d", v)))
 		}
 	case int64:
 		if value = int32(v); int64(value) != v {
-			return nil, fmt.Errorf("cannot encode binary int: provided Go int64 would lose precision: %d", v)
+			return nil, fmt.Errorf("cannot encode binary int: %w", ErrRange(fmt.Sprintf("provided Go int64 would lose precision: %d", v)))
 		}
+	case int8:
+		value = int32(v)
+	case int16:
+		value = int32(v)
+	case uint8:
+		value = int32(v)
+	case uint16:
+		value = int32(v)
+	case uint, uint32, uint64:
+		someUint64 := reflect.ValueOf(v).Uint()
+		if someUint64 > math.MaxInt32 {
+			return nil, fmt.Errorf("cannot encode binary int: %w", ErrRange(fmt.Sprintf("provided Go %T would lose precision: %d", v, someUint64)))
+		}
+		value = int32(someUint64)
 	case float64:
 		if value = int32(v); float64(value) != v {
-			return nil, fmt.Errorf("cannot encode binary int: provided Go float64 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode binary int: %w", ErrRange(fmt.Sprintf("provided Go float64 would lose precision: %f", v)))
 		}
 	case float32:
 		if value = int32(v); float32(value) != v {
-			return nil, fmt.Errorf("cannot encode binary int: provided Go float32 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode binary int: %w", ErrRange(fmt.Sprintf("provided Go float32 would lose precision: %f", v)))
+		}
+	case json.Number:
+		someInt64, err := int64FromNumber(v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode binary int: %w", err)
+		}
+		if value = int32(someInt64); int64(value) != someInt64 {
+			return nil, fmt.Errorf("cannot encode binary int: %w", ErrRange(fmt.Sprintf("provided Go json.Number would lose precision: %s", v)))
 		}
 	default:
 		return nil, fmt.Errorf("cannot encode binary int: expected: Go numeric; received: %T", datum)
@@ -97,22 +191,26 @@ func longBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 		value = int64(v)
 	case int32:
 		value = int64(v)
+	case int8:
+		value = int64(v)
+	case int16:
+		value = int64(v)
 	case float64:
 		if value = int64(v); float64(value) != v {
-			return nil, fmt.Errorf("cannot encode binary long: provided Go float64 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode binary long: %w", ErrRange(fmt.Sprintf("provided Go float64 would lose precision: %f", v)))
 		}
 	case float32:
 		if value = int64(v); float32(value) != v {
-			return nil, fmt.Errorf("cannot encode binary long: provided Go float32 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode binary long: %w", ErrRange(fmt.Sprintf("provided Go float32 would lose precision: %f", v)))
 		}
 	case uint:
-		if value > math.MaxInt32 {
-			return nil, fmt.Errorf("cannot encode binary long: uint would overflow")
+		if uint64(v) > math.MaxInt64 {
+			return nil, fmt.Errorf("cannot encode binary long: %w", ErrRange("uint would overflow"))
 		}
 		value = int64(v)
 	case uint64:
-		if v > math.MaxInt32 {
-			return nil, fmt.Errorf("cannot encode binary long: uint would overflow")
+		if v > math.MaxInt64 {
+			return nil, fmt.Errorf("cannot encode binary long: %w", ErrRange("uint would overflow"))
 		}
 		value = int64(v)
 	case uint8:
@@ -121,6 +219,11 @@ func longBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 		value = int64(v)
 	case uint32:
 		value = int64(v)
+	case json.Number:
+		var err error
+		if value, err = int64FromNumber(v); err != nil {
+			return nil, fmt.Errorf("cannot encode binary long: %w", err)
+		}
 	default:
 		return nil, fmt.Errorf("long: expected: Go numeric; received: %T", datum)
 	}
@@ -128,6 +231,34 @@ func longBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 	return integerBinaryEncoder(buf, encoded)
 }
 
+// int64FromNumber returns the integer n represents. It parses n as an integer
+// so values beyond 2^53 keep their precision, and only accepts a number with a
+// fraction or exponent when it is whole.
+func int64FromNumber(n json.Number) (int64, error) {
+	if someInt64, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return someInt64, nil
+	}
+	someFloat64, err := n.Float64()
+	if err != nil {
+		return 0, err
+	}
+	// NOTE: Converting a float64 outside the range of int64 is implementation
+	// defined, so check the range first.
+	if someFloat64 >= -(1<<63) && someFloat64 < 1<<63 {
+		if someInt64 := int64(someFloat64); float64(someInt64) == someFloat64 {
+			return someInt64, nil
+		}
+	}
+	return 0, ErrRange(fmt.Sprintf("provided Go json.Number would lose precision: %s", n))
+}
+
+// appendLong appends the binary encoding of value, like longBinaryFromNative,
+// but without converting value to an interface, which may allocate.
+func appendLong(buf []byte, value int64) []byte {
+	buf, _ = integerBinaryEncoder(buf, (uint64(value)<<1)^uint64(value>>longDownShift))
+	return buf
+}
+
 func integerBinaryEncoder(buf []byte, encoded uint64) ([]byte, error) {
 	// used by both intBinaryEncoder and longBinaryEncoder
 	if encoded == 0 {
@@ -188,6 +319,10 @@ func integerTextEncoder(buf []byte, datum interface{}, bitSize int) ([]byte, err
 	switch v := datum.(type) {
 	case int:
 		someInt64 = int64(v)
+	case int8:
+		someInt64 = int64(v)
+	case int16:
+		someInt64 = int64(v)
 	case int32:
 		someInt64 = int64(v)
 	case int64:
@@ -195,16 +330,36 @@ func integerTextEncoder(buf []byte, datum interface{}, bitSize int) ([]byte, err
 	case float32:
 		if someInt64 = int64(v); float32(someInt64) != v {
 			if bitSize == 64 {
-				return nil, fmt.Errorf("cannot encode textual long: provided Go float32 would lose precision: %f", v)
+				return nil, fmt.Errorf("cannot encode textual long: %w", ErrRange(fmt.Sprintf("provided Go float32 would lose precision: %f", v)))
 			}
-			return nil, fmt.Errorf("cannot encode textual int: provided Go float32 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode textual int: %w", ErrRange(fmt.Sprintf("provided Go float32 would lose precision: %f", v)))
 		}
 	case float64:
 		if someInt64 = int64(v); float64(someInt64) != v {
 			if bitSize == 64 {
-				return nil, fmt.Errorf("cannot encode textual long: provided Go float64 would lose precision: %f", v)
+				return nil, fmt.Errorf("cannot encode textual long: %w", ErrRange(fmt.Sprintf("provided Go float64 would lose precision: %f", v)))
+			}
+			return nil, fmt.Errorf("cannot encode textual int: %w", ErrRange(fmt.Sprintf("provided Go float64 would lose precision: %f", v)))
+		}
+	case uint, uint8, uint16, uint32, uint64:
+		someUint64 := reflect.ValueOf(v).Uint()
+		if someUint64 > math.MaxInt64 {
+			if bitSize == 64 {
+				return nil, fmt.Errorf("cannot encode textual long: %w", ErrRange("uint would overflow"))
 			}
-			return nil, fmt.Errorf("cannot encode textual int: provided Go float64 would lose precision: %f", v)
+			return nil, fmt.Errorf("cannot encode textual int: %w", ErrRange(fmt.Sprintf("provided Go %T would lose precision: %d", v, someUint64)))
+		}
+		someInt64 = int64(someUint64)
+	case json.Number:
+		var err error
+		if someInt64, err = int64FromNumber(v); err == nil && bitSize == 32 && int64(int32(someInt64)) != someInt64 {
+			err = ErrRange(fmt.Sprintf("provided Go json.Number would lose precision: %s", v))
+		}
+		if err != nil {
+			if bitSize == 64 {
+				return nil, fmt.Errorf("cannot encode textual long: %w", err)
+			}
+			return nil, fmt.Errorf("cannot encode textual int: %w", err)
 		}
 	default:
 		if bitSize == 64 {
@@ -212,5 +367,8 @@ func integerTextEncoder(buf []byte, datum interface{}, bitSize int) ([]byte, err
 		}
 		return nil, fmt.Errorf("cannot encode textual int: expected: Go numeric; received: %T", datum)
 	}
+	if bitSize == 32 && int64(int32(someInt64)) != someInt64 {
+		return nil, fmt.Errorf("cannot encode textual int: %w", ErrRange(fmt.Sprintf("provided Go %T would lose precision: %v", datum, datum)))
+	}
 	return strconv.AppendInt(buf, someInt64, 10), nil
 }
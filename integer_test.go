@@ -10,6 +10,10 @@
 package goavro
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
 	"testing"
 )
 
@@ -73,6 +77,24 @@ func TestPrimitiveLongBinary(t *testing.T) {
 	testBinaryCodecPass(t, `"long"`, int64(-5513458701470791632), []byte("\x9f\xdf\x9f\x8f\xc7\xde\xde\x83\x99\x01"))
 }
 
+func TestPrimitiveLongUnsigned(t *testing.T) {
+	// unsigned values encode when they do not exceed math.MaxInt64
+	for _, datum := range []interface{}{uint(5), uint8(5), uint16(5), uint32(5), uint64(5)} {
+		testBinaryEncodePass(t, `"long"`, datum, []byte("\x0a"))
+		testTextEncodePass(t, `"long"`, datum, []byte("5"))
+	}
+	testBinaryEncodePass(t, `"long"`, uint32(math.MaxUint32), []byte("\xfe\xff\xff\xff\x1f"))
+	testBinaryEncodePass(t, `"long"`, uint64(math.MaxInt32+1), []byte("\x80\x80\x80\x80\x10"))
+	testBinaryEncodePass(t, `"long"`, uint64(math.MaxInt64), []byte{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x1})
+	testBinaryEncodePass(t, `"long"`, uint(math.MaxInt64), []byte{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x1})
+	testTextEncodePass(t, `"long"`, uint64(math.MaxInt64), []byte("9223372036854775807"))
+
+	testBinaryEncodeFail(t, `"long"`, uint64(math.MaxInt64+1), "cannot encode binary long: uint would overflow")
+	testBinaryEncodeFail(t, `"long"`, uint64(math.MaxUint64), "cannot encode binary long: uint would overflow")
+	testBinaryEncodeFail(t, `"long"`, uint(math.MaxUint64), "cannot encode binary long: uint would overflow")
+	testTextEncodeFail(t, `"long"`, uint64(math.MaxUint64), "cannot encode textual long: uint would overflow")
+}
+
 func TestPrimitiveLongText(t *testing.T) {
 	testTextDecodeFailShortBuffer(t, `"long"`, []byte(""))
 	testTextDecodeFailShortBuffer(t, `"long"`, []byte("-"))
@@ -83,3 +105,122 @@ func TestPrimitiveLongText(t *testing.T) {
 	testTextDecodePass(t, `"long"`, -0, []byte("-0"))
 	testTextEncodePass(t, `"long"`, -0, []byte("0")) // NOTE: -0 encodes as "0"
 }
+
+func TestPrimitiveIntegerWidths(t *testing.T) {
+	for _, schema := range []string{`"int"`, `"long"`} {
+		for _, datum := range []interface{}{int(-3), int8(-3), int16(-3), int32(-3), int64(-3)} {
+			testBinaryEncodePass(t, schema, datum, []byte("\x05"))
+			testTextEncodePass(t, schema, datum, []byte("-3"))
+		}
+		for _, datum := range []interface{}{uint(3), uint8(3), uint16(3), uint32(3), uint64(3)} {
+			testBinaryEncodePass(t, schema, datum, []byte("\x06"))
+			testTextEncodePass(t, schema, datum, []byte("3"))
+		}
+		testBinaryEncodePass(t, schema, int8(math.MinInt8), []byte("\xff\x01"))
+		testBinaryEncodePass(t, schema, int16(math.MaxInt16), []byte("\xfe\xff\x03"))
+		testBinaryEncodePass(t, schema, uint8(math.MaxUint8), []byte("\xfe\x03"))
+		testBinaryEncodePass(t, schema, uint16(math.MaxUint16), []byte("\xfe\xff\x07"))
+		testBinaryEncodePass(t, schema, uint32(math.MaxInt32), []byte("\xfe\xff\xff\xff\x0f"))
+	}
+
+	// values beyond the range of int are rejected
+	testBinaryEncodeFail(t, `"int"`, uint32(math.MaxInt32+1), "cannot encode binary int: provided Go uint32 would lose precision: 2147483648")
+	testBinaryEncodeFail(t, `"int"`, uint(math.MaxInt32+1), "cannot encode binary int: provided Go uint would lose precision")
+	testBinaryEncodeFail(t, `"int"`, uint64(math.MaxUint64), "cannot encode binary int: provided Go uint64 would lose precision")
+	testTextEncodeFail(t, `"int"`, uint32(math.MaxInt32+1), "cannot encode textual int: provided Go uint32 would lose precision: 2147483648")
+	testTextEncodeFail(t, `"int"`, uint64(math.MaxUint64), "cannot encode textual int: provided Go uint64 would lose precision")
+	testTextEncodeFail(t, `"int"`, int64(math.MinInt32-1), "cannot encode textual int: provided Go int64 would lose precision: -2147483649")
+	testTextEncodeFail(t, `"int"`, 3e10, "cannot encode textual int: provided Go float64 would lose precision")
+	testBinaryEncodePass(t, `"long"`, uint32(math.MaxInt32+1), []byte("\x80\x80\x80\x80\x10"))
+	testTextEncodePass(t, `"long"`, int64(math.MinInt32-1), []byte("-2147483649"))
+}
+
+func TestPrimitiveIntegerJSONNumber(t *testing.T) {
+	// 2^53 + 1 cannot be represented as a float64
+	testBinaryEncodePass(t, `"long"`, json.Number("9007199254740993"), []byte("\x82\x80\x80\x80\x80\x80\x80\x20"))
+	testTextEncodePass(t, `"long"`, json.Number("9007199254740993"), []byte("9007199254740993"))
+	testBinaryEncodePass(t, `"long"`, json.Number("333333333333333"), []byte("\xaa\xd5\xe6\xee\xc6\xca\x97\x01"))
+	testBinaryEncodePass(t, `"long"`, json.Number("-3"), []byte("\x05"))
+	testBinaryEncodePass(t, `"long"`, json.Number("3.0"), []byte("\x06"))
+	testBinaryEncodePass(t, `"int"`, json.Number("-3"), []byte("\x05"))
+	testTextEncodePass(t, `"int"`, json.Number("-3"), []byte("-3"))
+
+	testBinaryEncodeFail(t, `"long"`, json.Number("3.5"), "cannot encode binary long: provided Go json.Number would lose precision: 3.5")
+	testBinaryEncodeFail(t, `"long"`, json.Number("three"), "cannot encode binary long: ")
+	testBinaryEncodeFail(t, `"long"`, json.Number("1e19"), "cannot encode binary long: provided Go json.Number would lose precision: 1e19")
+	testBinaryEncodeFail(t, `"long"`, json.Number("-1e19"), "cannot encode binary long: provided Go json.Number would lose precision: -1e19")
+	testBinaryEncodeFail(t, `"int"`, json.Number("2147483648"), "cannot encode binary int: provided Go json.Number would lose precision: 2147483648")
+	testTextEncodeFail(t, `"int"`, json.Number("2147483648"), "cannot encode textual int: provided Go json.Number would lose precision: 2147483648")
+
+	// a json.Number selects an integer member of a union when it is whole
+	testBinaryEncodePass(t, `["null","long","double"]`, json.Number("9007199254740993"), []byte("\x02\x82\x80\x80\x80\x80\x80\x80\x20"))
+	testBinaryEncodePass(t, `["null","long","double"]`, json.Number("3.5"), []byte("\x04\x00\x00\x00\x00\x00\x00\x0c\x40"))
+	testBinaryEncodePass(t, `["null","long","double"]`, json.Number("3.0"), []byte("\x02\x06"))
+	testBinaryEncodePass(t, `["null","long","double"]`, json.Number("1e3"), []byte("\x02\xd0\x0f"))
+	testBinaryEncodePass(t, `["null","long","double"]`, json.Number("1e19"), []byte("\x04\x00\x3d\x91\x60\xe4\x58\xe1\x43"))
+}
+
+func TestFloatToIntPolicy(t *testing.T) {
+	for _, schema := range []string{`"int"`, `"long"`} {
+		for _, tc := range []struct {
+			policy   FloatToIntPolicy
+			datum    interface{}
+			expected string // textual encoding, or error
+		}{
+			{FloatToIntReject, 3.0, "3"},
+			{FloatToIntReject, 3.5, "would lose precision: 3.500000"},
+			{FloatToIntReject, json.Number("3.5"), "would lose precision: 3.5"},
+			{FloatToIntTruncate, 3.0, "3"},
+			{FloatToIntTruncate, 3.5, "3"},
+			{FloatToIntTruncate, -3.5, "-3"},
+			{FloatToIntTruncate, float32(3.5), "3"},
+			{FloatToIntTruncate, json.Number("3.5"), "3"},
+			{FloatToIntRound, 3.0, "3"},
+			{FloatToIntRound, 3.5, "4"},
+			{FloatToIntRound, -3.5, "-4"},
+			{FloatToIntRound, float32(3.4), "3"},
+			{FloatToIntRound, json.Number("3.5"), "4"},
+			{FloatToIntRound, 7, "7"},
+			{FloatToIntRound, math.NaN(), "would lose precision"},
+			{FloatToIntTruncate, math.Inf(1), "would lose precision"},
+		} {
+			codec, err := NewCodecWithOptions(schema, WithFloatToIntPolicy(tc.policy))
+			ensureError(t, err)
+			text, err := codec.TextualFromNative(nil, tc.datum)
+			if err != nil {
+				ensureError(t, err, tc.expected)
+				_, err = codec.BinaryFromNative(nil, tc.datum)
+				ensureError(t, err, tc.expected)
+				continue
+			}
+			if actual := string(text); actual != tc.expected {
+				t.Errorf("schema: %s; policy: %d; datum: %v; GOT: %s; WANT: %s", schema, tc.policy, tc.datum, actual, tc.expected)
+			}
+			buf, err := codec.BinaryFromNative(nil, tc.datum)
+			ensureError(t, err)
+			native, _, err := codec.NativeFromBinary(buf)
+			ensureError(t, err)
+			if actual := fmt.Sprint(native); actual != tc.expected {
+				t.Errorf("schema: %s; policy: %d; datum: %v; GOT: %s; WANT: %s", schema, tc.policy, tc.datum, actual, tc.expected)
+			}
+		}
+	}
+
+	// the policy applies within unions, and may be changed using With
+	codec, err := NewCodec(`["null","int"]`)
+	ensureError(t, err)
+	floatPtr := 3.5
+	_, err = codec.BinaryFromNative(nil, &floatPtr)
+	ensureError(t, err, "cannot encode binary int: provided Go float64 would lose precision: 3.500000")
+	codec, err = codec.With(WithFloatToIntPolicy(FloatToIntTruncate))
+	ensureError(t, err)
+	buf, err := codec.BinaryFromNative(nil, &floatPtr)
+	ensureError(t, err)
+	if expected := []byte("\x02\x06"); !bytes.Equal(buf, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", buf, expected)
+	}
+	codec, err = codec.With(WithFloatToIntPolicy(FloatToIntReject))
+	ensureError(t, err)
+	_, err = codec.BinaryFromNative(nil, &floatPtr)
+	ensureError(t, err, "would lose precision")
+}
@@ -10,19 +10,22 @@
 package goavro
 
 import (
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
 type toNativeFn func([]byte) (interface{}, []byte, error)
 type fromNativeFn func([]byte, interface{}) ([]byte, error)
 
-var reFromPattern = make(map[string]*regexp.Regexp)
-
 //////////////////////////////////////////////////////////////////////////////////////////////
 // date logical type - to/from time.Time, time.UTC location
 //////////////////////////////////////////////////////////////////////////////////////////////
@@ -55,7 +58,12 @@ func dateFromNative(fn fromNativeFn) fromNativeFn {
 			// reviewing the source code, both functions are based on the internal function unixSec()
 			// unixSec() returns the seconds since unix epoch as int64, whereby Unix() provides the greater range and UnixNano() the higher precision
 			// As a date requires a precision of days Unix() provides more then enough precision and a greater range, including the go zero time
-			numDays := val.Unix() / 86400
+			// Division rounds toward zero, so times before the epoch which are not at midnight UTC are moved back to the start of their day
+			seconds := val.Unix()
+			numDays := seconds / 86400
+			if seconds%86400 < 0 {
+				numDays--
+			}
 			return fn(b, numDays)
 
 		default:
@@ -64,8 +72,31 @@ func dateFromNative(fn fromNativeFn) fromNativeFn {
 	}
 }
 
+// timeOfDayLimit is the exclusive upper bound of time-millis and time-micros
+// values, which represent a time of day after midnight.
+const timeOfDayLimit = 24 * time.Hour
+
+// timeOfDayInRange returns true when the Go numeric value, counting units since
+// midnight, is within a single day.
+func timeOfDayInRange(d interface{}, unit time.Duration) bool {
+	limit := int64(timeOfDayLimit / unit)
+	switch val := d.(type) {
+	case int:
+		return val >= 0 && int64(val) < limit
+	case int32:
+		return val >= 0 && int64(val) < limit
+	case int64:
+		return val >= 0 && val < limit
+	case float32:
+		return val >= 0 && float64(val) < float64(limit)
+	case float64:
+		return val >= 0 && val < float64(limit)
+	}
+	return false
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////
-// time-millis logical type - to/from time.Time, time.UTC location
+// time-millis logical type - to/from time.Duration, within [0, 24h)
 //////////////////////////////////////////////////////////////////////////////////////////////
 func nativeFromTimeMillis(fn toNativeFn) toNativeFn {
 	return func(bytes []byte) (interface{}, []byte, error) {
@@ -78,6 +109,9 @@ func nativeFromTimeMillis(fn toNativeFn) toNativeFn {
 			return l, b, fmt.Errorf("cannot transform to native time.Duration, expected int, received %T", l)
 		}
 		t := time.Duration(i) * time.Millisecond
+		if t < 0 || t >= timeOfDayLimit {
+			return nil, b, ErrRange(fmt.Sprintf("cannot transform to native time.Duration, time-millis out of range [0, 24h): %d", i))
+		}
 		return t, b, nil
 	}
 }
@@ -88,9 +122,15 @@ func timeMillisFromNative(fn fromNativeFn) fromNativeFn {
 		case int, int32, int64, float32, float64:
 			// "Language implementations may choose to represent logical types with an appropriate native type, although this is not required."
 			// especially permitted default values depend on the field's schema type and goavro encodes default values using the field schema
+			if !timeOfDayInRange(val, time.Millisecond) {
+				return nil, ErrRange(fmt.Sprintf("cannot transform to binary time-millis, %T out of range [0, 24h): %v", val, val))
+			}
 			return fn(b, val)
 
 		case time.Duration:
+			if val < 0 || val >= timeOfDayLimit {
+				return nil, ErrRange(fmt.Sprintf("cannot transform to binary time-millis, time.Duration out of range [0, 24h): %s", val))
+			}
 			duration := int32(val.Nanoseconds() / int64(time.Millisecond))
 			return fn(b, duration)
 
@@ -101,7 +141,7 @@ func timeMillisFromNative(fn fromNativeFn) fromNativeFn {
 }
 
 //////////////////////////////////////////////////////////////////////////////////////////////
-// time-micros logical type - to/from time.Time, time.UTC location
+// time-micros logical type - to/from time.Duration, within [0, 24h)
 //////////////////////////////////////////////////////////////////////////////////////////////
 func nativeFromTimeMicros(fn toNativeFn) toNativeFn {
 	return func(bytes []byte) (interface{}, []byte, error) {
@@ -113,8 +153,12 @@ func nativeFromTimeMicros(fn toNativeFn) toNativeFn {
 		if !ok {
 			return l, b, fmt.Errorf("cannot transform to native time.Duration, expected long, received %T", l)
 		}
-		t := time.Duration(i) * time.Microsecond
-		return t, b, nil
+		// NOTE: compare in microseconds, because a large long overflows
+		// time.Duration.
+		if i < 0 || i >= int64(timeOfDayLimit/time.Microsecond) {
+			return nil, b, ErrRange(fmt.Sprintf("cannot transform to native time.Duration, time-micros out of range [0, 24h): %d", i))
+		}
+		return time.Duration(i) * time.Microsecond, b, nil
 	}
 }
 
@@ -124,9 +168,15 @@ func timeMicrosFromNative(fn fromNativeFn) fromNativeFn {
 		case int, int32, int64, float32, float64:
 			// "Language implementations may choose to represent logical types with an appropriate native type, although this is not required."
 			// especially permitted default values depend on the field's schema type and goavro encodes default values using the field schema
+			if !timeOfDayInRange(val, time.Microsecond) {
+				return nil, ErrRange(fmt.Sprintf("cannot transform to binary time-micros, %T out of range [0, 24h): %v", val, val))
+			}
 			return fn(b, val)
 
 		case time.Duration:
+			if val < 0 || val >= timeOfDayLimit {
+				return nil, ErrRange(fmt.Sprintf("cannot transform to binary time-micros, time.Duration out of range [0, 24h): %s", val))
+			}
 			duration := val.Nanoseconds() / int64(time.Microsecond)
 			return fn(b, duration)
 
@@ -220,6 +270,199 @@ func timeStampMicrosFromNative(fn fromNativeFn) fromNativeFn {
 	}
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////
+// uuid logical type - to/from string, canonical lowercase hyphenated form
+//////////////////////////////////////////////////////////////////////////////////////////////
+func nativeFromUUID(fn toNativeFn) toNativeFn {
+	return func(bytes []byte) (interface{}, []byte, error) {
+		l, b, err := fn(bytes)
+		if err != nil {
+			return l, b, err
+		}
+		s, ok := l.(string)
+		if !ok {
+			return l, b, fmt.Errorf("cannot transform to native uuid, expected string, received %T", l)
+		}
+		u, err := parseUUID(s)
+		if err != nil {
+			return nil, b, fmt.Errorf("cannot transform to native uuid: %w", err)
+		}
+		return formatUUID(u), b, nil
+	}
+}
+
+func uuidFromNative(fn fromNativeFn) fromNativeFn {
+	return func(b []byte, d interface{}) ([]byte, error) {
+		switch val := d.(type) {
+		case string:
+			u, err := parseUUID(val)
+			if err != nil {
+				return nil, fmt.Errorf("cannot transform to binary uuid: %w", err)
+			}
+			return fn(b, formatUUID(u))
+
+		case [16]byte:
+			return fn(b, formatUUID(val))
+
+		default:
+			// NOTE: Accept named types whose underlying type is [16]byte, such
+			// as uuid.UUID from the commonly used UUID packages.
+			v := reflect.ValueOf(d)
+			if v.Kind() == reflect.Array && v.Len() == 16 && v.Type().Elem().Kind() == reflect.Uint8 {
+				var u [16]byte
+				for i := range u {
+					u[i] = byte(v.Index(i).Uint())
+				}
+				return fn(b, formatUUID(u))
+			}
+			return nil, fmt.Errorf("cannot transform to binary uuid, expected string or [16]byte, received %T", d)
+		}
+	}
+}
+
+// parseUUID returns the 16 bytes represented by the 36 character hyphenated
+// form of a UUID, as described in RFC-4122. Hexadecimal digits may be either
+// upper or lower case.
+func parseUUID(s string) ([16]byte, error) {
+	var u [16]byte
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("expected hyphenated form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx; received: %q", s)
+	}
+	if _, err := hex.Decode(u[:], []byte(s[0:8]+s[9:13]+s[14:18]+s[19:23]+s[24:36])); err != nil {
+		return u, fmt.Errorf("expected hexadecimal digits; received: %q", s)
+	}
+	return u, nil
+}
+
+// formatUUID returns the canonical lowercase hyphenated form of a UUID.
+func formatUUID(u [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:36], u[10:16])
+	return string(buf[:])
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////
+// duration logical type - fixed of size 12 - to/from Duration
+//////////////////////////////////////////////////////////////////////////////////////////////
+
+// durationSize is the required size of a fixed annotated with the duration
+// logical type.
+const durationSize = 12
+
+// Duration is the native Go form of the Avro duration logical type, which
+// stores an amount of time as three independent unsigned counts of months,
+// days, and milliseconds.
+type Duration struct {
+	Months uint32
+	Days   uint32
+	Millis uint32
+}
+
+func makeDurationFixedCodec(st map[string]*Codec, enclosingNamespace string, schemaMap map[string]interface{}) (*Codec, error) {
+	if _, ok := schemaMap["name"]; !ok {
+		schemaMap["name"] = "fixed.duration"
+	}
+	c, err := makeFixedCodec(st, enclosingNamespace, schemaMap)
+	if err != nil {
+		return nil, err
+	}
+	size, err := sizeFromSchemaMap(c.typeName, schemaMap)
+	if err != nil {
+		return nil, err
+	}
+	if size != durationSize {
+		return nil, fmt.Errorf("cannot create duration logical type when fixed size is not %d: %d", durationSize, size)
+	}
+	c.binaryFromNative = durationFromNative(c.binaryFromNative)
+	c.textualFromNative = durationFromNative(c.textualFromNative)
+	c.nativeFromBinary = nativeFromDuration(c.nativeFromBinary)
+	c.nativeFromTextual = nativeFromDuration(c.nativeFromTextual)
+	return c, nil
+}
+
+func nativeFromDuration(fn toNativeFn) toNativeFn {
+	return func(bytes []byte) (interface{}, []byte, error) {
+		d, b, err := fn(bytes)
+		if err != nil {
+			return d, b, err
+		}
+		bs, ok := d.([]byte)
+		if !ok {
+			return nil, nil, fmt.Errorf("cannot transform to native duration, expected []byte, received %T", d)
+		}
+		return Duration{
+			Months: binary.LittleEndian.Uint32(bs[0:4]),
+			Days:   binary.LittleEndian.Uint32(bs[4:8]),
+			Millis: binary.LittleEndian.Uint32(bs[8:12]),
+		}, b, nil
+	}
+}
+
+func durationFromNative(fn fromNativeFn) fromNativeFn {
+	return func(b []byte, d interface{}) ([]byte, error) {
+		var duration Duration
+		switch val := d.(type) {
+		case Duration:
+			duration = val
+		case map[string]interface{}:
+			var err error
+			if duration.Months, err = durationFieldFromMap(val, "months"); err != nil {
+				return nil, err
+			}
+			if duration.Days, err = durationFieldFromMap(val, "days"); err != nil {
+				return nil, err
+			}
+			if duration.Millis, err = durationFieldFromMap(val, "millis"); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("cannot transform to bytes, expected goavro.Duration or map[string]interface{}, received %T", d)
+		}
+		bs := make([]byte, durationSize)
+		binary.LittleEndian.PutUint32(bs[0:4], duration.Months)
+		binary.LittleEndian.PutUint32(bs[4:8], duration.Days)
+		binary.LittleEndian.PutUint32(bs[8:12], duration.Millis)
+		return fn(b, bs)
+	}
+}
+
+// durationFieldFromMap returns the named field of a duration provided as a
+// map, which must be a Go numeric that fits in an unsigned 32-bit integer. A
+// missing field is treated as zero.
+func durationFieldFromMap(m map[string]interface{}, key string) (uint32, error) {
+	var value int64
+	switch v := m[key].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		value = int64(v)
+	case int32:
+		value = int64(v)
+	case int64:
+		value = v
+	case uint32:
+		return v, nil
+	case float64:
+		if value = int64(v); float64(value) != v {
+			return 0, ErrRange(fmt.Sprintf("cannot transform to bytes, duration %s would lose precision: %f", key, v))
+		}
+	default:
+		return 0, fmt.Errorf("cannot transform to bytes, expected duration %s to be Go numeric, received %T", key, v)
+	}
+	if value < 0 || value > math.MaxUint32 {
+		return 0, ErrRange(fmt.Sprintf("cannot transform to bytes, duration %s out of range [0, %d]: %d", key, uint32(math.MaxUint32), value))
+	}
+	return uint32(value), nil
+}
+
 /////////////////////////////////////////////////////////////////////////////////////////////
 // decimal logical-type - byte/fixed - to/from math/big.Rat
 // two's complement algorithm taken from:
@@ -269,8 +512,9 @@ func makeDecimalBytesCodec(st map[string]*Codec, enclosingNamespace string, sche
 	}
 	c, err := registerNewCodec(st, schemaMap, enclosingNamespace)
 	if err != nil {
-		return nil, fmt.Errorf("Bytes ought to have valid name: %s", err)
+		return nil, fmt.Errorf("Bytes ought to have valid name: %w", err)
 	}
+	c.schemaType = "bytes"
 
 	// Add an additional cached codec for this "bytes.decimal" keyed also by "precision" and "scale"
 	decimalSearchType := fmt.Sprintf("bytes.decimal.%d.%d", precision, scale)
@@ -313,6 +557,10 @@ func decimalBytesFromNative(fromNativeFn fromNativeFn, toBytesFn toBytesFn, prec
 		i := new(big.Int).Mul(num, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil))
 		// divide that by the denominator
 		precnum := new(big.Int).Div(i, denom)
+		// ensure the unscaled value has no more digits than the precision
+		if new(big.Int).Abs(precnum).Cmp(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)) >= 0 {
+			return nil, ErrRange(fmt.Sprintf("cannot transform to bytes, value exceeds decimal precision: %d; received: %s", precision, r.FloatString(scale)))
+		}
 		bout, err := toBytesFn(precnum)
 		if err != nil {
 			return nil, err
@@ -337,6 +585,12 @@ func makeDecimalFixedCodec(st map[string]*Codec, enclosingNamespace string, sche
 	if err != nil {
 		return nil, err
 	}
+	// a fixed of size bytes holds two's complement values of at most
+	// floor(log10(2^(8*size-1)-1)) decimal digits
+	maxValue := new(big.Int).Sub(new(big.Int).Lsh(one, uint(8*size-1)), one)
+	if maxPrecision := len(maxValue.String()) - 1; precision > maxPrecision {
+		return nil, fmt.Errorf("cannot create decimal logical type when precision is larger than fixed size allows: %d > %d", precision, maxPrecision)
+	}
 	c.binaryFromNative = decimalBytesFromNative(c.binaryFromNative, toSignedFixedBytes(size), precision, scale)
 	c.textualFromNative = decimalBytesFromNative(c.textualFromNative, toSignedFixedBytes(size), precision, scale)
 	c.nativeFromBinary = nativeFromDecimalBytes(c.nativeFromBinary, precision, scale)
@@ -351,16 +605,11 @@ func makeValidatedStringCodec(st map[string]*Codec, enclosingNamespace string, s
 	}
 
 	patternStr := strings.TrimSpace(pattern.(string))
-	if reFromPattern[patternStr] == nil {
-		var (
-			regexpr *regexp.Regexp
-			err     error
-		)
-		if regexpr, err = regexp.Compile(patternStr); err != nil {
-			return nil, err
-		}
-
-		reFromPattern[patternStr] = regexpr
+	// NOTE: Each codec keeps its own compiled pattern, rather than sharing a
+	// package level cache, so codecs may be built from concurrent goroutines.
+	regexpr, err := regexp.Compile(patternStr)
+	if err != nil {
+		return nil, err
 	}
 
 	if _, ok := schemaMap["name"]; !ok {
@@ -371,11 +620,12 @@ func makeValidatedStringCodec(st map[string]*Codec, enclosingNamespace string, s
 	if err != nil {
 		return nil, err
 	}
+	c.schemaType = "string"
 
 	c.binaryFromNative = validatedStringBinaryFromNative(c.binaryFromNative)
 	c.textualFromNative = validatedStringTextualFromNative(c.textualFromNative)
-	c.nativeFromBinary = validatedStringNativeFromBinary(c.nativeFromBinary, patternStr)
-	c.nativeFromTextual = validatedStringNativeFromTextual(c.nativeFromTextual, patternStr)
+	c.nativeFromBinary = validatedStringNativeFromBinary(c.nativeFromBinary, regexpr)
+	c.nativeFromTextual = validatedStringNativeFromTextual(c.nativeFromTextual, regexpr)
 	return c, nil
 }
 
@@ -391,7 +641,7 @@ func validatedStringTextualFromNative(fromNativeFn fromNativeFn) fromNativeFn {
 	}
 }
 
-func validatedStringNativeFromBinary(fn toNativeFn, pattern string) toNativeFn {
+func validatedStringNativeFromBinary(fn toNativeFn, pattern *regexp.Regexp) toNativeFn {
 	return func(bytes []byte) (interface{}, []byte, error) {
 		fn, newBytes, err := stringNativeFromBinary(bytes)
 		if err != nil {
@@ -399,7 +649,7 @@ func validatedStringNativeFromBinary(fn toNativeFn, pattern string) toNativeFn {
 		}
 
 		result := fn.(string)
-		if ok := reFromPattern[pattern].MatchString(result); !ok {
+		if ok := pattern.MatchString(result); !ok {
 			return nil, bytes, fmt.Errorf("cannot match input string against validation pattern: %q does not match %q", result, pattern)
 		}
 
@@ -407,7 +657,7 @@ func validatedStringNativeFromBinary(fn toNativeFn, pattern string) toNativeFn {
 	}
 }
 
-func validatedStringNativeFromTextual(fn toNativeFn, pattern string) toNativeFn {
+func validatedStringNativeFromTextual(fn toNativeFn, pattern *regexp.Regexp) toNativeFn {
 	return func(bytes []byte) (interface{}, []byte, error) {
 		fn, newBytes, err := stringNativeFromTextual(bytes)
 		if err != nil {
@@ -415,7 +665,7 @@ func validatedStringNativeFromTextual(fn toNativeFn, pattern string) toNativeFn
 		}
 
 		result := fn.(string)
-		if ok := reFromPattern[pattern].MatchString(result); !ok {
+		if ok := pattern.MatchString(result); !ok {
 			return nil, bytes, fmt.Errorf("cannot match input string against validation pattern: %q does not match %q", result, pattern)
 		}
 
@@ -476,7 +726,7 @@ func toSignedFixedBytes(size uint) func(*big.Int) ([]byte, error) {
 	return func(n *big.Int) ([]byte, error) {
 		switch n.Sign() {
 		case 0:
-			return []byte{0}, nil
+			return make([]byte, size), nil
 		case 1:
 			b := n.Bytes()
 			if b[0]&0x80 > 0 {
@@ -492,3 +742,144 @@ func toSignedFixedBytes(size uint) func(*big.Int) ([]byte, error) {
 		return nil, fmt.Errorf("toSignedBytes: error big.Int.Sign() returned unexpected value")
 	}
 }
+
+// logicalTypeHandler converts between the native values of a base type and
+// those of a logical type registered using RegisterLogicalType.
+type logicalTypeHandler struct {
+	onEncode, onDecode func(interface{}) (interface{}, error)
+}
+
+var (
+	logicalTypeHandlersLock sync.RWMutex
+	logicalTypeHandlers     = make(map[string]*logicalTypeHandler)
+)
+
+// RegisterLogicalType registers a handler for the named logical type, which
+// converts between the native values of the logical type and those of the
+// type it annotates. The Codec of the annotated type encodes and decodes the
+// data as usual, while onEncode converts each value provided to the Codec
+// before it is encoded, and onDecode converts each value the Codec decodes
+// before it is returned. A nil function leaves values unchanged.
+//
+// The handler applies to codecs created after it is registered, and replaces
+// any handler previously registered for the logical type. The logical types
+// this library supports, such as decimal and timestamp-millis, take
+// precedence over a handler registered with the same name for the types they
+// annotate.
+//
+//     goavro.RegisterLogicalType("epoch-seconds",
+//         func(native interface{}) (interface{}, error) {
+//             t, ok := native.(time.Time)
+//             if !ok {
+//                 return nil, fmt.Errorf("expected: time.Time; received: %T", native)
+//             }
+//             return t.Unix(), nil
+//         },
+//         func(native interface{}) (interface{}, error) {
+//             return time.Unix(native.(int64), 0).UTC(), nil
+//         })
+//     codec, err := goavro.NewCodec(`{"type":"long","logicalType":"epoch-seconds"}`)
+func RegisterLogicalType(name string, onEncode, onDecode func(interface{}) (interface{}, error)) {
+	logicalTypeHandlersLock.Lock()
+	logicalTypeHandlers[name] = &logicalTypeHandler{onEncode: onEncode, onDecode: onDecode}
+	logicalTypeHandlersLock.Unlock()
+}
+
+// registeredLogicalType returns the handler registered for the named logical
+// type, or nil when there is none.
+func registeredLogicalType(name string) *logicalTypeHandler {
+	logicalTypeHandlersLock.RLock()
+	h := logicalTypeHandlers[name]
+	logicalTypeHandlersLock.RUnlock()
+	return h
+}
+
+// makeRegisteredLogicalTypeCodec returns a copy of the base Codec that applies
+// the handler to its native values. The base Codec is never modified, because
+// the codecs of primitive types are shared by every schema, and those of named
+// types by every reference to them. When definition is true, base is a named
+// type being defined, which is annotated by replacing it in the symbol table
+// with the copy, so later references to the named type share the annotation.
+func makeRegisteredLogicalTypeCodec(st map[string]*Codec, logicalType string, base *Codec, h *logicalTypeHandler, definition bool) *Codec {
+	copied := *base
+	c := &copied
+	if cd, ok := st[base.typeName.fullName]; ok && cd == base && base.schemaType == "" {
+		c.typeName = &name{base.typeName.fullName + "." + logicalType, nullNamespace}
+	}
+	if definition {
+		// NOTE: Aliases of the named type refer to the same codec.
+		for key, cd := range st {
+			if cd == base {
+				st[key] = c
+			}
+		}
+	}
+	c.annotated, c.logicalType, c.handler = base, logicalType, h
+	if h.onEncode != nil {
+		c.binaryFromNative = registeredLogicalTypeFromNative(c.binaryFromNative, "binary", logicalType, h.onEncode)
+		c.textualFromNative = registeredLogicalTypeFromNative(c.textualFromNative, "textual", logicalType, h.onEncode)
+		if c.textualStandardFromNative != nil {
+			c.textualStandardFromNative = registeredLogicalTypeFromNative(c.textualStandardFromNative, "textual", logicalType, h.onEncode)
+		}
+	}
+	if h.onDecode != nil {
+		c.nativeFromBinary = nativeFromRegisteredLogicalType(c.nativeFromBinary, "binary", logicalType, h.onDecode)
+		c.nativeFromTextual = nativeFromRegisteredLogicalType(c.nativeFromTextual, "textual", logicalType, h.onDecode)
+		if c.nativeFromTextualStandard != nil {
+			c.nativeFromTextualStandard = nativeFromRegisteredLogicalType(c.nativeFromTextualStandard, "textual", logicalType, h.onDecode)
+		}
+		// NOTE: Records are decoded into the provided map without onDecode.
+		c.recordFromBinary = nil
+	}
+	return c
+}
+
+func registeredLogicalTypeFromNative(fn fromNativeFn, encoding, logicalType string, onEncode func(interface{}) (interface{}, error)) fromNativeFn {
+	return func(b []byte, d interface{}) ([]byte, error) {
+		v, err := onEncode(d)
+		if err != nil {
+			return b, fmt.Errorf("cannot encode %s %s: %w", encoding, logicalType, err)
+		}
+		return fn(b, v)
+	}
+}
+
+// registeredDatum returns the value the codec of the type a registered logical
+// type annotates encodes for a native value of the logical type.
+func (c *Codec) registeredDatum(encoding string, datum interface{}) (interface{}, error) {
+	if c.handler.onEncode == nil {
+		return datum, nil
+	}
+	v, err := c.handler.onEncode(datum)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %s %s: %w", encoding, c.logicalType, err)
+	}
+	return v, nil
+}
+
+// registeredNative returns the native value of the registered logical type of
+// the codec for a value decoded by the codec of the type it annotates.
+func (c *Codec) registeredNative(encoding string, value interface{}) (interface{}, error) {
+	if c.handler.onDecode == nil {
+		return value, nil
+	}
+	v, err := c.handler.onDecode(value)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s %s: %w", encoding, c.logicalType, err)
+	}
+	return v, nil
+}
+
+func nativeFromRegisteredLogicalType(fn toNativeFn, encoding, logicalType string, onDecode func(interface{}) (interface{}, error)) toNativeFn {
+	return func(b []byte) (interface{}, []byte, error) {
+		l, b2, err := fn(b)
+		if err != nil {
+			return l, b2, err
+		}
+		v, err := onDecode(l)
+		if err != nil {
+			return nil, b, fmt.Errorf("cannot decode %s %s: %w", encoding, logicalType, err)
+		}
+		return v, b2, nil
+	}
+}
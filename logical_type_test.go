@@ -11,6 +11,7 @@ package goavro
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 	"testing"
 	"time"
@@ -39,6 +40,25 @@ func TestLongLogicalTypeFallback(t *testing.T) {
 	testBinaryCodecPass(t, schema, 12345, []byte("\xf2\xc0\x01"))
 }
 
+func TestLogicalTypeFallbackOnUnsupportedBaseType(t *testing.T) {
+	// known logical types annotating a type they do not support
+	testBinaryCodecPass(t, `{"type": "int", "logicalType": "uuid"}`, 3, []byte("\x06"))
+	testBinaryCodecPass(t, `{"type": "string", "logicalType": "timestamp-millis"}`, "now", []byte("\x06now"))
+	testBinaryCodecPass(t, `{"type": "bytes", "logicalType": "duration"}`, []byte("ab"), []byte("\x04ab"))
+}
+
+func TestNamedTypeLogicalTypeFallback(t *testing.T) {
+	schema := `{"type": "fixed", "name": "f4", "size": 4, "logicalType": "this_logical_type_does_not_exist"}`
+	testSchemaValid(t, schema)
+	testBinaryCodecPass(t, schema, []byte("abcd"), []byte("abcd"))
+}
+
+func TestUnionLogicalTypeFallback(t *testing.T) {
+	schema := `["null", {"type": "string", "logicalType": "this_logical_type_does_not_exist"}]`
+	testStr := "test"
+	testBinaryCodecPass(t, schema, &testStr, []byte("\x02\x08test"))
+}
+
 func TestTimeStampMillisLogicalTypeEncode(t *testing.T) {
 	schema := `{"type": "long", "logicalType": "timestamp-millis"}`
 	testBinaryDecodeFail(t, schema, []byte(""), "short buffer")
@@ -91,6 +111,20 @@ func TestTimeMicrosLogicalTypeEncode(t *testing.T) {
 	testBinaryCodecPass(t, schema, 66904022566*time.Microsecond, []byte("\xcc\xf8\xd2\xbc\xf2\x03"))
 }
 
+func TestTimeMillisLogicalTypeRange(t *testing.T) {
+	schema := `{"type": "int", "logicalType": "time-millis"}`
+	testBinaryCodecPass(t, schema, time.Duration(0), []byte("\x00"))
+	testBinaryCodecPass(t, schema, 12*time.Hour, []byte("\x80\xb8\x99\x29"))
+	testBinaryCodecPass(t, schema, 24*time.Hour-time.Millisecond, []byte("\xfe\xef\xb2\x52"))
+	testBinaryEncodeFail(t, schema, -time.Millisecond, "time.Duration out of range [0, 24h)")
+	testBinaryEncodeFail(t, schema, 24*time.Hour, "time.Duration out of range [0, 24h)")
+	testBinaryDecodeFail(t, schema, []byte("\x01"), "time-millis out of range [0, 24h)")
+	testBinaryEncodeFail(t, schema, -1, "int out of range [0, 24h): -1")
+	testBinaryEncodeFail(t, schema, int64(86400000), "int64 out of range [0, 24h): 86400000")
+	testBinaryEncodeFail(t, schema, float64(86400000), "float64 out of range [0, 24h)")
+	testBinaryDecodeFail(t, schema, []byte("\x80\xf0\xb2\x52"), "time-millis out of range [0, 24h)")
+}
+
 func TestTimeMicrosLogicalTypeUnionEncode(t *testing.T) {
 	schema := `{"type": ["null", {"type": "long", "logicalType": "time-micros"}]}`
 	testStr := "test"
@@ -98,11 +132,78 @@ func TestTimeMicrosLogicalTypeUnionEncode(t *testing.T) {
 	testMicro := 66904022566 * time.Microsecond
 	testBinaryCodecPass(t, schema, &testMicro, []byte("\x02\xcc\xf8\xd2\xbc\xf2\x03"))
 }
+
+func TestTimeMicrosLogicalTypeRange(t *testing.T) {
+	schema := `{"type": "long", "logicalType": "time-micros"}`
+	testBinaryCodecPass(t, schema, time.Duration(0), []byte("\x00"))
+	testBinaryCodecPass(t, schema, 12*time.Hour, []byte("\x80\xc0\xdd\xee\xc1\x02"))
+	testBinaryCodecPass(t, schema, 24*time.Hour-time.Millisecond, []byte("\xb0\xf0\xba\xdd\x83\x05"))
+	testBinaryEncodeFail(t, schema, -time.Microsecond, "time.Duration out of range [0, 24h)")
+	testBinaryEncodeFail(t, schema, 24*time.Hour, "time.Duration out of range [0, 24h)")
+	testBinaryDecodeFail(t, schema, []byte("\x01"), "time-micros out of range [0, 24h)")
+	testBinaryEncodeFail(t, schema, -1, "int out of range [0, 24h): -1")
+	testBinaryEncodeFail(t, schema, int64(86400000000), "int64 out of range [0, 24h): 86400000000")
+	testBinaryEncodeFail(t, schema, float64(86400000000), "float64 out of range [0, 24h)")
+	testBinaryDecodeFail(t, schema, []byte("\x80\x80\xbb\xdd\x83\x05"), "time-micros out of range [0, 24h)")
+}
+
+type testUUID [16]byte
+
+func TestUUIDLogicalTypeEncode(t *testing.T) {
+	schema := `{"type": "string", "logicalType": "uuid"}`
+	u := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	encoded := []byte("\x48123e4567-e89b-12d3-a456-426614174000")
+	testBinaryDecodeFail(t, schema, []byte(""), "short buffer")
+	testBinaryEncodeFail(t, schema, 42, "cannot transform to binary uuid, expected string or [16]byte, received int")
+	testBinaryEncodeFail(t, schema, [15]byte{}, "cannot transform to binary uuid, expected string or [16]byte")
+	testBinaryEncodeFail(t, schema, "not-a-uuid", "cannot transform to binary uuid: expected hyphenated form")
+	testBinaryEncodeFail(t, schema, "123e4567-e89b-12d3-a456-42661417400g", "cannot transform to binary uuid: expected hexadecimal digits")
+	testBinaryEncodeFail(t, schema, "123e4567e89b12d3a456426614174000", "cannot transform to binary uuid: expected hyphenated form")
+	testBinaryDecodeFail(t, schema, []byte("\x14not-a-uuid"), "cannot transform to native uuid: expected hyphenated form")
+	testBinaryCodecPass(t, schema, "123e4567-e89b-12d3-a456-426614174000", encoded)
+	testBinaryEncodePass(t, schema, "123E4567-E89B-12D3-A456-426614174000", encoded)
+	testBinaryEncodePass(t, schema, u, encoded)
+	testBinaryEncodePass(t, schema, testUUID(u), encoded)
+	testTextEncodePass(t, schema, u, []byte(`"123e4567-e89b-12d3-a456-426614174000"`))
+}
+
+func TestUUIDLogicalTypeDecodeLowercase(t *testing.T) {
+	codec, err := NewCodec(`{"type": "string", "logicalType": "uuid"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, _, err := codec.NativeFromBinary([]byte("\x48123E4567-E89B-12D3-A456-426614174000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := value, "123e4567-e89b-12d3-a456-426614174000"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
+
+func TestUUIDLogicalTypeUnionEncode(t *testing.T) {
+	schema := `{"type": ["null", {"type": "string", "logicalType": "uuid"}]}`
+	testStr := "test"
+	testBinaryEncodeFail(t, schema, &testStr, "cannot transform to binary uuid: expected hyphenated form")
+	testValue := "123e4567-e89b-12d3-a456-426614174000"
+	testBinaryCodecPass(t, schema, &testValue, []byte("\x02\x48123e4567-e89b-12d3-a456-426614174000"))
+}
+
 func TestDateLogicalTypeEncode(t *testing.T) {
 	schema := `{"type": "int", "logicalType": "date"}`
 	testBinaryDecodeFail(t, schema, []byte(""), "short buffer")
 	testBinaryEncodeFail(t, schema, "test", "cannot transform to binary date, expected time.Time or Go numeric, received string")
 	testBinaryCodecPass(t, schema, time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC), []byte("\xbc\xcd\x01"))
+	testBinaryCodecPass(t, schema, time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), []byte("\x00"))
+	testBinaryCodecPass(t, schema, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), []byte("\x01"))
+	testBinaryCodecPass(t, schema, time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), []byte("\xbd\x8f\x03"))
+	testBinaryCodecPass(t, schema, time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), []byte("\xc0\x82\xe6\x02"))
+
+	// times of day are truncated to the start of their UTC date
+	testBinaryEncodePass(t, schema, time.Date(1970, 1, 1, 23, 59, 59, 999999999, time.UTC), []byte("\x00"))
+	testBinaryEncodePass(t, schema, time.Date(1969, 12, 31, 12, 0, 0, 0, time.UTC), []byte("\x01"))
+	testBinaryEncodePass(t, schema, time.Date(1969, 12, 31, 23, 59, 59, 999999999, time.UTC), []byte("\x01"))
+	testBinaryEncodePass(t, schema, time.Date(1970, 1, 1, 1, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)), []byte("\x01"))
 }
 
 func testGoZeroTime(t *testing.T, schema string, expected []byte) {
@@ -201,19 +302,112 @@ func TestDecimalFixedLogicalTypeEncode(t *testing.T) {
 	//ratHelper(t, schema0scale, big.NewRat(617, 50), []byte("\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x0c"))
 	ratHelper(t, schema0scale, big.NewRat(12, 1), []byte("\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x0c"))
 
+	schemaPrecision3 := `{"type": "fixed", "size": 4, "logicalType": "decimal", "precision": 3, "scale": 1}`
+	ratHelper(t, schemaPrecision3, big.NewRat(163, 10), []byte("\x00\x00\x00\xa3"))
+	ratHelper(t, schemaPrecision3, big.NewRat(-130, 4), []byte("\xff\xff\xfe\xbb"))
+	ratHelper(t, schemaPrecision3, big.NewRat(25, 2), []byte("\x00\x00\x00\x7d"))
+	//ratHelper(t, schemaPrecision3, big.NewRat(math.MaxInt32, -1), "datum size ought to equal schema size")
+}
+
+func TestDecimalLogicalTypeExceedsPrecision(t *testing.T) {
 	schemaPrecision1 := `{"type": "fixed", "size": 4, "logicalType": "decimal", "precision": 1, "scale": 1}`
-	ratHelper(t, schemaPrecision1, big.NewRat(163, 10), []byte("\x00\x00\x00\xa3"))
-	ratHelper(t, schemaPrecision1, big.NewRat(-130, 4), []byte("\xff\xff\xfe\xbb"))
-	ratHelper(t, schemaPrecision1, big.NewRat(25, 2), []byte("\x00\x00\x00\x7d"))
-	//ratHelper(t, schemaPrecision1, big.NewRat(math.MaxInt32, -1), "datum size ought to equal schema size")
+	testBinaryEncodeFail(t, schemaPrecision1, big.NewRat(163, 10), "value exceeds decimal precision: 1")
+	testBinaryEncodeFail(t, schemaPrecision1, big.NewRat(-130, 4), "value exceeds decimal precision: 1")
+	testBinaryEncodeFail(t, schemaPrecision1, big.NewRat(1, 1), "value exceeds decimal precision: 1")
+	testBinaryEncodePass(t, schemaPrecision1, big.NewRat(9, 10), []byte("\x00\x00\x00\x09"))
+	testBinaryEncodePass(t, schemaPrecision1, big.NewRat(-9, 10), []byte("\xff\xff\xff\xf7"))
+
+	schema := `{"type": "bytes", "logicalType": "decimal", "precision": 4, "scale": 2}`
+	testBinaryEncodeFail(t, schema, big.NewRat(10000, 100), "value exceeds decimal precision: 4")
+	testBinaryEncodeFail(t, schema, big.NewRat(-10000, 100), "value exceeds decimal precision: 4")
+	ratHelper(t, schema, big.NewRat(9999, 100), []byte("\x04\x27\x0f"))
+	ratHelper(t, schema, big.NewRat(-9999, 100), []byte("\x04\xd8\xf1"))
 }
 
-// TODO: fix this
-//func TestDecimalBytesLogicalTypeInRecordEncode(t *testing.T) {
-//	schema := `{"type": "record", "name": "myrecord", "fields" : [
-//	       {"name": "mydecimal", "type": "bytes", "logicalType": "decimal", "precision": 4, "scale": 2}]}`
-//	testBinaryCodecPass(t, schema, map[string]interface{}{"mydecimal": big.NewRat(617, 50)}, []byte("\x04\x04\xd2"))
-//}
+func TestDurationLogicalTypeEncode(t *testing.T) {
+	schema := `{"type": "fixed", "name": "interval", "size": 12, "logicalType": "duration"}`
+	encoded := []byte("\x01\x00\x00\x00\x02\x01\x00\x00\xff\xff\xff\xff")
+	testBinaryDecodeFail(t, schema, []byte("\x01\x00\x00\x00"), "short buffer")
+	testBinaryEncodeFail(t, schema, "test", "cannot transform to bytes, expected goavro.Duration or map[string]interface{}, received string")
+	testBinaryEncodeFail(t, schema, map[string]interface{}{"months": -1}, "duration months out of range")
+	testBinaryEncodeFail(t, schema, map[string]interface{}{"days": int64(math.MaxUint32) + 1}, "duration days out of range")
+	testBinaryEncodeFail(t, schema, map[string]interface{}{"millis": "1"}, "expected duration millis to be Go numeric")
+	testBinaryCodecPass(t, schema, Duration{}, make([]byte, 12))
+	testBinaryCodecPass(t, schema, Duration{Months: 1, Days: 258, Millis: math.MaxUint32}, encoded)
+	testBinaryEncodePass(t, schema, map[string]interface{}{"months": 1, "days": int64(258), "millis": float64(math.MaxUint32)}, encoded)
+	testBinaryEncodePass(t, schema, map[string]interface{}{"months": int32(1)}, []byte("\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"))
+}
+
+func TestDurationLogicalTypeRoundTrip(t *testing.T) {
+	codec, err := NewCodec(`{"type": "fixed", "name": "interval", "size": 12, "logicalType": "duration"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum := Duration{Months: 14, Days: 3, Millis: 7200000}
+	for _, tc := range []struct {
+		encode func([]byte, interface{}) ([]byte, error)
+		decode func([]byte) (interface{}, []byte, error)
+	}{
+		{codec.BinaryFromNative, codec.NativeFromBinary},
+		{codec.TextualFromNative, codec.NativeFromTextual},
+	} {
+		buf, err := tc.encode(nil, datum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, _, err := tc.decode(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual, expected := value, datum; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	}
+}
+
+func TestDurationLogicalTypeSize(t *testing.T) {
+	testSchemaInvalid(t, `{"type": "fixed", "name": "interval", "size": 8, "logicalType": "duration"}`, "cannot create duration logical type when fixed size is not 12: 8")
+}
+
+func TestDecimalFixedLogicalTypePrecisionExceedsSize(t *testing.T) {
+	testSchemaInvalid(t, `{"type": "fixed", "name": "d1", "size": 1, "logicalType": "decimal", "precision": 3}`, "precision is larger than fixed size allows: 3 > 2")
+	testSchemaInvalid(t, `{"type": "fixed", "name": "d16", "size": 16, "logicalType": "decimal", "precision": 39}`, "precision is larger than fixed size allows: 39 > 38")
+	testSchemaValid(t, `{"type": "fixed", "name": "d16", "size": 16, "logicalType": "decimal", "precision": 38}`)
+}
+
+func TestDecimalLogicalTypeRoundTrip(t *testing.T) {
+	for _, scale := range []int{0, 2, 5} {
+		for _, num := range []int64{0, 1, -1, 123456, -123456} {
+			datum := new(big.Rat).SetFrac(big.NewInt(num), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil))
+			for _, schema := range []string{
+				fmt.Sprintf(`{"type": "bytes", "logicalType": "decimal", "precision": 9, "scale": %d}`, scale),
+				fmt.Sprintf(`{"type": "fixed", "name": "f1", "size": 8, "logicalType": "decimal", "precision": 9, "scale": %d}`, scale),
+			} {
+				codec, err := NewCodec(schema)
+				if err != nil {
+					t.Fatal(err)
+				}
+				buf, err := codec.BinaryFromNative(nil, datum)
+				if err != nil {
+					t.Fatalf("schema: %s; datum: %s; %s", schema, datum, err)
+				}
+				value, _, err := codec.NativeFromBinary(buf)
+				if err != nil {
+					t.Fatalf("schema: %s; datum: %s; %s", schema, datum, err)
+				}
+				if value.(*big.Rat).Cmp(datum) != 0 {
+					t.Errorf("schema: %s; GOT: %s; WANT: %s", schema, value, datum)
+				}
+			}
+		}
+	}
+}
+
+func TestDecimalBytesLogicalTypeInRecordEncode(t *testing.T) {
+	schema := `{"type": "record", "name": "myrecord", "fields" : [
+	       {"name": "mydecimal", "type": "bytes", "logicalType": "decimal", "precision": 4, "scale": 2}]}`
+	testBinaryEncodePass(t, schema, map[string]interface{}{"mydecimal": big.NewRat(617, 50)}, []byte("\x04\x04\xd2"))
+}
 
 func TestValidatedStringLogicalTypeInRecordEncode(t *testing.T) {
 	schema := `{
@@ -282,6 +476,8 @@ func ExampleUnion_logicalType() {
 	// * time-micros      - time.Duration
 	// * date             - int
 	// * decimal          - big.Rat
+	// * uuid             - string
+	// * duration         - goavro.Duration
 	codec, err := NewCodec(`["null", {"type": "long", "logicalType": "timestamp-millis"}]`)
 	if err != nil {
 		fmt.Println(err)
@@ -304,6 +500,82 @@ func ExampleUnion_logicalType() {
 	// Output: "2006-01-02 15:04:05 +0000 UTC"
 }
 
+func TestRegisterLogicalType(t *testing.T) {
+	RegisterLogicalType("test-celsius",
+		func(native interface{}) (interface{}, error) {
+			c, ok := native.(float64)
+			if !ok {
+				return nil, fmt.Errorf("expected: float64; received: %T", native)
+			}
+			return int32(math.Round(c * 10)), nil
+		},
+		func(native interface{}) (interface{}, error) {
+			return float64(native.(int32)) / 10, nil
+		})
+
+	schema := `{"type":"int","logicalType":"test-celsius"}`
+	testBinaryCodecPass(t, schema, 21.5, []byte{0xae, 0x03})
+	testTextCodecPass(t, schema, -4.5, []byte("-45"))
+	testBinaryEncodeFail(t, schema, "hot", "cannot encode binary test-celsius: expected: float64; received: string")
+
+	// the shared codec of the base type is unchanged
+	testBinaryCodecPass(t, `"int"`, 215, []byte{0xae, 0x03})
+
+	// the logical type names its union member like other logical types
+	testBinaryCodecPass(t, `["null","string",`+schema+`]`, Union("int.test-celsius", 21.5), []byte{0x04, 0xae, 0x03})
+
+	// named types are annotated, including where they are referred to
+	RegisterLogicalType("test-hex", nil, func(native interface{}) (interface{}, error) {
+		return fmt.Sprintf("%x", native), nil
+	})
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":{"type":"fixed","name":"f1","size":2,"logicalType":"test-hex"}},{"name":"b","type":"f1"}]}`)
+	ensureError(t, err)
+	datum, _, err := codec.NativeFromBinary([]byte{0x01, 0x02, 0xab, 0xcd})
+	ensureError(t, err)
+	if actual, expected := fmt.Sprint(datum), "map[a:0102 b:abcd]"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	// annotated references do not annotate the named type they refer to, nor
+	// one another
+	codec, err = NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f","type":{"type":"fixed","name":"f1","size":2}},{"name":"a","type":{"type":"f1","logicalType":"test-hex"}},{"name":"b","type":{"type":"f1","logicalType":"test-hex"}},{"name":"c","type":"f1"}]}`)
+	ensureError(t, err)
+	datum, _, err = codec.NativeFromBinary([]byte{0x01, 0x02, 0x01, 0x02, 0xab, 0xcd, 0xab, 0xcd})
+	ensureError(t, err)
+	if actual, expected := fmt.Sprint(datum), "map[a:0102 b:abcd c:[171 205] f:[1 2]]"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
+
+func ExampleRegisterLogicalType() {
+	RegisterLogicalType("epoch-seconds",
+		func(native interface{}) (interface{}, error) {
+			t, ok := native.(time.Time)
+			if !ok {
+				return nil, fmt.Errorf("expected: time.Time; received: %T", native)
+			}
+			return t.Unix(), nil
+		},
+		func(native interface{}) (interface{}, error) {
+			return time.Unix(native.(int64), 0).UTC(), nil
+		})
+
+	codec, err := NewCodec(`{"type":"long","logicalType":"epoch-seconds"}`)
+	if err != nil {
+		fmt.Println(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC))
+	if err != nil {
+		fmt.Println(err)
+	}
+	native, _, err := codec.NativeFromBinary(buf)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(native)
+	// Output: 2006-01-02 15:04:05 +0000 UTC
+}
+
 func TestPrecisionAndScaleFromSchemaMapValidation(t *testing.T) {
 	testCasesInvalid := []struct {
 		schemaMap map[string]interface{}
@@ -12,11 +12,31 @@ package goavro
 import (
 	"errors"
 	"fmt"
-	"io"
 	"math"
 	"reflect"
+	"sort"
 )
 
+// WithSortedMapKeys returns a CodecOption which makes the map encoders of the
+// Codec encode keys in lexicographic order rather than in the random order Go
+// ranges over maps, so the same map always encodes to the same bytes, at the
+// cost of sorting its keys.
+//
+//     codec, err := goavro.NewCodecWithOptions(`{"type":"map","values":"int"}`, goavro.WithSortedMapKeys())
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     buf, err := codec.TextualFromNative(nil, map[string]interface{}{"b": 2, "a": 1})
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Println(string(buf)) // {"a":1,"b":2}
+func WithSortedMapKeys() CodecOption {
+	return func(o *codecOptions) {
+		o.sortMapKeys = true
+	}
+}
+
 func makeMapCodec(st map[string]*Codec, namespace string, schemaMap map[string]interface{}, cb *codecBuilder) (*Codec, error) {
 	// map type must have values
 	valueSchema, ok := schemaMap["values"]
@@ -25,128 +45,161 @@ func makeMapCodec(st map[string]*Codec, namespace string, schemaMap map[string]i
 	}
 	valueCodec, err := buildCodec(st, namespace, valueSchema, cb)
 	if err != nil {
-		return nil, fmt.Errorf("Map values ought to be valid Avro type: %s", err)
+		return nil, fmt.Errorf("Map values ought to be valid Avro type: %w", err)
 	}
 
-	return &Codec{
-		typeName: &name{"map", nullNamespace},
-		nativeFromBinary: func(buf []byte) (interface{}, []byte, error) {
-			var err error
-			var value interface{}
+	c := &Codec{
+		typeName:  &name{"map", nullNamespace},
+		itemCodec: valueCodec,
+		nativeFromTextual: func(buf []byte) (interface{}, []byte, error) {
+			return genericMapTextDecoder(buf, valueCodec, nil) // codecFromKey == nil
+		},
+		nativeFromTextualStandard: func(buf []byte) (interface{}, []byte, error) {
+			return genericMapTextKeysDecoder(buf, valueCodec, nil, nil, true)
+		},
+	}
+	c.binaryFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
+		mapValues, err := convertMap(datum)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode binary map: %w", err)
+		}
 
-			// block count and block size
-			if value, buf, err = longNativeFromBinary(buf); err != nil {
-				return nil, nil, fmt.Errorf("cannot decode binary map block count: %s", err)
-			}
-			blockCount := value.(int64)
-			if blockCount < 0 {
-				// NOTE: A negative block count implies there is a long encoded
-				// block size following the negative block count. We have no use
-				// for the block size in this decoder, so we read and discard
-				// the value.
-				if blockCount == math.MinInt64 {
-					// The minimum number for any signed numerical type can
-					// never be made positive
-					return nil, nil, fmt.Errorf("cannot decode binary map with block count: %d", blockCount)
-				}
-				blockCount = -blockCount // convert to its positive equivalent
-				if _, buf, err = longNativeFromBinary(buf); err != nil {
-					return nil, nil, fmt.Errorf("cannot decode binary map block size: %s", err)
-				}
-			}
-			// Ensure block count does not exceed some sane value.
-			if blockCount > MaxBlockCount {
-				return nil, nil, fmt.Errorf("cannot decode binary map when block count exceeds MaxBlockCount: %d > %d", blockCount, MaxBlockCount)
-			}
-			// NOTE: While the attempt of a RAM optimization shown below is not
-			// necessary, many encoders will encode all items in a single block.
-			// We can optimize amount of RAM allocated by runtime for the array
-			// by initializing the array for that number of items.
-			mapValues := make(map[string]interface{}, blockCount)
+		keyCount := int64(len(mapValues))
+		var alreadyEncoded, remainingInBlock int64
 
-			for blockCount != 0 {
-				// Decode `blockCount` datum values from buffer
-				for i := int64(0); i < blockCount; i++ {
-					// first decode the key string
-					if value, buf, err = stringNativeFromBinary(buf); err != nil {
-						return nil, nil, fmt.Errorf("cannot decode binary map key: %s", err)
-					}
-					key := value.(string) // string decoder always returns a string
-					if _, ok := mapValues[key]; ok {
-						return nil, nil, fmt.Errorf("cannot decode binary map: duplicate key: %q", key)
-					}
-					// then decode the value
-					if value, buf, err = valueCodec.nativeFromBinary(buf); err != nil {
-						return nil, nil, fmt.Errorf("cannot decode binary map value for key %q: %s", key, err)
-					}
-					mapValues[key] = value
+		for _, k := range mapKeys(mapValues, c.sortMapKeys) {
+			v := mapValues[k]
+			if remainingInBlock == 0 { // start a new block
+				remainingInBlock = keyCount - alreadyEncoded
+				if remainingInBlock > MaxBlockCount {
+					// limit block count to MacBlockCount
+					remainingInBlock = MaxBlockCount
 				}
-				// Decode next blockCount from buffer, because there may be more blocks
-				if value, buf, err = longNativeFromBinary(buf); err != nil {
-					return nil, nil, fmt.Errorf("cannot decode binary map block count: %s", err)
-				}
-				blockCount = value.(int64)
-				if blockCount < 0 {
-					// NOTE: A negative block count implies there is a long
-					// encoded block size following the negative block count. We
-					// have no use for the block size in this decoder, so we
-					// read and discard the value.
-					if blockCount == math.MinInt64 {
-						// The minimum number for any signed numerical type can
-						// never be made positive
-						return nil, nil, fmt.Errorf("cannot decode binary map with block count: %d", blockCount)
-					}
-					blockCount = -blockCount // convert to its positive equivalent
-					if _, buf, err = longNativeFromBinary(buf); err != nil {
-						return nil, nil, fmt.Errorf("cannot decode binary map block size: %s", err)
-					}
-				}
-				// Ensure block count does not exceed some sane value.
-				if blockCount > MaxBlockCount {
-					return nil, nil, fmt.Errorf("cannot decode binary map when block count exceeds MaxBlockCount: %d > %d", blockCount, MaxBlockCount)
-				}
-			}
-			return mapValues, buf, nil
-		},
-		binaryFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
-			mapValues, err := convertMap(datum)
-			if err != nil {
-				return nil, fmt.Errorf("cannot encode binary map: %s", err)
+				buf, _ = longBinaryFromNative(buf, remainingInBlock)
 			}
 
-			keyCount := int64(len(mapValues))
-			var alreadyEncoded, remainingInBlock int64
+			// only fails when given non string, so elide error checking
+			buf, _ = stringBinaryFromNative(buf, k)
 
-			for k, v := range mapValues {
-				if remainingInBlock == 0 { // start a new block
-					remainingInBlock = keyCount - alreadyEncoded
-					if remainingInBlock > MaxBlockCount {
-						// limit block count to MacBlockCount
-						remainingInBlock = MaxBlockCount
-					}
-					buf, _ = longBinaryFromNative(buf, remainingInBlock)
-				}
+			// encode the value
+			if buf, err = valueCodec.binaryFromNative(buf, v); err != nil {
+				return nil, fmt.Errorf("cannot encode binary map value for key %q: %v: %w", k, v, err)
+			}
 
-				// only fails when given non string, so elide error checking
-				buf, _ = stringBinaryFromNative(buf, k)
+			remainingInBlock--
+			alreadyEncoded++
+		}
+		return longBinaryFromNative(buf, 0) // append tailing 0 block count to signal end of Map
+	}
+	c.textualFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
+		return genericMapTextEncoder(buf, datum, valueCodec, nil, false, c.sortMapKeys)
+	}
+	c.textualStandardFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
+		return genericMapTextEncoder(buf, datum, valueCodec, nil, true, c.sortMapKeys)
+	}
+	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
+		return genericMapBinaryDecoder(buf, valueCodec.nativeFromBinary, c.decodeLimits)
+	}
+	return c, nil
+}
 
-				// encode the value
-				if buf, err = valueCodec.binaryFromNative(buf, v); err != nil {
-					return nil, fmt.Errorf("cannot encode binary map value for key %q: %v: %s", k, v, err)
-				}
+// genericMapBinaryDecoder decodes the blocks of a binary map, using
+// valueNativeFromBinary to decode each value, and rejecting blocks exceeding
+// limits.
+func genericMapBinaryDecoder(buf []byte, valueNativeFromBinary func([]byte) (interface{}, []byte, error), limits decodeLimits) (interface{}, []byte, error) {
+	var err error
+	var value interface{}
+	start := len(buf)
 
-				remainingInBlock--
-				alreadyEncoded++
+	// block count and block size
+	if value, buf, err = longNativeFromBinary(buf); err != nil {
+		return nil, nil, fmt.Errorf("cannot decode binary map block count: %w", err)
+	}
+	blockCount := value.(int64)
+	if blockCount < 0 {
+		// NOTE: A negative block count implies there is a long encoded
+		// block size following the negative block count. This decoder
+		// only uses the block size to check it does not exceed its
+		// limit.
+		if blockCount == math.MinInt64 {
+			// The minimum number for any signed numerical type can
+			// never be made positive
+			return nil, nil, fmt.Errorf("cannot decode binary map with block count: %d", blockCount)
+		}
+		blockCount = -blockCount // convert to its positive equivalent
+		if value, buf, err = longNativeFromBinary(buf); err != nil {
+			return nil, nil, fmt.Errorf("cannot decode binary map block size: %w", err)
+		}
+		if blockSize, maxBlockSize := value.(int64), limits.blockSize(); blockSize > maxBlockSize {
+			return nil, nil, fmt.Errorf("cannot decode binary map when block size exceeds MaxBlockSize: %d > %d", blockSize, maxBlockSize)
+		}
+	}
+	// Ensure block count does not exceed some sane value.
+	if maxBlockCount := limits.blockCount(); blockCount > maxBlockCount {
+		return nil, nil, fmt.Errorf("cannot decode binary map when block count exceeds MaxBlockCount: %d > %d", blockCount, maxBlockCount)
+	}
+	if limits.itemCountExceeded(0, blockCount) {
+		return nil, nil, fmt.Errorf("cannot decode binary map when item count exceeds limit: %d > %d", blockCount, limits.maxItemCount)
+	}
+	// NOTE: While the attempt of a RAM optimization shown below is not
+	// necessary, many encoders will encode all items in a single block.
+	// We can optimize amount of RAM allocated by runtime for the array
+	// by initializing the array for that number of items, although never
+	// for more items than there are bytes remaining, so a malformed block
+	// count does not allocate before any of its items are read.
+	mapValues := make(map[string]interface{}, initialBlockCapacity(blockCount, buf))
+
+	for blockCount != 0 {
+		// Decode `blockCount` datum values from buffer
+		for i := int64(0); i < blockCount; i++ {
+			// first decode the key string
+			if value, buf, err = stringNativeFromBinary(buf); err != nil {
+				return nil, nil, fmt.Errorf("cannot decode binary map key: %w", err)
 			}
-			return longBinaryFromNative(buf, 0) // append tailing 0 block count to signal end of Map
-		},
-		nativeFromTextual: func(buf []byte) (interface{}, []byte, error) {
-			return genericMapTextDecoder(buf, valueCodec, nil) // codecFromKey == nil
-		},
-		textualFromNative: func(buf []byte, datum interface{}) ([]byte, error) {
-			return genericMapTextEncoder(buf, datum, valueCodec, nil)
-		},
-	}, nil
+			key := value.(string) // string decoder always returns a string
+			if _, ok := mapValues[key]; ok {
+				return nil, nil, fmt.Errorf("cannot decode binary map: duplicate key: %q", key)
+			}
+			// then decode the value
+			var newBuf []byte
+			if value, newBuf, err = valueNativeFromBinary(buf); err != nil {
+				return nil, nil, newDecodeError(err, fmt.Errorf("cannot decode binary map value for key %q: %w", key, err), fmt.Sprintf("[%q]", key), start-len(buf))
+			}
+			buf = newBuf
+			mapValues[key] = value
+		}
+		// Decode next blockCount from buffer, because there may be more blocks
+		if value, buf, err = longNativeFromBinary(buf); err != nil {
+			return nil, nil, fmt.Errorf("cannot decode binary map block count: %w", err)
+		}
+		blockCount = value.(int64)
+		if blockCount < 0 {
+			// NOTE: A negative block count implies there is a long
+			// encoded block size following the negative block count.
+			// This decoder only uses the block size to check it does
+			// not exceed its limit.
+			if blockCount == math.MinInt64 {
+				// The minimum number for any signed numerical type can
+				// never be made positive
+				return nil, nil, fmt.Errorf("cannot decode binary map with block count: %d", blockCount)
+			}
+			blockCount = -blockCount // convert to its positive equivalent
+			if value, buf, err = longNativeFromBinary(buf); err != nil {
+				return nil, nil, fmt.Errorf("cannot decode binary map block size: %w", err)
+			}
+			if blockSize, maxBlockSize := value.(int64), limits.blockSize(); blockSize > maxBlockSize {
+				return nil, nil, fmt.Errorf("cannot decode binary map when block size exceeds MaxBlockSize: %d > %d", blockSize, maxBlockSize)
+			}
+		}
+		// Ensure block count does not exceed some sane value.
+		if maxBlockCount := limits.blockCount(); blockCount > maxBlockCount {
+			return nil, nil, fmt.Errorf("cannot decode binary map when block count exceeds MaxBlockCount: %d > %d", blockCount, maxBlockCount)
+		}
+		if limits.itemCountExceeded(len(mapValues), blockCount) {
+			return nil, nil, fmt.Errorf("cannot decode binary map when item count exceeds limit: %d > %d", int64(len(mapValues))+blockCount, limits.maxItemCount)
+		}
+	}
+	return mapValues, buf, nil
 }
 
 // genericMapTextDecoder decodes a JSON text blob to a native Go map, using the
@@ -156,6 +209,14 @@ func makeMapCodec(st map[string]*Codec, namespace string, schemaMap map[string]i
 // codecFromKey is nil, every map value will be decoded using defaultCodec, if
 // possible.
 func genericMapTextDecoder(buf []byte, defaultCodec *Codec, codecFromKey map[string]*Codec) (map[string]interface{}, []byte, error) {
+	return genericMapTextKeysDecoder(buf, defaultCodec, codecFromKey, nil, false)
+}
+
+// genericMapTextKeysDecoder decodes a JSON text blob like genericMapTextDecoder
+// does, also appending each key to keys in the order they appear in the text,
+// when keys is not nil. When standard is true, values are decoded from
+// standard JSON rather than textual Avro data.
+func genericMapTextKeysDecoder(buf []byte, defaultCodec *Codec, codecFromKey map[string]*Codec, keys *[]string, standard bool) (map[string]interface{}, []byte, error) {
 	var value interface{}
 	var err error
 	var b byte
@@ -167,7 +228,7 @@ func genericMapTextDecoder(buf []byte, defaultCodec *Codec, codecFromKey map[str
 		return nil, nil, err
 	}
 	if buf, _ = advanceToNonWhitespace(buf); len(buf) == 0 {
-		return nil, nil, io.ErrShortBuffer
+		return nil, nil, ErrShortBuffer{}
 	}
 	// NOTE: Special case empty map
 	if buf[0] == '}' {
@@ -179,7 +240,7 @@ func genericMapTextDecoder(buf []byte, defaultCodec *Codec, codecFromKey map[str
 		// decode key string
 		value, buf, err = stringNativeFromTextual(buf)
 		if err != nil {
-			return nil, nil, fmt.Errorf("cannot decode textual map: expected key: %s", err)
+			return nil, nil, fmt.Errorf("cannot decode textual map: expected key: %w", err)
 		}
 		key := value.(string)
 		// Is key already used?
@@ -200,22 +261,30 @@ func genericMapTextDecoder(buf []byte, defaultCodec *Codec, codecFromKey map[str
 		}
 		// decode value
 		if buf, _ = advanceToNonWhitespace(buf); len(buf) == 0 {
-			return nil, nil, io.ErrShortBuffer
+			return nil, nil, ErrShortBuffer{}
+		}
+		if standard {
+			value, buf, err = fieldCodec.nativeStandard(buf)
+		} else {
+			value, buf, err = fieldCodec.nativeFromTextual(buf)
 		}
-		value, buf, err = fieldCodec.nativeFromTextual(buf)
 		if err != nil {
-			return nil, nil, fmt.Errorf("%s for key: %q", err, key)
+			return nil, nil, fmt.Errorf("%w for key: %q", err, key)
 		}
 		// set map value for key
+		if keys != nil {
+			*keys = append(*keys, key)
+		}
 		if fieldCodec.typeName.fullName == "union" {
-			mapValues[key] = &value
-
+			// NOTE: value is reused for every key, so point at a copy of it
+			unionValue := value
+			mapValues[key] = &unionValue
 		} else {
 			mapValues[key] = value
 		}
 		// either comma or closing curly brace
 		if buf, _ = advanceToNonWhitespace(buf); len(buf) == 0 {
-			return nil, nil, io.ErrShortBuffer
+			return nil, nil, ErrShortBuffer{}
 		}
 		switch b = buf[0]; b {
 		case '}':
@@ -227,10 +296,10 @@ func genericMapTextDecoder(buf []byte, defaultCodec *Codec, codecFromKey map[str
 		}
 		// NOTE: consume comma from above
 		if buf, _ = advanceToNonWhitespace(buf[1:]); len(buf) == 0 {
-			return nil, nil, io.ErrShortBuffer
+			return nil, nil, ErrShortBuffer{}
 		}
 	}
-	return nil, nil, io.ErrShortBuffer
+	return nil, nil, ErrShortBuffer{}
 }
 
 // genericMapTextEncoder encodes a native Go map to a JSON text blob, using the
@@ -238,18 +307,21 @@ func genericMapTextDecoder(buf []byte, defaultCodec *Codec, codecFromKey map[str
 // defaultCodec if provided. If defaultCodec is nil, this function returns an
 // error if it encounters a map key that is not present in codecFromKey. If
 // codecFromKey is nil, every map value will be encoded using defaultCodec, if
-// possible.
-func genericMapTextEncoder(buf []byte, datum interface{}, defaultCodec *Codec, codecFromKey map[string]*Codec) ([]byte, error) {
+// possible. When standard is true, values are encoded as standard JSON rather
+// than as textual Avro data. When sorted is true, keys are encoded in
+// lexicographic order rather than in the random order of the map.
+func genericMapTextEncoder(buf []byte, datum interface{}, defaultCodec *Codec, codecFromKey map[string]*Codec, standard, sorted bool) ([]byte, error) {
 	mapValues, err := convertMap(datum)
 	if err != nil {
-		return nil, fmt.Errorf("cannot encode textual map: %s", err)
+		return nil, fmt.Errorf("cannot encode textual map: %w", err)
 	}
 
 	var atLeastOne bool
 
 	buf = append(buf, '{')
 
-	for key, value := range mapValues {
+	for _, key := range mapKeys(mapValues, sorted) {
+		value := mapValues[key]
 		atLeastOne = true
 
 		// Find a codec for the key
@@ -260,30 +332,9 @@ func genericMapTextEncoder(buf []byte, datum interface{}, defaultCodec *Codec, c
 		if fieldCodec == nil {
 			return nil, fmt.Errorf("cannot encode textual map: cannot determine codec: %q", key)
 		}
-		// Encode key string
-		buf, err = stringTextualFromNative(buf, key)
-		if err != nil {
+		if buf, err = genericMapTextEntryEncoder(buf, key, value, fieldCodec, standard); err != nil {
 			return nil, err
 		}
-		buf = append(buf, ':')
-
-		// Encode value
-		rVal := reflect.ValueOf(value)
-
-		if fieldCodec.typeName.fullName == "union" && rVal.Kind() == reflect.Ptr {
-			if rVal.IsNil() {
-				buf, err = nullTextualFromNative(buf, nil)
-			} else {
-				buf, err = fieldCodec.textualFromNative(buf, value)
-			}
-		} else {
-			// Encode value
-			buf, err = fieldCodec.textualFromNative(buf, value)
-		}
-		if err != nil {
-			// field was specified in datum; therefore its value was invalid
-			return nil, fmt.Errorf("cannot encode textual map: value for %q does not match its schema: %s", key, err)
-		}
 
 		buf = append(buf, ',')
 	}
@@ -294,6 +345,46 @@ func genericMapTextEncoder(buf []byte, datum interface{}, defaultCodec *Codec, c
 	return append(buf, '}'), nil
 }
 
+// genericMapTextEntryEncoder encodes a single key and value of a native Go map
+// or record to JSON text, using fieldCodec to encode the value.
+func genericMapTextEntryEncoder(buf []byte, key string, value interface{}, fieldCodec *Codec, standard bool) ([]byte, error) {
+	// Encode key string
+	buf, err := stringTextualFromNative(buf, key)
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, ':')
+
+	// Encode value
+	rVal := reflect.ValueOf(value)
+
+	if fieldCodec.typeName.fullName == "union" && rVal.Kind() == reflect.Ptr && rVal.IsNil() {
+		buf, err = nullTextualFromNative(buf, nil)
+	} else if standard {
+		buf, err = fieldCodec.textualStandard(buf, value)
+	} else {
+		buf, err = fieldCodec.textualFromNative(buf, value)
+	}
+	if err != nil {
+		// field was specified in datum; therefore its value was invalid
+		return nil, fmt.Errorf("cannot encode textual map: value for %q does not match its schema: %w", key, err)
+	}
+	return buf, nil
+}
+
+// mapKeys returns the keys of mapValues, in lexicographic order when sorted is
+// true, or otherwise in the random order of the map.
+func mapKeys(mapValues map[string]interface{}, sorted bool) []string {
+	keys := make([]string, 0, len(mapValues))
+	for key := range mapValues {
+		keys = append(keys, key)
+	}
+	if sorted {
+		sort.Strings(keys)
+	}
+	return keys
+}
+
 // convertMap converts datum to map[string]interface{} if possible.
 func convertMap(datum interface{}) (map[string]interface{}, error) {
 	mapValues, ok := datum.(map[string]interface{})
@@ -10,6 +10,7 @@
 package goavro
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"testing"
@@ -98,6 +99,28 @@ func TestMapDecodeNextBlockCountTooNegative(t *testing.T) {
 	testBinaryDecodeFail(t, `{"type":"map","values":"int"}`, append(append([]byte{1, 2, 4, 'k', '1', 6}, mostNegativeBlockCount...), 2), "block count")
 }
 
+func TestMapDecodeBlockLimits(t *testing.T) {
+	codec, err := NewCodecWithOptions(`{"type":"map","values":"int"}`, WithMaxBlockCount(2), WithMaxBlockSize(8))
+	ensureError(t, err)
+
+	_, _, err = codec.NativeFromBinary([]byte{4, 2, 'a', 2, 2, 'b', 4, 0})
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinary([]byte{6, 2, 'a', 2, 2, 'b', 4, 2, 'c', 6, 0})
+	ensureError(t, err, "block count exceeds MaxBlockCount: 3 > 2")
+	_, _, err = codec.NativeFromBinary([]byte{1, 0xc8, 0x01, 2, 'a', 2, 0})
+	ensureError(t, err, "block size exceeds MaxBlockSize: 100 > 8")
+}
+
+func TestMapDecodeItemCountLimit(t *testing.T) {
+	codec, err := NewCodecWithOptions(`{"type":"map","values":"int"}`, WithMaxItemCount(2))
+	ensureError(t, err)
+
+	_, _, err = codec.NativeFromBinary([]byte{2, 2, 'a', 2, 2, 2, 'b', 4, 0})
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinary([]byte{2, 2, 'a', 2, 2, 2, 'b', 4, 2, 2, 'c', 6, 0})
+	ensureError(t, err, "item count exceeds limit: 3 > 2")
+}
+
 func TestMapDecodeFail(t *testing.T) {
 	schema := `{"type":"map","values":"boolean"}`
 	testBinaryDecodeFail(t, schema, nil, "cannot decode binary map block count")           // leading block count
@@ -174,3 +197,52 @@ func ExampleMap() {
 	fmt.Println(string(buf))
 	// Output: {"f1":{"k1":3.5}}
 }
+
+func TestMapSortedKeys(t *testing.T) {
+	codec, err := NewCodecWithOptions(`{"type":"record","name":"r1","fields":[{"name":"m","type":{"type":"map","values":{"type":"map","values":"int"}}}]}`, WithSortedMapKeys())
+	ensureError(t, err)
+
+	inner := make(map[string]interface{})
+	outer := make(map[string]interface{})
+	for i := 0; i < 20; i++ {
+		inner[fmt.Sprintf("k%02d", i)] = i
+		outer[fmt.Sprintf("k%02d", 19-i)] = inner
+	}
+	datum := map[string]interface{}{"m": outer}
+
+	expected, err := codec.BinaryFromNative(nil, datum)
+	ensureError(t, err)
+	text, err := codec.TextualFromNative(nil, datum)
+	ensureError(t, err)
+	for i := 0; i < 10; i++ {
+		buf, err := codec.BinaryFromNative(nil, datum)
+		ensureError(t, err)
+		if !bytes.Equal(buf, expected) {
+			t.Fatalf("GOT: %v; WANT: %v", buf, expected)
+		}
+		buf, err = codec.TextualFromNative(nil, datum)
+		ensureError(t, err)
+		if !bytes.Equal(buf, text) {
+			t.Fatalf("GOT: %s; WANT: %s", buf, text)
+		}
+	}
+
+	codec, err = NewCodecWithOptions(`{"type":"map","values":"int"}`, WithSortedMapKeys())
+	ensureError(t, err)
+	datum = map[string]interface{}{"c": 3, "a": 1, "b": 2}
+	buf, err := codec.BinaryFromNative(nil, datum)
+	ensureError(t, err)
+	if expected := []byte{6, 2, 'a', 2, 2, 'b', 4, 2, 'c', 6, 0}; !bytes.Equal(buf, expected) {
+		t.Errorf("GOT: %v; WANT: %v", buf, expected)
+	}
+	buf, err = codec.TextualFromNative(nil, datum)
+	ensureError(t, err)
+	if actual, expected := string(buf), `{"a":1,"b":2,"c":3}`; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	bb := new(bytes.Buffer)
+	ensureError(t, codec.TextualFromNativeWriter(bb, datum))
+	if actual, expected := bb.String(), `{"a":1,"b":2,"c":3}`; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
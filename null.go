@@ -13,7 +13,6 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"io"
 )
 
 var nullBytes = []byte("null")
@@ -29,7 +28,7 @@ func nullBinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
 
 func nullNativeFromTextual(buf []byte) (interface{}, []byte, error) {
 	if len(buf) < 4 {
-		return nil, nil, fmt.Errorf("cannot decode textual null: %s", io.ErrShortBuffer)
+		return nil, nil, fmt.Errorf("cannot decode textual null: %w", ErrShortBuffer{})
 	}
 	if bytes.Equal(buf[:4], nullBytes) {
 		return nil, buf[4:], nil
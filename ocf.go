@@ -15,6 +15,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 )
 
 const (
@@ -45,6 +46,7 @@ const (
 	ocfHeaderSizeConst = 48 // OCF header is usually about 48 bytes longer than its compressed schema
 	ocfMagicString     = "Obj\x01"
 	ocfMetadataSchema  = `{"type":"map","values":"bytes"}`
+	ocfReservedPrefix  = "avro." // Prefix of metadata keys reserved by the specification
 	ocfSyncLength      = 16
 )
 
@@ -94,10 +96,20 @@ func newOCFHeader(config OCFConfig) (*ocfHeader, error) {
 		return nil, fmt.Errorf("cannot create OCF header without either Codec or Schema specified")
 	} else {
 		if header.codec, err = NewCodec(config.Schema); err != nil {
-			return nil, fmt.Errorf("cannot create OCF header: %s", err)
+			return nil, fmt.Errorf("cannot create OCF header: %w", err)
 		}
 	}
 
+	//
+	// application metadata
+	//
+	// The specification reserves keys prefixed with "avro." for its own use,
+	// so they cannot be overridden, nor used for anything else.
+	for k := range config.MetaData {
+		if strings.HasPrefix(k, ocfReservedPrefix) {
+			return nil, fmt.Errorf("cannot create OCF header with reserved metadata key: %q", k)
+		}
+	}
 	header.metadata = config.MetaData
 
 	//
@@ -118,7 +130,7 @@ func readOCFHeader(ior io.Reader) (*ocfHeader, error) {
 	magic := make([]byte, 4)
 	_, err := io.ReadFull(ior, magic)
 	if err != nil {
-		return nil, fmt.Errorf("cannot read OCF header magic bytes: %s", err)
+		return nil, fmt.Errorf("cannot read OCF header magic bytes: %w", err)
 	}
 	if !bytes.Equal(magic, ocfMagicBytes) {
 		return nil, fmt.Errorf("cannot read OCF header with invalid magic bytes: %#q", magic)
@@ -129,7 +141,7 @@ func readOCFHeader(ior io.Reader) (*ocfHeader, error) {
 	//
 	metadata, err := metadataBinaryReader(ior)
 	if err != nil {
-		return nil, fmt.Errorf("cannot read OCF header metadata: %s", err)
+		return nil, fmt.Errorf("cannot read OCF header metadata: %w", err)
 	}
 
 	//
@@ -167,7 +179,7 @@ func readOCFHeader(ior io.Reader) (*ocfHeader, error) {
 	}
 	codec, err := NewCodec(string(value))
 	if err != nil {
-		return nil, fmt.Errorf("cannot read OCF header with invalid avro.schema: %s", err)
+		return nil, fmt.Errorf("cannot read OCF header with invalid avro.schema: %w", err)
 	}
 
 	header := &ocfHeader{codec: codec, compressionID: cID, metadata: metadata}
@@ -176,7 +188,7 @@ func readOCFHeader(ior io.Reader) (*ocfHeader, error) {
 	// read and store sync marker
 	//
 	if n, err := io.ReadFull(ior, header.syncMarker[:]); err != nil {
-		return nil, fmt.Errorf("cannot read OCF header without sync marker: only read %d of %d bytes: %s", n, ocfSyncLength, err)
+		return nil, fmt.Errorf("cannot read OCF header without sync marker: only read %d of %d bytes: %w", n, ocfSyncLength, err)
 	}
 
 	//
@@ -223,7 +235,7 @@ func writeOCFHeader(header *ocfHeader, iow io.Writer) (err error) {
 
 	buf, err = ocfMetadataCodec.BinaryFromNative(buf, meta)
 	if err != nil {
-		return fmt.Errorf("should not get here: cannot write OCF header: %s", err)
+		return fmt.Errorf("should not get here: cannot write OCF header: %w", err)
 	}
 
 	//
@@ -234,7 +246,7 @@ func writeOCFHeader(header *ocfHeader, iow io.Writer) (err error) {
 	// emit OCF header
 	_, err = iow.Write(buf)
 	if err != nil {
-		return fmt.Errorf("cannot write OCF header: %s", err)
+		return fmt.Errorf("cannot write OCF header: %w", err)
 	}
 	return nil
 }
@@ -12,12 +12,14 @@ package goavro
 import (
 	"bytes"
 	"compress/flate"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"hash/crc32"
 	"io"
 	"io/ioutil"
+	"strings"
 
 	"github.com/golang/snappy"
 )
@@ -25,15 +27,25 @@ import (
 // OCFReader structure is used to read Object Container Files (OCF).
 type OCFReader struct {
 	header              *ocfHeader
+	codec               *Codec // decodes data values read by Read
 	block               []byte // buffer from which decoding takes place
 	rerr                error  // most recent error that took place while reading bytes (unrecoverable)
 	ior                 io.Reader
 	readReady           bool  // true after Scan and before Read
 	remainingBlockItems int64 // count of encoded data items remaining in block buffer to be decoded
+
+	// NOTE: Decoded values may refer to the bytes of the block they were
+	// decoded from, so only the buffers of compressed blocks, which are
+	// decompressed into a new block, are reused for the next block.
+	compressed   []byte
+	decompressor io.ReadCloser // deflate decompressor, reset for each block
+	sync         [ocfSyncLength]byte
 }
 
 // NewOCFReader initializes and returns a new structure used to read an Avro
-// Object Container File (OCF).
+// Object Container File (OCF). The file is read from ior one block at a time,
+// as Scan needs the next block, so only a single block of the file is held in
+// memory, however large the file is.
 //
 //     func example(ior io.Reader) error {
 //         // NOTE: Wrap provided io.Reader in a buffered reader, which improves the
@@ -55,9 +67,45 @@ type OCFReader struct {
 func NewOCFReader(ior io.Reader) (*OCFReader, error) {
 	header, err := readOCFHeader(ior)
 	if err != nil {
-		return nil, fmt.Errorf("cannot create OCFReader: %s", err)
+		return nil, fmt.Errorf("cannot create OCFReader: %w", err)
+	}
+	return &OCFReader{header: header, codec: header.codec, ior: ior}, nil
+}
+
+// NewOCFReaderWithReaderSchema initializes and returns a new structure used to
+// read an Avro Object Container File (OCF) like NewOCFReader does, but whose
+// Read method returns data values in the form of readerSchema rather than of
+// the schema found within the OCF file. The schema of the file is the writer
+// schema, which is resolved with readerSchema as NewCodecForReaderWriter does,
+// so files written with an older version of a schema can be read with a newer
+// one: fields added to the reader schema take their default values, fields
+// removed from it are skipped, and numeric values are promoted.
+//
+//     ocfr, err := goavro.NewOCFReaderWithReaderSchema(br, `{"type":"record","name":"r","fields":[{"name":"a","type":"long"},{"name":"b","type":"string","default":"none"}]}`)
+//     if err != nil {
+//         return err
+//     }
+//     for ocfr.Scan() {
+//         datum, err := ocfr.Read()
+//         if err != nil {
+//             return err
+//         }
+//         fmt.Println(datum) // map[a:3 b:none]
+//     }
+//     return ocfr.Err()
+//
+// The Codec method still returns the codec of the writer schema found within
+// the file, and ReadRaw still returns data values encoded using it.
+func NewOCFReaderWithReaderSchema(ior io.Reader, readerSchema string) (*OCFReader, error) {
+	header, err := readOCFHeader(ior)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create OCFReader: %w", err)
+	}
+	codec, err := NewCodecForReaderWriter(readerSchema, string(header.metadata["avro.schema"]))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create OCFReader: %w", err)
 	}
-	return &OCFReader{header: header, ior: ior}, nil
+	return &OCFReader{header: header, codec: codec, ior: ior}, nil
 }
 
 //MetaData returns the file metadata map found within the OCF file
@@ -65,6 +113,19 @@ func (ocfr *OCFReader) MetaData() map[string][]byte {
 	return ocfr.header.metadata
 }
 
+// Metadata returns the application specific metadata found within the OCF
+// file, which is the file metadata map without the keys prefixed with
+// "avro.", such as avro.schema and avro.codec, reserved by the specification.
+func (ocfr *OCFReader) Metadata() map[string][]byte {
+	metadata := make(map[string][]byte, len(ocfr.header.metadata))
+	for k, v := range ocfr.header.metadata {
+		if !strings.HasPrefix(k, ocfReservedPrefix) {
+			metadata[k] = v
+		}
+	}
+	return metadata
+}
+
 // Codec returns the codec found within the OCF file.
 func (ocfr *OCFReader) Codec() *Codec {
 	return ocfr.header.codec
@@ -95,6 +156,20 @@ func (ocfr *OCFReader) Err() error {
 // is designed to be called only once after each invocation of the Scan method.
 // See `NewOCFReader` documentation for an example.
 func (ocfr *OCFReader) Read() (interface{}, error) {
+	return ocfr.read(nil)
+}
+
+// ReadContext consumes one datum value from the Avro OCF stream and returns it
+// like Read does, but stops decoding the value once ctx is done, returning the
+// error of the context, as Codec.NativeFromBinaryContext does. The error is also
+// returned by Err.
+func (ocfr *OCFReader) ReadContext(ctx context.Context) (interface{}, error) {
+	return ocfr.read(ctx)
+}
+
+// read decodes one datum value from the block, using NativeFromBinaryContext
+// when ctx is not nil.
+func (ocfr *OCFReader) read(ctx context.Context) (interface{}, error) {
 	// NOTE: Test previous error before testing readReady to prevent overwriting
 	// previous error.
 	if ocfr.rerr != nil {
@@ -108,21 +183,90 @@ func (ocfr *OCFReader) Read() (interface{}, error) {
 
 	// decode one datum value from block
 	var datum interface{}
-	datum, ocfr.block, ocfr.rerr = ocfr.header.codec.NativeFromBinary(ocfr.block)
+	if ctx != nil {
+		datum, ocfr.block, ocfr.rerr = ocfr.codec.NativeFromBinaryContext(ctx, ocfr.block)
+	} else {
+		datum, ocfr.block, ocfr.rerr = ocfr.codec.NativeFromBinary(ocfr.block)
+	}
 	if ocfr.rerr != nil {
-		return false, ocfr.rerr
+		return nil, ocfr.rerr
 	}
 	ocfr.remainingBlockItems--
 
 	return datum, nil
 }
 
+// ReadRaw consumes one datum value from the Avro OCF stream and returns its
+// binary encoding, without converting it to a native Go value. The datum is
+// still decoded to find where it ends in the block, but the returned slice is
+// exactly the bytes written for it, which may be handed to OCFWriter.AppendRaw
+// to copy records into another OCF with the same schema. Like Read, ReadRaw is
+// designed to be called only once after each invocation of the Scan method.
+//
+//     for ocfr.Scan() {
+//         raw, err := ocfr.ReadRaw()
+//         if err != nil {
+//             break
+//         }
+//         if err = ocfw.AppendRaw(raw); err != nil {
+//             break
+//         }
+//     }
+//
+// The returned slice refers to the block being read, and ought not to be
+// modified.
+func (ocfr *OCFReader) ReadRaw() ([]byte, error) {
+	// NOTE: Test previous error before testing readReady to prevent overwriting
+	// previous error.
+	if ocfr.rerr != nil {
+		return nil, ocfr.rerr
+	}
+	if !ocfr.readReady {
+		ocfr.rerr = errors.New("ReadRaw called without successful Scan")
+		return nil, ocfr.rerr
+	}
+	ocfr.readReady = false
+
+	// decode one datum value from block only to find where it ends
+	var remaining []byte
+	if _, remaining, ocfr.rerr = ocfr.header.codec.NativeFromBinary(ocfr.block); ocfr.rerr != nil {
+		return nil, ocfr.rerr
+	}
+	n := len(ocfr.block) - len(remaining)
+	raw := ocfr.block[:n:n]
+	ocfr.block = remaining
+	ocfr.remainingBlockItems--
+
+	return raw, nil
+}
+
 // RemainingBlockItems returns the number of items remaining in the block being
 // processed.
 func (ocfr *OCFReader) RemainingBlockItems() int64 {
 	return ocfr.remainingBlockItems
 }
 
+// ScanContext returns true when there is at least one more data item to be read
+// from the Avro OCF like Scan does, but returns false once ctx is done, after
+// which Err returns the error of the context.
+//
+//     for ocfr.ScanContext(ctx) {
+//         datum, err := ocfr.ReadContext(ctx)
+//         if err != nil {
+//             break
+//         }
+//         fmt.Println(datum)
+//     }
+//     if err := ocfr.Err(); err != nil {
+//         return err // context.Canceled when ctx was cancelled
+//     }
+func (ocfr *OCFReader) ScanContext(ctx context.Context) bool {
+	if ocfr.rerr == nil {
+		ocfr.rerr = ctx.Err()
+	}
+	return ocfr.Scan()
+}
+
 // Scan returns true when there is at least one more data item to be read from
 // the Avro OCF. Scan ought to be called prior to calling the Read method each
 // time the Read method is invoked.  See `NewOCFReader` documentation for an
@@ -149,7 +293,7 @@ func (ocfr *OCFReader) Scan() bool {
 			if ocfr.rerr == io.EOF {
 				ocfr.rerr = nil // merely end of file, rather than error
 			} else {
-				ocfr.rerr = fmt.Errorf("cannot read block count: %s", ocfr.rerr)
+				ocfr.rerr = fmt.Errorf("cannot read block count: %w", ocfr.rerr)
 			}
 			return false
 		}
@@ -159,12 +303,13 @@ func (ocfr *OCFReader) Scan() bool {
 		}
 		if ocfr.remainingBlockItems > MaxBlockCount {
 			ocfr.rerr = fmt.Errorf("cannot decode when block count exceeds MaxBlockCount: %d > %d", ocfr.remainingBlockItems, MaxBlockCount)
+			return false
 		}
 
 		var blockSize int64
 		blockSize, ocfr.rerr = longBinaryReader(ocfr.ior)
 		if ocfr.rerr != nil {
-			ocfr.rerr = fmt.Errorf("cannot read block size: %s", ocfr.rerr)
+			ocfr.rerr = fmt.Errorf("cannot read block size: %w", ocfr.rerr)
 			return false
 		}
 		if blockSize <= 0 {
@@ -177,10 +322,17 @@ func (ocfr *OCFReader) Scan() bool {
 		}
 
 		// read entire block into buffer
-		ocfr.block = make([]byte, blockSize)
+		if ocfr.header.compressionID == compressionNull {
+			ocfr.block = make([]byte, blockSize)
+		} else {
+			if int64(cap(ocfr.compressed)) < blockSize {
+				ocfr.compressed = make([]byte, blockSize)
+			}
+			ocfr.block = ocfr.compressed[:blockSize]
+		}
 		_, ocfr.rerr = io.ReadFull(ocfr.ior, ocfr.block)
 		if ocfr.rerr != nil {
-			ocfr.rerr = fmt.Errorf("cannot read block: %s", ocfr.rerr)
+			ocfr.rerr = fmt.Errorf("cannot read block: %w", ocfr.rerr)
 			return false
 		}
 
@@ -191,13 +343,19 @@ func (ocfr *OCFReader) Scan() bool {
 		case compressionDeflate:
 			// NOTE: flate.NewReader wraps with io.ByteReader if argument does
 			// not implement that interface.
-			rc := flate.NewReader(bytes.NewBuffer(ocfr.block))
-			ocfr.block, ocfr.rerr = ioutil.ReadAll(rc)
+			if ocfr.decompressor == nil {
+				ocfr.decompressor = flate.NewReader(bytes.NewReader(ocfr.block))
+			} else if ocfr.rerr = ocfr.decompressor.(flate.Resetter).Reset(bytes.NewReader(ocfr.block), nil); ocfr.rerr != nil {
+				ocfr.rerr = fmt.Errorf("cannot decompress: %w", ocfr.rerr)
+				return false
+			}
+			ocfr.block, ocfr.rerr = ioutil.ReadAll(ocfr.decompressor)
 			if ocfr.rerr != nil {
-				_ = rc.Close()
+				ocfr.rerr = fmt.Errorf("cannot decompress: %w", ocfr.rerr)
 				return false
 			}
-			if ocfr.rerr = rc.Close(); ocfr.rerr != nil {
+			if ocfr.rerr = ocfr.decompressor.Close(); ocfr.rerr != nil {
+				ocfr.rerr = fmt.Errorf("cannot decompress: %w", ocfr.rerr)
 				return false
 			}
 
@@ -209,7 +367,7 @@ func (ocfr *OCFReader) Scan() bool {
 			}
 			decoded, err := snappy.Decode(nil, ocfr.block[:index])
 			if err != nil {
-				ocfr.rerr = fmt.Errorf("cannot decompress: %s", err)
+				ocfr.rerr = fmt.Errorf("cannot decompress: %w", err)
 				return false
 			}
 			actualCRC := crc32.ChecksumIEEE(decoded)
@@ -227,10 +385,10 @@ func (ocfr *OCFReader) Scan() bool {
 		}
 
 		// read and ensure sync marker matches
-		sync := make([]byte, ocfSyncLength)
+		sync := ocfr.sync[:]
 		var n int
 		if n, ocfr.rerr = io.ReadFull(ocfr.ior, sync); ocfr.rerr != nil {
-			ocfr.rerr = fmt.Errorf("cannot read sync marker: read %d out of %d bytes: %s", n, ocfSyncLength, ocfr.rerr)
+			ocfr.rerr = fmt.Errorf("cannot read sync marker: read %d out of %d bytes: %w", n, ocfSyncLength, ocfr.rerr)
 			return false
 		}
 		if !bytes.Equal(sync, ocfr.header.syncMarker[:]) {
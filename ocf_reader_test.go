@@ -11,6 +11,9 @@ package goavro
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -92,11 +95,381 @@ func TestReadOCFHeaderMetadataSyncMarker(t *testing.T) {
 // OCFReader
 //
 
-// func testOCFReader(t *testing.T, schema string, input []byte, expected ...string) {
-// 	_, err := NewOCFReader(bytes.NewBuffer(append([]byte("Obj\x01"), input...)))
-// 	ensureError(t, err, append([]string{"any prefix?"}, expected...)...)
-// }
+// testOCFReaderScanError writes a few values to an OCF stream, modifies the
+// encoded stream, then ensures reading it back fails with the expected error.
+func testOCFReaderScanError(t *testing.T, compressionName string, modify func([]byte) []byte, expected ...string) {
+	t.Helper()
+	bb := new(bytes.Buffer)
+	ocfw, err := NewOCFWriter(OCFConfig{W: bb, CompressionName: compressionName, Schema: `"long"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ocfw.Append([]int64{13, 42, -12, -1234}); err != nil {
+		t.Fatal(err)
+	}
+
+	ocfr, err := NewOCFReader(bytes.NewReader(modify(bb.Bytes())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ocfr.Scan() {
+		if _, err = ocfr.Read(); err != nil {
+			break
+		}
+	}
+	ensureError(t, ocfr.Err(), expected...)
+}
+
+func TestOCFReaderTruncatedBlock(t *testing.T) {
+	testOCFReaderScanError(t, CompressionNullLabel, func(buf []byte) []byte {
+		return buf[:len(buf)-ocfSyncLength-2] // drop sync marker and end of block
+	}, "cannot read block", "EOF")
+}
+
+func TestOCFReaderTruncatedSyncMarker(t *testing.T) {
+	testOCFReaderScanError(t, CompressionNullLabel, func(buf []byte) []byte {
+		return buf[:len(buf)-3]
+	}, "cannot read sync marker", "read 13 out of 16 bytes")
+}
+
+func TestOCFReaderSyncMarkerMismatch(t *testing.T) {
+	testOCFReaderScanError(t, CompressionNullLabel, func(buf []byte) []byte {
+		buf[len(buf)-1]++
+		return buf
+	}, "sync marker mismatch")
+}
+
+func TestOCFReaderCorruptDeflateBlock(t *testing.T) {
+	testOCFReaderScanError(t, CompressionDeflateLabel, func(buf []byte) []byte {
+		// replace deflate block contents with a reserved block type
+		block := buf[len(buf)-ocfSyncLength-1]
+		buf[len(buf)-ocfSyncLength-1] = block | 0x07
+		return buf
+	}, "cannot decompress")
+}
+
+func TestOCFReaderReadWithoutScan(t *testing.T) {
+	bb := new(bytes.Buffer)
+	if _, err := NewOCFWriter(OCFConfig{W: bb, Schema: `"long"`}); err != nil {
+		t.Fatal(err)
+	}
+	ocfr, err := NewOCFReader(bb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum, err := ocfr.Read()
+	ensureError(t, err, "Read called without successful Scan")
+	if datum != nil {
+		t.Errorf("GOT: %v; WANT: %v", datum, nil)
+	}
+}
+
+func TestOCFReaderSnappyChecksumMismatch(t *testing.T) {
+	testOCFReaderScanError(t, CompressionSnappyLabel, func(buf []byte) []byte {
+		buf[len(buf)-ocfSyncLength-1]++ // last byte of CRC32 checksum
+		return buf
+	}, "snappy CRC32 checksum mismatch")
+}
+
+func TestOCFReaderSnappyCorruptBlock(t *testing.T) {
+	testOCFReaderScanError(t, CompressionSnappyLabel, func(buf []byte) []byte {
+		// find the single byte block size preceding the only block
+		for i := len(buf) - ocfSyncLength - 1; i > 0; i-- {
+			if int(buf[i]>>1) == len(buf)-ocfSyncLength-i-1 {
+				buf[i+1] = 0xff // invalidate snappy decoded length preamble
+				break
+			}
+		}
+		return buf
+	}, "cannot decompress")
+}
+
+func TestOCFReaderContext(t *testing.T) {
+	bb := new(bytes.Buffer)
+	ocfw, err := NewOCFWriter(OCFConfig{W: bb, Schema: `{"type":"array","items":"long"}`})
+	ensureError(t, err)
+	large := make([]interface{}, 1000)
+	for i := range large {
+		large[i] = int64(i)
+	}
+	ensureError(t, ocfw.Append([]interface{}{[]interface{}{int64(1)}, large, []interface{}{int64(2)}}))
+
+	ocfr, err := NewOCFReader(bytes.NewReader(bb.Bytes()))
+	ensureError(t, err)
+	ctx := &countdownContext{Context: context.Background(), remaining: 3}
+	var count int
+	for ocfr.ScanContext(ctx) {
+		if _, err = ocfr.ReadContext(ctx); err != nil {
+			break
+		}
+		count++
+	}
+	// the second value is too large to decode before the context is cancelled
+	if err != context.Canceled || ocfr.Err() != context.Canceled || count != 1 {
+		t.Errorf("GOT: %v, %v, %d; WANT: %v, %v, 1", err, ocfr.Err(), count, context.Canceled, context.Canceled)
+	}
+
+	ocfr, err = NewOCFReader(bytes.NewReader(bb.Bytes()))
+	ensureError(t, err)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if ocfr.ScanContext(cancelled) || ocfr.Err() != context.Canceled {
+		t.Errorf("GOT: %v; WANT: %v", ocfr.Err(), context.Canceled)
+	}
+}
+
+// countingReader counts the bytes read from the reader it wraps.
+type countingReader struct {
+	r     *bytes.Reader
+	count int
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.count += n
+	return n, err
+}
+
+func TestOCFReaderMultipleBlocks(t *testing.T) {
+	for _, compressionName := range []string{CompressionNullLabel, CompressionDeflateLabel, CompressionSnappyLabel} {
+		t.Run(compressionName, func(t *testing.T) {
+			bb := new(bytes.Buffer)
+			ocfw, err := NewOCFWriter(OCFConfig{W: bb, CompressionName: compressionName, Schema: `{"type":"record","name":"r1","fields":[{"name":"id","type":"long"},{"name":"name","type":"bytes"}]}`, BlockSize: 64})
+			ensureError(t, err)
+			const total = 1000
+			for i := 0; i < total; i++ {
+				ensureError(t, ocfw.Append([]interface{}{map[string]interface{}{"id": int64(i), "name": []byte(fmt.Sprintf("name %d", i))}}))
+			}
+			ensureError(t, ocfw.Close())
 
-// func TestOCFReaderRead(t *testing.T) {
-// 	testOCFReader(t,
-// }
+			cr := &countingReader{r: bytes.NewReader(bb.Bytes())}
+			ocfr, err := NewOCFReader(cr)
+			ensureError(t, err)
+			var values []interface{}
+			var blocks int
+			for ocfr.Scan() {
+				datum, err := ocfr.Read()
+				ensureError(t, err)
+				values = append(values, datum)
+				if ocfr.RemainingBlockItems() == 0 {
+					blocks++
+				}
+
+				// no more of the file is read than the blocks decoded so far
+				if len(values) == 1 && cr.count >= bb.Len() {
+					t.Errorf("GOT: %d bytes read before the first value; WANT: fewer than %d", cr.count, bb.Len())
+				}
+			}
+			ensureError(t, ocfr.Err())
+			if blocks < 10 {
+				t.Errorf("GOT: %d blocks; WANT: at least 10", blocks)
+			}
+			if actual, expected := len(values), total; actual != expected {
+				t.Fatalf("GOT: %v; WANT: %v", actual, expected)
+			}
+			// values decoded from earlier blocks are not overwritten by later
+			// blocks
+			for i, value := range values {
+				record := value.(map[string]interface{})
+				if actual, expected := string(record["name"].([]byte)), fmt.Sprintf("name %d", i); record["id"] != int64(i) || actual != expected {
+					t.Fatalf("GOT: %v, %v; WANT: %v, %v", record["id"], actual, i, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestOCFReaderReadRaw(t *testing.T) {
+	const schema = `{"type":"record","name":"r1","fields":[{"name":"id","type":"long"},{"name":"name","type":["null","string"]}]}`
+
+	readRaw := func(t *testing.T, b []byte) [][]byte {
+		t.Helper()
+		ocfr, err := NewOCFReader(bytes.NewReader(b))
+		ensureError(t, err)
+		var records [][]byte
+		for ocfr.Scan() {
+			raw, err := ocfr.ReadRaw()
+			ensureError(t, err)
+			records = append(records, raw)
+		}
+		ensureError(t, ocfr.Err())
+		return records
+	}
+
+	for _, compressionName := range []string{CompressionNullLabel, CompressionDeflateLabel, CompressionSnappyLabel} {
+		t.Run(compressionName, func(t *testing.T) {
+			source := new(bytes.Buffer)
+			ocfw, err := NewOCFWriter(OCFConfig{W: source, CompressionName: compressionName, Schema: schema, BlockSize: 64})
+			ensureError(t, err)
+			const total = 100
+			for i := 0; i < total; i++ {
+				var name interface{}
+				if i%3 != 0 {
+					name = Union("string", fmt.Sprintf("name %d", i))
+				}
+				ensureError(t, ocfw.Append([]interface{}{map[string]interface{}{"id": int64(i), "name": name}}))
+			}
+			ensureError(t, ocfw.Close())
+
+			records := readRaw(t, source.Bytes())
+			if actual, expected := len(records), total; actual != expected {
+				t.Fatalf("GOT: %v; WANT: %v", actual, expected)
+			}
+
+			// copy records into one OCF block by block, and into another all
+			// in a single block
+			for _, blockSize := range []int{64, 0} {
+				destination := new(bytes.Buffer)
+				ocfw, err = NewOCFWriter(OCFConfig{W: destination, CompressionName: compressionName, Schema: schema, BlockSize: blockSize})
+				ensureError(t, err)
+				if blockSize > 0 {
+					for _, raw := range records {
+						ensureError(t, ocfw.AppendRaw(raw))
+					}
+				} else {
+					ensureError(t, ocfw.AppendRaw(records...))
+				}
+				ensureError(t, ocfw.Close())
+
+				copied := readRaw(t, destination.Bytes())
+				if actual, expected := len(copied), total; actual != expected {
+					t.Fatalf("GOT: %v; WANT: %v", actual, expected)
+				}
+				for i := range records {
+					if !bytes.Equal(copied[i], records[i]) {
+						t.Fatalf("record %d: GOT: %#v; WANT: %#v", i, copied[i], records[i])
+					}
+				}
+
+				// copied records decode to the values originally written
+				ocfr, err := NewOCFReader(bytes.NewReader(destination.Bytes()))
+				ensureError(t, err)
+				var i int64
+				for ocfr.Scan() {
+					datum, err := ocfr.Read()
+					ensureError(t, err)
+					if actual, expected := datum.(map[string]interface{})["id"], i; actual != expected {
+						t.Fatalf("GOT: %v; WANT: %v", actual, expected)
+					}
+					i++
+				}
+				ensureError(t, ocfr.Err())
+			}
+		})
+	}
+}
+
+func TestOCFReaderReadRawWithoutScan(t *testing.T) {
+	bb := new(bytes.Buffer)
+	ocfw, err := NewOCFWriter(OCFConfig{W: bb, Schema: `"long"`})
+	ensureError(t, err)
+	ensureError(t, ocfw.Append([]interface{}{int64(13)}))
+
+	ocfr, err := NewOCFReader(bytes.NewReader(bb.Bytes()))
+	ensureError(t, err)
+	_, err = ocfr.ReadRaw()
+	ensureError(t, err, "ReadRaw called without successful Scan")
+
+	ocfr, err = NewOCFReader(bytes.NewReader(bb.Bytes()))
+	ensureError(t, err)
+	if !ocfr.Scan() {
+		t.Fatalf("GOT: %v; WANT: %v", false, true)
+	}
+	raw, err := ocfr.ReadRaw()
+	ensureError(t, err)
+	if actual, expected := raw, []byte{0x1a}; !bytes.Equal(actual, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+	}
+}
+
+func TestOCFReaderWithReaderSchema(t *testing.T) {
+	bb := new(bytes.Buffer)
+	ocfw, err := NewOCFWriter(OCFConfig{W: bb, Schema: `{"type":"record","name":"r1","fields":[{"name":"id","type":"int"},{"name":"obsolete","type":"string"}]}`, BlockSize: 16})
+	ensureError(t, err)
+	const total = 10
+	for i := 0; i < total; i++ {
+		ensureError(t, ocfw.Append([]interface{}{map[string]interface{}{"id": int32(i), "obsolete": "some string"}}))
+	}
+	ensureError(t, ocfw.Close())
+
+	// evolved schema promotes id, removes obsolete, and adds label
+	ocfr, err := NewOCFReaderWithReaderSchema(bytes.NewReader(bb.Bytes()), `{"type":"record","name":"r1","fields":[{"name":"id","type":"long"},{"name":"label","type":"string","default":"none"}]}`)
+	ensureError(t, err)
+	var i int64
+	for ocfr.Scan() {
+		datum, err := ocfr.Read()
+		ensureError(t, err)
+		if actual, expected := datum, (map[string]interface{}{"id": i, "label": "none"}); !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("GOT: %v; WANT: %v", actual, expected)
+		}
+		i++
+	}
+	ensureError(t, ocfr.Err())
+	if actual, expected := i, int64(total); actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	// the codec of the file is still the writer codec
+	if actual, expected := ocfr.Codec().Schema(), `{"type":"record","name":"r1","fields":[{"name":"id","type":"int"},{"name":"obsolete","type":"string"}]}`; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
+
+func TestOCFReaderWithReaderSchemaDepth(t *testing.T) {
+	// a LongList nested more deeply than DefaultMaxDecodeDepth allows
+	datum := map[string]interface{}{"next": nil}
+	for i := 0; i < DefaultMaxDecodeDepth; i++ {
+		datum = map[string]interface{}{"next": Union("LongList", datum)}
+	}
+	bb := new(bytes.Buffer)
+	ocfw, err := NewOCFWriter(OCFConfig{W: bb, Schema: testSafeLinkedList})
+	ensureError(t, err)
+	ensureError(t, ocfw.Append([]interface{}{datum}))
+	ensureError(t, ocfw.Close())
+
+	for _, newReader := range []func() (*OCFReader, error){
+		func() (*OCFReader, error) { return NewOCFReader(bytes.NewReader(bb.Bytes())) },
+		func() (*OCFReader, error) {
+			return NewOCFReaderWithReaderSchema(bytes.NewReader(bb.Bytes()), testSafeLinkedList)
+		},
+	} {
+		ocfr, err := newReader()
+		ensureError(t, err)
+		if !ocfr.Scan() {
+			t.Fatalf("GOT: %v; WANT: %v", false, true)
+		}
+		_, err = ocfr.Read()
+		ensureError(t, err, fmt.Sprintf("nesting depth exceeds limit: %d", DefaultMaxDecodeDepth))
+	}
+}
+
+func TestOCFReaderWithReaderSchemaReadContext(t *testing.T) {
+	items := make([]interface{}, 10000)
+	for i := range items {
+		items[i] = int32(i)
+	}
+	bb := new(bytes.Buffer)
+	ocfw, err := NewOCFWriter(OCFConfig{W: bb, Schema: `{"type":"array","items":"int"}`})
+	ensureError(t, err)
+	ensureError(t, ocfw.Append([]interface{}{items}))
+	ensureError(t, ocfw.Close())
+
+	// cancelled partway through decoding the array
+	ocfr, err := NewOCFReaderWithReaderSchema(bytes.NewReader(bb.Bytes()), `{"type":"array","items":"long"}`)
+	ensureError(t, err)
+	if !ocfr.Scan() {
+		t.Fatalf("GOT: %v; WANT: %v", false, true)
+	}
+	_, err = ocfr.ReadContext(&countdownContext{Context: context.Background(), remaining: 3})
+	if err != context.Canceled {
+		t.Errorf("GOT: %v; WANT: %v", err, context.Canceled)
+	}
+}
+
+func TestOCFReaderWithReaderSchemaCannotResolve(t *testing.T) {
+	bb := new(bytes.Buffer)
+	_, err := NewOCFWriter(OCFConfig{W: bb, Schema: `{"type":"record","name":"r1","fields":[{"name":"id","type":"int"}]}`})
+	ensureError(t, err)
+
+	_, err = NewOCFReaderWithReaderSchema(bytes.NewReader(bb.Bytes()), `{"type":"record","name":"r1","fields":[{"name":"id","type":"int"},{"name":"label","type":"string"}]}`)
+	ensureError(t, err, "cannot create OCFReader", "cannot resolve writer schema with reader schema")
+}
@@ -95,3 +95,102 @@ func TestOCFWriterCompressionSnappy(t *testing.T) {
 func TestOCFWriterWithApplicationMetaData(t *testing.T) {
 	testOCFRoundTripWithHeaders(t, CompressionNullLabel, map[string][]byte{"foo": []byte("BOING"), "goo": []byte("zoo")})
 }
+
+func TestOCFMetadata(t *testing.T) {
+	bb := new(bytes.Buffer)
+	ocfw, err := NewOCFWriter(OCFConfig{
+		W:        bb,
+		Schema:   `"long"`,
+		MetaData: map[string][]byte{"provenance": []byte("pipeline-7")},
+	})
+	ensureError(t, err)
+	ensureError(t, ocfw.Append([]int64{13}))
+
+	ocfr, err := NewOCFReader(bb)
+	ensureError(t, err)
+	// only application metadata, without the reserved keys
+	if actual, expected := fmt.Sprintf("%q", ocfr.Metadata()), `map["provenance":"pipeline-7"]`; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := string(ocfr.MetaData()["avro.codec"]), CompressionNullLabel; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	_, err = NewOCFWriter(OCFConfig{
+		W:        new(bytes.Buffer),
+		Schema:   `"long"`,
+		MetaData: map[string][]byte{"avro.codec": []byte(CompressionDeflateLabel)},
+	})
+	ensureError(t, err, `cannot create OCF header with reserved metadata key: "avro.codec"`)
+}
+
+func TestOCFWriterBlockSize(t *testing.T) {
+	for _, compressionName := range []string{CompressionNullLabel, CompressionDeflateLabel, CompressionSnappyLabel} {
+		t.Run(compressionName, func(t *testing.T) {
+			bb := new(bytes.Buffer)
+			ocfw, err := NewOCFWriter(OCFConfig{
+				W:               bb,
+				CompressionName: compressionName,
+				Schema:          `"long"`,
+				BlockSize:       4,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// each value encodes to a single byte, so blocks have 4 items
+			for i := int64(0); i < 10; i++ {
+				if err = ocfw.Append([]int64{i}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			readValues := func() []int64 {
+				ocfr, err := NewOCFReader(bytes.NewReader(bb.Bytes()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				var values []int64
+				for ocfr.Scan() {
+					value, err := ocfr.Read()
+					if err != nil {
+						t.Fatal(err)
+					}
+					values = append(values, value.(int64))
+				}
+				if err = ocfr.Err(); err != nil {
+					t.Fatal(err)
+				}
+				return values
+			}
+
+			// last two items remain pending until flushed
+			if values := readValues(); len(values) != 8 {
+				t.Errorf("GOT: %v; WANT: %v", len(values), 8)
+			}
+
+			if err = ocfw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			values := readValues()
+			if actual, expected := fmt.Sprintf("%v", values), "[0 1 2 3 4 5 6 7 8 9]"; actual != expected {
+				t.Errorf("GOT: %v; WANT: %v", actual, expected)
+			}
+
+			// nothing pending after Close
+			before := bb.Len()
+			if err = ocfw.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if actual, expected := bb.Len(), before; actual != expected {
+				t.Errorf("GOT: %v; WANT: %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestOCFWriterBlockSizeNegative(t *testing.T) {
+	_, err := NewOCFWriter(OCFConfig{W: new(bytes.Buffer), Schema: `"long"`, BlockSize: -1})
+	ensureError(t, err, "BlockSize is negative")
+}
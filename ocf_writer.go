@@ -54,15 +54,28 @@ type OCFConfig struct {
 
 	// MetaData specifies application specific meta data to be added to
 	// the OCF file.  When appending to an existing OCF, this field
-	// is ignored.
+	// is ignored. Keys prefixed with "avro." are reserved by the
+	// specification, and cause an error.
 	MetaData map[string][]byte
+
+	// BlockSize specifies the number of bytes of encoded data after which a
+	// block is written, (optional). When positive, Append buffers encoded
+	// data items into a pending block, writing the block whenever its size
+	// reaches BlockSize, and Flush or Close must be called to write any
+	// remaining data items. When zero, each invocation of Append writes its
+	// data items as their own block.
+	BlockSize int
 }
 
 // OCFWriter is used to create a new or append to an existing Avro Object
 // Container File (OCF).
 type OCFWriter struct {
-	header *ocfHeader
-	iow    io.Writer
+	header    *ocfHeader
+	iow       io.Writer
+	blockSize int
+
+	block      []byte // encoded data items not yet written
+	blockCount int    // number of data items in block
 }
 
 // NewOCFWriter returns a new OCFWriter instance that may be used for appending
@@ -70,7 +83,10 @@ type OCFWriter struct {
 // new OCF file.
 func NewOCFWriter(config OCFConfig) (*OCFWriter, error) {
 	var err error
-	ocf := &OCFWriter{iow: config.W}
+	if config.BlockSize < 0 {
+		return nil, fmt.Errorf("cannot create OCFWriter when BlockSize is negative: %d", config.BlockSize)
+	}
+	ocf := &OCFWriter{iow: config.W, blockSize: config.BlockSize}
 
 	switch config.W.(type) {
 	case nil:
@@ -79,18 +95,18 @@ func NewOCFWriter(config OCFConfig) (*OCFWriter, error) {
 		file := config.W.(*os.File)
 		stat, err := file.Stat()
 		if err != nil {
-			return nil, fmt.Errorf("cannot create OCFWriter: %s", err)
+			return nil, fmt.Errorf("cannot create OCFWriter: %w", err)
 		}
 		// NOTE: When upstream provides a new file, it will already exist but
 		// have a size of 0 bytes.
 		if stat.Size() > 0 {
 			// attempt to read existing OCF header
 			if ocf.header, err = readOCFHeader(file); err != nil {
-				return nil, fmt.Errorf("cannot create OCFWriter: %s", err)
+				return nil, fmt.Errorf("cannot create OCFWriter: %w", err)
 			}
 			// prepare for appending data to existing OCF
 			if err = ocf.quickScanToTail(file); err != nil {
-				return nil, fmt.Errorf("cannot create OCFWriter: %s", err)
+				return nil, fmt.Errorf("cannot create OCFWriter: %w", err)
 			}
 			return ocf, nil // happy case for appending to existing OCF
 		}
@@ -98,10 +114,10 @@ func NewOCFWriter(config OCFConfig) (*OCFWriter, error) {
 
 	// create new OCF header based on configuration parameters
 	if ocf.header, err = newOCFHeader(config); err != nil {
-		return nil, fmt.Errorf("cannot create OCFWriter: %s", err)
+		return nil, fmt.Errorf("cannot create OCFWriter: %w", err)
 	}
 	if err = writeOCFHeader(ocf.header, config.W); err != nil {
-		return nil, fmt.Errorf("cannot create OCFWriter: %s", err)
+		return nil, fmt.Errorf("cannot create OCFWriter: %w", err)
 	}
 	return ocf, nil // another happy case for creation of new OCF
 }
@@ -120,7 +136,7 @@ func (ocfw *OCFWriter) quickScanToTail(ior io.Reader) error {
 			if err == io.EOF {
 				return nil // merely end of file, rather than error
 			}
-			return fmt.Errorf("cannot read block count: %s", err)
+			return fmt.Errorf("cannot read block count: %w", err)
 		}
 		if blockCount <= 0 {
 			return fmt.Errorf("cannot read when block count is not greater than 0: %d", blockCount)
@@ -131,7 +147,7 @@ func (ocfw *OCFWriter) quickScanToTail(ior io.Reader) error {
 		// Read block size
 		blockSize, err := longBinaryReader(ior)
 		if err != nil {
-			return fmt.Errorf("cannot read block size: %s", err)
+			return fmt.Errorf("cannot read block size: %w", err)
 		}
 		if blockSize <= 0 {
 			return fmt.Errorf("cannot read when block size is not greater than 0: %d", blockSize)
@@ -141,12 +157,12 @@ func (ocfw *OCFWriter) quickScanToTail(ior io.Reader) error {
 		}
 		// Advance reader to end of block
 		if _, err = io.CopyN(ioutil.Discard, ior, blockSize); err != nil {
-			return fmt.Errorf("cannot seek to next block: %s", err)
+			return fmt.Errorf("cannot seek to next block: %w", err)
 		}
 		// Read and validate sync marker
 		var n int
 		if n, err = io.ReadFull(ior, sync); err != nil {
-			return fmt.Errorf("cannot read sync marker: read %d out of %d bytes: %s", n, ocfSyncLength, err)
+			return fmt.Errorf("cannot read sync marker: read %d out of %d bytes: %w", n, ocfSyncLength, err)
 		}
 		if !bytes.Equal(sync, ocfw.header.syncMarker[:]) {
 			return fmt.Errorf("sync marker mismatch: %v != %v", sync, ocfw.header.syncMarker)
@@ -157,13 +173,19 @@ func (ocfw *OCFWriter) quickScanToTail(ior io.Reader) error {
 // Append appends one or more data items to an OCF file in a block. If there are
 // more data items in the slice than MaxBlockCount allows, the data slice will
 // be chunked into multiple blocks, each not having more than MaxBlockCount
-// items.
+// items. When the OCFWriter was created with a positive BlockSize, the data
+// items are instead buffered into a pending block, which is written each time
+// its size reaches BlockSize.
 func (ocfw *OCFWriter) Append(data interface{}) error {
 	arrayValues, err := convertArray(data)
 	if err != nil {
 		return err
 	}
 
+	if ocfw.blockSize > 0 {
+		return ocfw.appendDataIntoPendingBlock(arrayValues)
+	}
+
 	// Chunk data so no block has more than MaxBlockCount items.
 	for int64(len(arrayValues)) > MaxBlockCount {
 		if err := ocfw.appendDataIntoBlock(arrayValues[:MaxBlockCount]); err != nil {
@@ -174,6 +196,83 @@ func (ocfw *OCFWriter) Append(data interface{}) error {
 	return ocfw.appendDataIntoBlock(arrayValues)
 }
 
+// AppendRaw appends one or more data items already encoded in the binary form
+// of the OCF schema, such as those returned by OCFReader.ReadRaw, without
+// decoding and re-encoding them. Blocks are formed the same way Append forms
+// them. Because the items are not examined, it is the caller's responsibility
+// to ensure each one is a single datum encoded with the OCFWriter schema;
+// otherwise the resulting OCF cannot be read.
+func (ocfw *OCFWriter) AppendRaw(data ...[]byte) error {
+	if ocfw.blockSize > 0 {
+		for _, raw := range data {
+			ocfw.block = append(ocfw.block, raw...)
+			ocfw.blockCount++
+
+			if len(ocfw.block) >= ocfw.blockSize || int64(ocfw.blockCount) >= MaxBlockCount {
+				if err := ocfw.Flush(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	// Chunk data so no block has more than MaxBlockCount items.
+	for len(data) > 0 {
+		count := len(data)
+		if int64(count) > MaxBlockCount {
+			count = int(MaxBlockCount)
+		}
+		var block []byte
+		for _, raw := range data[:count] {
+			block = append(block, raw...)
+		}
+		if err := ocfw.writeBlock(block, count); err != nil {
+			return err
+		}
+		data = data[count:]
+	}
+	return nil
+}
+
+// Flush writes the pending block, if any, to the underlying io.Writer. It is
+// only necessary when the OCFWriter was created with a positive BlockSize.
+func (ocfw *OCFWriter) Flush() error {
+	if ocfw.blockCount == 0 {
+		return nil
+	}
+	if err := ocfw.writeBlock(ocfw.block, ocfw.blockCount); err != nil {
+		return err
+	}
+	ocfw.block = ocfw.block[:0]
+	ocfw.blockCount = 0
+	return nil
+}
+
+// Close writes the pending block, if any, to the underlying io.Writer. It does
+// not close the underlying io.Writer, which remains owned by the caller.
+func (ocfw *OCFWriter) Close() error {
+	return ocfw.Flush()
+}
+
+func (ocfw *OCFWriter) appendDataIntoPendingBlock(data []interface{}) error {
+	var err error
+
+	for _, datum := range data {
+		if ocfw.block, err = ocfw.header.codec.BinaryFromNative(ocfw.block, datum); err != nil {
+			return fmt.Errorf("cannot translate datum to binary: %v; %w", datum, err)
+		}
+		ocfw.blockCount++
+
+		if len(ocfw.block) >= ocfw.blockSize || int64(ocfw.blockCount) >= MaxBlockCount {
+			if err = ocfw.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (ocfw *OCFWriter) appendDataIntoBlock(data []interface{}) error {
 	var block []byte // working buffer for encoding data values
 	var err error
@@ -181,10 +280,18 @@ func (ocfw *OCFWriter) appendDataIntoBlock(data []interface{}) error {
 	// Encode and concatenate each data item into the block
 	for _, datum := range data {
 		if block, err = ocfw.header.codec.BinaryFromNative(block, datum); err != nil {
-			return fmt.Errorf("cannot translate datum to binary: %v; %s", datum, err)
+			return fmt.Errorf("cannot translate datum to binary: %v; %w", datum, err)
 		}
 	}
 
+	return ocfw.writeBlock(block, len(data))
+}
+
+// writeBlock compresses the encoded data items in block, then writes it to the
+// underlying io.Writer framed as an OCF data block of count items.
+func (ocfw *OCFWriter) writeBlock(block []byte, count int) error {
+	var err error
+
 	switch ocfw.header.compressionID {
 	case compressionNull:
 		// no-op
@@ -219,7 +326,7 @@ func (ocfw *OCFWriter) appendDataIntoBlock(data []interface{}) error {
 
 	// create file data block
 	buf := make([]byte, 0, len(block)+ocfBlockConst) // pre-allocate block bytes
-	buf, _ = longBinaryFromNative(buf, len(data))    // block count (number of data items)
+	buf, _ = longBinaryFromNative(buf, count)        // block count (number of data items)
 	buf, _ = longBinaryFromNative(buf, len(block))   // block size (number of bytes in block)
 	buf = append(buf, block...)                      // serialized objects
 	buf = append(buf, ocfw.header.syncMarker[:]...)  // sync marker
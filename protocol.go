@@ -0,0 +1,334 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Protocol describes an Avro protocol, which declares named types, and the
+// messages that may be exchanged using them.
+type Protocol struct {
+	typeName *name
+	types    []*Codec
+	messages map[string]*protocolMessage
+}
+
+// protocolMessage holds the codecs of one message of a protocol.
+type protocolMessage struct {
+	request, response *Codec
+}
+
+// protocolDefinition is a named type declared by a protocol, along with the
+// namespace enclosing its declaration.
+type protocolDefinition struct {
+	schemaMap          map[string]interface{}
+	enclosingNamespace string
+}
+
+// ParseProtocol returns a Protocol after parsing the provided Avro protocol
+// JSON, such as the contents of an .avpr file.
+//
+// Each type the protocol declares is given a Codec of its own, and so is the
+// request and response of each message. The request of a message is encoded as
+// a record named after the message, in the namespace of the protocol, whose
+// fields are the message parameters. Because each Codec stands alone, a named
+// type is defined within the schema of each Codec that uses it.
+//
+//     protocol, err := goavro.ParseProtocol(`{"protocol":"Greeter","namespace":"com.example",
+//         "types":[{"type":"record","name":"Greeting","fields":[{"name":"message","type":"string"}]}],
+//         "messages":{"hello":{"request":[{"name":"greeting","type":"Greeting"}],"response":"Greeting"}}}`)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     request, response, err := protocol.Message("hello")
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+func ParseProtocol(protocolSpecification string) (*Protocol, error) {
+	var protocolMap map[string]interface{}
+	if err := json.Unmarshal([]byte(protocolSpecification), &protocolMap); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal protocol JSON: %w", err)
+	}
+
+	protocolName, ok := protocolMap["protocol"].(string)
+	if !ok || protocolName == "" {
+		return nil, fmt.Errorf("Protocol ought to have non-empty string protocol key; received: %T: %v", protocolMap["protocol"], protocolMap["protocol"])
+	}
+	var namespace string
+	if v, ok := protocolMap["namespace"]; ok {
+		if namespace, ok = v.(string); !ok {
+			return nil, fmt.Errorf("Protocol %q namespace, if provided, ought to be a string; received: %T: %v", protocolName, v, v)
+		}
+	}
+	n, err := newName(protocolName, namespace, nullNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("Protocol ought to have valid name: %w", err)
+	}
+	p := &Protocol{typeName: n, messages: make(map[string]*protocolMessage)}
+	pb := &protocolBuilder{definitions: make(map[string]*protocolDefinition)}
+
+	if v, ok := protocolMap["types"]; ok {
+		types, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Protocol %q types ought to be array; received: %T: %v", n, v, v)
+		}
+		for i, typeSchema := range types {
+			schemaMap, ok := typeSchema.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Protocol %q type %d ought to be valid Avro named type; received: %v", n, i+1, typeSchema)
+			}
+			c, err := pb.newCodec(n.namespace, schemaMap, true)
+			if err != nil {
+				return nil, fmt.Errorf("Protocol %q type %d ought to be valid Avro named type: %w", n, i+1, err)
+			}
+			p.types = append(p.types, c)
+		}
+	}
+
+	if v, ok := protocolMap["messages"]; ok {
+		messages, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Protocol %q messages ought to be object; received: %T: %v", n, v, v)
+		}
+		for messageName, message := range messages {
+			m, err := pb.newMessage(n.namespace, messageName, message)
+			if err != nil {
+				return nil, fmt.Errorf("Protocol %q message %q: %w", n, messageName, err)
+			}
+			p.messages[messageName] = m
+		}
+	}
+
+	return p, nil
+}
+
+// Name returns the full name of the protocol.
+func (p *Protocol) Name() string { return p.typeName.fullName }
+
+// Types returns a Codec for each of the named types the protocol declares, in
+// the order they are declared.
+func (p *Protocol) Types() []*Codec { return p.types }
+
+// Message returns the request and response codecs of the named message. The
+// request Codec encodes a record whose fields are the message parameters, and
+// the response Codec of a one-way message encodes null.
+//
+//     request, response, err := protocol.Message("hello")
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     buf, err := request.BinaryFromNative(nil, map[string]interface{}{
+//         "greeting": map[string]interface{}{"message": "hi"},
+//     })
+func (p *Protocol) Message(messageName string) (*Codec, *Codec, error) {
+	m, ok := p.messages[messageName]
+	if !ok {
+		return nil, nil, fmt.Errorf("cannot find protocol %q message: %q", p.typeName, messageName)
+	}
+	return m.request, m.response, nil
+}
+
+// protocolBuilder builds codecs from the schemas of a protocol, defining within
+// each schema the named types it uses that the protocol declared elsewhere.
+type protocolBuilder struct {
+	definitions map[string]*protocolDefinition
+}
+
+func (pb *protocolBuilder) newMessage(enclosingNamespace, messageName string, message interface{}) (*protocolMessage, error) {
+	messageMap, ok := message.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("message ought to be object; received: %T: %v", message, message)
+	}
+	oneWay, _ := messageMap["one-way"].(bool)
+
+	request, ok := messageMap["request"]
+	if !ok {
+		return nil, fmt.Errorf("message ought to have request key")
+	}
+	n, err := newName(messageName, nullNamespace, enclosingNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("message ought to have valid name: %w", err)
+	}
+	if _, ok := pb.definitions[n.fullName]; ok {
+		return nil, fmt.Errorf("message ought not to have the same name as a declared type: %q", n)
+	}
+	requestCodec, err := pb.newCodec(enclosingNamespace, map[string]interface{}{
+		"type":   "record",
+		"name":   messageName,
+		"fields": request,
+	}, false)
+	if err != nil {
+		return nil, fmt.Errorf("request ought to be valid record fields: %w", err)
+	}
+
+	response, ok := messageMap["response"]
+	if !ok {
+		if !oneWay {
+			return nil, fmt.Errorf("message ought to have response key unless it is one-way")
+		}
+		response = "null"
+	}
+	responseCodec, err := pb.newCodec(enclosingNamespace, response, false)
+	if err != nil {
+		return nil, fmt.Errorf("response ought to be valid Avro type: %w", err)
+	}
+	if oneWay && responseCodec.typeName.fullName != "null" {
+		return nil, fmt.Errorf("one-way message response ought to be null; received: %q", responseCodec.typeName)
+	}
+
+	return &protocolMessage{request: requestCodec, response: responseCodec}, nil
+}
+
+// newCodec returns a Codec for the schema, after defining within it the named
+// types it refers to. When declare is true, the named types the schema itself
+// defines are declared by the protocol, and may be referred to by the schemas
+// which follow.
+func (pb *protocolBuilder) newCodec(enclosingNamespace string, schema interface{}, declare bool) (*Codec, error) {
+	schema, err := pb.define(enclosingNamespace, schema, make(map[string]struct{}), declare)
+	if err != nil {
+		return nil, err
+	}
+	schemaSpecification, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err // should not get here because schema was decoded from JSON
+	}
+	return NewCodec(string(schemaSpecification))
+}
+
+// define returns a copy of the schema in which the first reference to each
+// named type declared by the protocol, and not defined within the schema, is
+// replaced with its definition. Named types are resolved using the same lookup
+// order as buildCodecForTypeDescribedByString.
+func (pb *protocolBuilder) define(enclosingNamespace string, schema interface{}, defined map[string]struct{}, declare bool) (interface{}, error) {
+	switch val := schema.(type) {
+	case string:
+		switch val {
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+			return val, nil
+		}
+		var candidates []string
+		if enclosingNamespace != nullNamespace && !strings.ContainsRune(val, '.') {
+			candidates = append(candidates, enclosingNamespace+"."+val)
+		}
+		candidates = append(candidates, val)
+		for _, fullName := range candidates {
+			if _, ok := defined[fullName]; ok {
+				// NOTE: Use the full name, which resolves the same way even
+				// at the root of a schema, where the enclosing namespace of
+				// the built codec is the null namespace.
+				return fullName, nil
+			}
+			if d, ok := pb.definitions[fullName]; ok {
+				if d.enclosingNamespace == nullNamespace && enclosingNamespace != nullNamespace {
+					return nil, fmt.Errorf("cannot refer to type in null namespace from namespace %q: %q", enclosingNamespace, fullName)
+				}
+				return pb.define(d.enclosingNamespace, d.schemaMap, defined, false)
+			}
+		}
+		return nil, fmt.Errorf("unknown type name: %q", val)
+	case []interface{}:
+		members := make([]interface{}, len(val))
+		for i, member := range val {
+			m, err := pb.define(enclosingNamespace, member, defined, declare)
+			if err != nil {
+				return nil, err
+			}
+			members[i] = m
+		}
+		return members, nil
+	case map[string]interface{}:
+		schemaMap := make(map[string]interface{}, len(val))
+		for k, v := range val {
+			schemaMap[k] = v
+		}
+		switch typeName := val["type"]; typeName {
+		case "record", "error", "enum", "fixed":
+			if _, ok := val["name"]; !ok {
+				// NOTE: Only an anonymous fixed with a logical type may omit
+				// its name, and it cannot be referred to elsewhere.
+				return schemaMap, nil
+			}
+			n, err := newNameFromSchemaMap(enclosingNamespace, val)
+			if err != nil {
+				return nil, err
+			}
+			if declare {
+				if _, ok := pb.definitions[n.fullName]; ok {
+					return nil, fmt.Errorf("type ought to be declared once: %q", n)
+				}
+				pb.definitions[n.fullName] = &protocolDefinition{schemaMap: val, enclosingNamespace: enclosingNamespace}
+			}
+			defined[n.fullName] = struct{}{}
+			if _, ok := val["namespace"]; !ok && n.namespace != nullNamespace {
+				// NOTE: Keep the namespace the type was declared in, which
+				// is the protocol namespace at the root of a schema, and may
+				// differ from the enclosing namespace where it is defined.
+				schemaMap["namespace"] = n.namespace
+			}
+			if typeName == "record" || typeName == "error" {
+				fields, ok := val["fields"].([]interface{})
+				if !ok {
+					return schemaMap, nil // NewCodec reports the invalid fields
+				}
+				newFields := make([]interface{}, len(fields))
+				for i, field := range fields {
+					fieldMap, ok := field.(map[string]interface{})
+					if !ok {
+						newFields[i] = field
+						continue
+					}
+					newField := make(map[string]interface{}, len(fieldMap))
+					for k, v := range fieldMap {
+						newField[k] = v
+					}
+					if fieldType, ok := fieldMap["type"]; ok {
+						if newField["type"], err = pb.define(n.namespace, fieldType, defined, declare); err != nil {
+							return nil, fmt.Errorf("Record %q field %d: %w", n, i+1, err)
+						}
+					}
+					newFields[i] = newField
+				}
+				schemaMap["fields"] = newFields
+			}
+			return schemaMap, nil
+		case "array":
+			if items, ok := val["items"]; ok {
+				var err error
+				if schemaMap["items"], err = pb.define(enclosingNamespace, items, defined, declare); err != nil {
+					return nil, err
+				}
+			}
+			return schemaMap, nil
+		case "map":
+			if values, ok := val["values"]; ok {
+				var err error
+				if schemaMap["values"], err = pb.define(enclosingNamespace, values, defined, declare); err != nil {
+					return nil, err
+				}
+			}
+			return schemaMap, nil
+		case nil:
+			return schemaMap, nil // NewCodec reports the missing type
+		default:
+			// A primitive type, possibly with a logical type, or a reference
+			// to a named type.
+			var err error
+			if schemaMap["type"], err = pb.define(enclosingNamespace, typeName, defined, declare); err != nil {
+				return nil, err
+			}
+			return schemaMap, nil
+		}
+	default:
+		return schema, nil // NewCodec reports the invalid schema
+	}
+}
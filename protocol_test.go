@@ -0,0 +1,139 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+const testProtocol = `{
+	"protocol": "Greeter",
+	"namespace": "com.example",
+	"types": [
+		{"type":"enum","name":"Mood","symbols":["happy","sad"]},
+		{"type":"record","name":"Greeting","fields":[{"name":"message","type":"string"},{"name":"mood","type":"Mood"}]},
+		{"type":"error","name":"Oops","namespace":"com.example.errors","fields":[{"name":"cause","type":["null","com.example.Greeting"]}]}
+	],
+	"messages": {
+		"hello": {
+			"request": [{"name":"greeting","type":"Greeting"},{"name":"times","type":"int"}],
+			"response": "Greeting",
+			"errors": ["com.example.errors.Oops"]
+		},
+		"ping": {"request":[],"response":"null","one-way":true},
+		"all": {"request":[],"response":["null","Greeting",{"type":"map","values":"Greeting"}]}
+	}
+}`
+
+func TestProtocol(t *testing.T) {
+	protocol, err := ParseProtocol(testProtocol)
+	ensureError(t, err)
+
+	if actual, expected := protocol.Name(), "com.example.Greeter"; actual != expected {
+		t.Errorf("GOT: %q; WANT: %q", actual, expected)
+	}
+
+	var names []string
+	for _, codec := range protocol.Types() {
+		names = append(names, codec.typeName.fullName)
+	}
+	if actual, expected := fmt.Sprint(names), "[com.example.Mood com.example.Greeting com.example.errors.Oops]"; actual != expected {
+		t.Errorf("GOT: %s; WANT: %s", actual, expected)
+	}
+
+	// each type codec stands alone, defining the types it refers to
+	types := protocol.Types()
+	oops, err := NewCodec(types[2].Schema())
+	ensureError(t, err)
+	if actual, expected := oops.CanonicalSchema(), `{"name":"com.example.errors.Oops","type":"record","fields":[{"name":"cause","type":["null",{"name":"com.example.Greeting","type":"record","fields":[{"name":"message","type":"string"},{"name":"mood","type":{"name":"com.example.Mood","type":"enum","symbols":["happy","sad"]}}]}]}]}`; actual != expected {
+		t.Errorf("GOT: %s; WANT: %s", actual, expected)
+	}
+
+	request, response, err := protocol.Message("hello")
+	ensureError(t, err)
+	greeting := map[string]interface{}{"message": "hi", "mood": "sad"}
+	buf, err := request.BinaryFromNative(nil, map[string]interface{}{"greeting": greeting, "times": 3})
+	ensureError(t, err)
+	if expected := []byte("\x04hi\x02\x06"); !bytes.Equal(buf, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", buf, expected)
+	}
+	buf, err = response.BinaryFromNative(nil, greeting)
+	ensureError(t, err)
+	if expected := []byte("\x04hi\x02"); !bytes.Equal(buf, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", buf, expected)
+	}
+	if actual, expected := response.Rabin, types[1].Rabin; actual != expected {
+		t.Errorf("GOT: %#x; WANT: %#x", actual, expected)
+	}
+
+	request, response, err = protocol.Message("ping")
+	ensureError(t, err)
+	if actual, expected := request.CanonicalSchema(), `{"name":"com.example.ping","type":"record","fields":[]}`; actual != expected {
+		t.Errorf("GOT: %s; WANT: %s", actual, expected)
+	}
+	if actual, expected := response.CanonicalSchema(), `"null"`; actual != expected {
+		t.Errorf("GOT: %s; WANT: %s", actual, expected)
+	}
+
+	// later references to a type defined at the root of a schema use its full
+	// name
+	_, response, err = protocol.Message("all")
+	ensureError(t, err)
+	buf, err = response.BinaryFromNative(nil, Union("map", map[string]interface{}{"a": greeting}))
+	ensureError(t, err)
+	if expected := []byte("\x04\x02\x02a\x04hi\x02\x00"); !bytes.Equal(buf, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", buf, expected)
+	}
+
+	_, _, err = protocol.Message("goodbye")
+	ensureError(t, err, `cannot find protocol "com.example.Greeter" message: "goodbye"`)
+}
+
+func TestProtocolFail(t *testing.T) {
+	testProtocolFail := func(protocol string, errorMessages ...string) {
+		t.Helper()
+		_, err := ParseProtocol(protocol)
+		ensureError(t, err, errorMessages...)
+	}
+
+	testProtocolFail(`[]`, "cannot unmarshal protocol JSON")
+	testProtocolFail(`{"types":[]}`, "Protocol ought to have non-empty string protocol key")
+	testProtocolFail(`{"protocol":"p","types":[{"type":"record","name":"r","fields":[{"name":"a","type":"s"}]}]}`,
+		`Protocol "p" type 1`, `unknown type name: "s"`)
+	testProtocolFail(`{"protocol":"p","types":[{"type":"fixed","name":"f","size":4},{"type":"fixed","name":"f","size":8}]}`,
+		`Protocol "p" type 2`, `type ought to be declared once: "f"`)
+	testProtocolFail(`{"protocol":"p","messages":{"m":{"response":"null"}}}`,
+		`Protocol "p" message "m": message ought to have request key`)
+	testProtocolFail(`{"protocol":"p","messages":{"m":{"request":[{"name":"a","type":"s"}],"response":"null"}}}`,
+		`Protocol "p" message "m": request ought to be valid record fields`, `unknown type name: "s"`)
+	testProtocolFail(`{"protocol":"p","messages":{"m":{"request":[]}}}`,
+		"message ought to have response key unless it is one-way")
+	testProtocolFail(`{"protocol":"p","messages":{"m":{"request":[],"response":"int","one-way":true}}}`,
+		`one-way message response ought to be null; received: "int"`)
+	testProtocolFail(`{"protocol":"p","types":[{"type":"fixed","name":"m","size":4}],"messages":{"m":{"request":[],"response":"null"}}}`,
+		`message ought not to have the same name as a declared type: "m"`)
+}
+
+func ExampleParseProtocol() {
+	protocol, err := ParseProtocol(`{"protocol":"Greeter","namespace":"com.example",
+		"types":[{"type":"record","name":"Greeting","fields":[{"name":"message","type":"string"}]}],
+		"messages":{"hello":{"request":[{"name":"greeting","type":"Greeting"}],"response":"Greeting"}}}`)
+	if err != nil {
+		fmt.Println(err)
+	}
+	request, _, err := protocol.Message("hello")
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(request.CanonicalSchema())
+	// Output: {"name":"com.example.hello","type":"record","fields":[{"name":"greeting","type":{"name":"com.example.Greeting","type":"record","fields":[{"name":"message","type":"string"}]}}]}
+}
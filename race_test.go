@@ -216,3 +216,114 @@ func TestRaceCodecConstruction(t *testing.T) {
 		}
 	}
 }
+
+func TestRaceDecodeStandardJSONUnion(t *testing.T) {
+	codec, err := NewCodecForStandardJSON(`["null","string","long","double",{"type":"array","items":"long"},{"type":"map","values":"string"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		encoded  string
+		expected string
+	}{
+		{`"some string"`, "map[string:some string]"},
+		{`13`, "map[long:13]"},
+		{`3.5`, "map[double:3.5]"},
+		{`[1,2]`, "map[array:[1 2]]"},
+		{`{"k":"v"}`, "map[map:map[k:v]]"},
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan error, len(cases))
+	for _, c := range cases {
+		wg.Add(1)
+		go func(encoded, expected string) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				datum, _, err := codec.NativeFromTextual([]byte(encoded))
+				if err != nil {
+					done <- err
+					return
+				}
+				if actual := fmt.Sprintf("%v", datum); actual != expected {
+					done <- fmt.Errorf("GOT: %v; WANT: %v", actual, expected)
+					return
+				}
+			}
+		}(c.encoded, c.expected)
+	}
+	wg.Wait()
+	close(done)
+	for err := range done {
+		t.Error(err)
+	}
+}
+
+func TestRaceCodecConcurrentUse(t *testing.T) {
+	const schema = `{"type":"record","name":"r1","fields":[
+		{"name":"id","type":{"type":"string","name":"lowercase","logicalType":"validated-string","pattern":"^[a-z]+$|^%d$"}},
+		{"name":"u","type":["null","long",{"type":"array","items":"string"}],"default":null},
+		{"name":"m","type":{"type":"map","values":"double"},"default":{}}
+	]}`
+
+	shared, err := NewCodec(fmt.Sprintf(schema, -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan error, 8)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			// NOTE: Codecs with distinct patterns are also built concurrently,
+			// to exercise any state shared during construction.
+			codec, err := NewCodec(fmt.Sprintf(schema, g))
+			if err != nil {
+				done <- err
+				return
+			}
+			for i := 0; i < 1000; i++ {
+				for _, c := range []*Codec{codec, shared} {
+					datum := map[string]interface{}{
+						"id": "abc",
+						"u":  Union("long", int64(g*i)),
+						"m":  map[string]interface{}{"k": float64(i)},
+					}
+					if err := c.Valid(datum); err != nil {
+						done <- err
+						return
+					}
+					buf, err := c.BinaryFromNative(nil, datum)
+					if err != nil {
+						done <- err
+						return
+					}
+					if _, _, err = c.NativeFromBinary(buf); err != nil {
+						done <- err
+						return
+					}
+					buf, err = c.TextualFromNative(nil, datum)
+					if err != nil {
+						done <- err
+						return
+					}
+					if _, _, err = c.NativeFromTextual(buf); err != nil {
+						done <- err
+						return
+					}
+					if _, _, err = c.NativeFromTextual([]byte(`{"id":"ABC","u":null,"m":{}}`)); err == nil {
+						done <- fmt.Errorf("GOT: %v; WANT: %v", err, "validation error")
+						return
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(done)
+	for err := range done {
+		t.Error(err)
+	}
+}
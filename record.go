@@ -11,16 +11,150 @@ package goavro
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 )
 
+// RecordField is a single field of a Record, holding its name and value.
+type RecordField struct {
+	Name  string
+	Value interface{}
+}
+
+// Record is the native form of a record decoded by a Codec created using
+// WithOrderedRecords, which holds its fields in order: the order they appear
+// in the text of a textual record, or the order of the schema for a binary
+// record. Any Codec may also encode a Record, and textual encoders write its
+// fields in the order they appear in it, followed by the default values of
+// any fields it lacks.
+type Record []RecordField
+
+// Get returns the value of the field of the record named name, and whether the
+// record has that field.
+func (r Record) Get(name string) (interface{}, bool) {
+	for _, field := range r {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// WithOrderedRecords returns a CodecOption which makes the record decoders of
+// the Codec return Record values rather than maps, preserving the order of
+// their fields, so a record decoded from text may be encoded again with its
+// fields in their original order.
+//
+//     codec, err := goavro.NewCodecWithOptions(`{"type":"record","name":"r1","fields":[{"name":"a","type":"int"},{"name":"b","type":"int"}]}`, goavro.WithOrderedRecords())
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     native, _, err := codec.NativeFromTextual([]byte(`{"b":2,"a":1}`))
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     text, err := codec.TextualFromNative(nil, native)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Println(string(text)) // {"b":2,"a":1}
+func WithOrderedRecords() CodecOption {
+	return func(o *codecOptions) {
+		o.orderedRecords = true
+	}
+}
+
+// recordNative returns the native form of a record decoded from binary into
+// recordMap, which is the map itself unless the record Codec was created using
+// WithOrderedRecords.
+func (c *Codec) recordNative(recordMap map[string]interface{}) interface{} {
+	if !c.orderedRecords {
+		return recordMap
+	}
+	record := make(Record, len(c.recordFields))
+	for i, field := range c.recordFields {
+		record[i] = RecordField{Name: field.name, Value: recordMap[field.name]}
+	}
+	return record
+}
+
+// recordValueMap returns the fields of a record datum as a map, when datum is
+// either a map or a Record.
+func recordValueMap(datum interface{}) (map[string]interface{}, bool) {
+	switch v := datum.(type) {
+	case map[string]interface{}:
+		return v, true
+	case Record:
+		valueMap := make(map[string]interface{}, len(v))
+		for _, field := range v {
+			valueMap[field.Name] = field.Value
+		}
+		return valueMap, true
+	}
+	return nil, false
+}
+
+// orderedFieldNames returns the names of the fields of the record Codec in the
+// order they appear in keys, followed by the names of the fields missing from
+// keys in schema order. Keys which do not name a field are skipped.
+func (c *Codec) orderedFieldNames(keys []string) []string {
+	names := make([]string, 0, len(c.recordFields))
+	found := make(map[string]bool, len(c.recordFields))
+	for _, field := range c.recordFields {
+		found[field.name] = false
+	}
+	for _, key := range keys {
+		if seen, ok := found[key]; ok && !seen {
+			found[key] = true
+			names = append(names, key)
+		}
+	}
+	for _, field := range c.recordFields {
+		if !found[field.name] {
+			names = append(names, field.name)
+		}
+	}
+	return names
+}
+
+// recordField describes a record field, for resolving binary data encoded using
+// a different writer schema.
+type recordField struct {
+	name         string
+	aliases      []string
+	codec        *Codec
+	defaultValue interface{}
+	hasDefault   bool
+	order        string // ascending, descending, or ignore
+}
+
+// recordFieldOrder returns the sort order of a record field, which is ascending
+// when its schema does not specify one. Like the Java implementation, the order
+// is matched regardless of case, and returned in lower case.
+func recordFieldOrder(fieldSchemaMap map[string]interface{}) (string, error) {
+	value, ok := fieldSchemaMap["order"]
+	if !ok {
+		return "ascending", nil
+	}
+	order, _ := value.(string)
+	switch order = strings.ToLower(order); order {
+	case "ascending", "descending", "ignore":
+		return order, nil
+	}
+	return "", fmt.Errorf("order ought to be ascending, descending, or ignore: %v", value)
+}
+
 func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap map[string]interface{}, cb *codecBuilder) (*Codec, error) {
 	// NOTE: To support recursive data types, create the codec and register it
 	// using the specified name, and fill in the codec functions later.
 	c, err := registerNewCodec(st, schemaMap, enclosingNamespace)
 	if err != nil {
-		return nil, fmt.Errorf("Record ought to have valid name: %s", err)
+		return nil, fmt.Errorf("Record ought to have valid name: %w", err)
 	}
 
+	c.schemaType = "record"
+
 	fields, ok := schemaMap["fields"]
 	if !ok {
 		return nil, fmt.Errorf("Record %q ought to have fields key", c.typeName)
@@ -30,10 +164,15 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 		return nil, fmt.Errorf("Record %q fields ought to be non-nil array: %v", c.typeName, fields)
 	}
 
+	c.recordFields = make([]*recordField, 0, len(fieldSchemas))
 	codecFromFieldName := make(map[string]*Codec)
 	codecFromIndex := make([]*Codec, len(fieldSchemas))
 	nameFromIndex := make([]string, len(fieldSchemas))
 	defaultValueFromName := make(map[string]interface{}, len(fieldSchemas))
+	// NOTE: Default values are also kept in binary form, so decoders return
+	// them in the same native form as decoded values, and as a new copy for
+	// each decoded record.
+	defaultBinaryFromName := make(map[string][]byte, len(fieldSchemas))
 
 	for i, fieldSchema := range fieldSchemas {
 		fieldSchemaMap, ok := fieldSchema.(map[string]interface{})
@@ -46,7 +185,7 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 
 		fieldCodec, err := buildCodec(st, c.typeName.namespace, fieldSchemaMap, cb)
 		if err != nil {
-			return nil, fmt.Errorf("Record %q field %d ought to be valid Avro named type: %s", c.typeName, i+1, err)
+			return nil, fmt.Errorf("Record %q field %d ought to be valid Avro named type: %w", c.typeName, i+1, err)
 		}
 
 		// However, when creating a full name for the field name, be sure to use
@@ -60,79 +199,43 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 			return nil, fmt.Errorf("Record %q field %d ought to have unique name: %q", c.typeName, i+1, fieldName)
 		}
 
-		if defaultValue, ok := fieldSchemaMap["default"]; ok {
-			typeNameShort := fieldCodec.typeName.short()
-			switch typeNameShort {
-			case "boolean":
-				v, ok := defaultValue.(bool)
-				if !ok {
-					return nil, fmt.Errorf("Record %q field %q: default value ought to encode using field schema: %s", c.typeName, fieldName, err)
-				}
-				defaultValue = bool(v)
-			case "bytes":
-				v, ok := defaultValue.(string)
-				if !ok {
-					return nil, fmt.Errorf("Record %q field %q: default value ought to encode using field schema: %s", c.typeName, fieldName, err)
-				}
-				defaultValue = []byte(v)
-			case "double":
-				v, ok := defaultValue.(float64)
-				if !ok {
-					return nil, fmt.Errorf("Record %q field %q: default value ought to encode using field schema: %s", c.typeName, fieldName, err)
-				}
-				defaultValue = float64(v)
-			case "float":
-				v, ok := defaultValue.(float64)
-				if !ok {
-					return nil, fmt.Errorf("Record %q field %q: default value ought to encode using field schema: %s", c.typeName, fieldName, err)
-				}
-				defaultValue = float32(v)
-			case "int":
-				v, ok := defaultValue.(float64)
-				if !ok {
-					return nil, fmt.Errorf("Record %q field %q: default value ought to encode using field schema: %s", c.typeName, fieldName, err)
-				}
-				defaultValue = int32(v)
-			case "long":
-				v, ok := defaultValue.(float64)
-				if !ok {
-					return nil, fmt.Errorf("Record %q field %q: default value ought to encode using field schema: %s", c.typeName, fieldName, err)
-				}
-				defaultValue = int64(v)
-			case "string":
-				v, ok := defaultValue.(string)
-				if !ok {
-					return nil, fmt.Errorf("Record %q field %q: default value ought to encode using field schema: %s", c.typeName, fieldName, err)
-				}
-				defaultValue = string(v)
-			case "union":
-				// When codec is union, then default value ought to encode using
-				// first schema in union.  NOTE: To support a null default
-				// value, the string literal "null" must be coerced to a `nil`
-				if defaultValue == "null" || defaultValue == nil {
-					defaultValue = nil
-				} else {
-					return nil, fmt.Errorf("unions with non null default values are not supported")
-				}
-			default:
-				debug("fieldName: %q; type: %q; defaultValue: %T(%#v)\n", fieldName, c.typeName, defaultValue, defaultValue)
+		if rawDefault, ok := fieldSchemaMap["default"]; ok {
+			defaultValue, err := decodeDefault(fieldCodec, rawDefault)
+			if err != nil {
+				return nil, fmt.Errorf("Record %q field %q: default value ought to encode using field schema: %w", c.typeName, fieldName, err)
 			}
 
 			// attempt to encode default value using codec
-			_, err = fieldCodec.binaryFromNative(nil, defaultValue)
+			defaultBinary, err := fieldCodec.binaryFromNative(nil, defaultValue)
 			if err != nil {
-				return nil, fmt.Errorf("Record %q field %q: default value ought to encode using field schema: %s", c.typeName, fieldName, err)
+				return nil, fmt.Errorf("Record %q field %q: default value ought to encode using field schema: %w", c.typeName, fieldName, err)
 			}
 			defaultValueFromName[fieldName] = defaultValue
+			defaultBinaryFromName[fieldName] = defaultBinary
+		}
+
+		order, err := recordFieldOrder(fieldSchemaMap)
+		if err != nil {
+			return nil, fmt.Errorf("Record %q field %q: %w", c.typeName, fieldName, err)
 		}
 
 		nameFromIndex[i] = fieldName
 		codecFromIndex[i] = fieldCodec
 		codecFromFieldName[fieldName] = fieldCodec
+
+		defaultValue, hasDefault := defaultValueFromName[fieldName]
+		c.recordFields = append(c.recordFields, &recordField{
+			name:         fieldName,
+			aliases:      schemaAliases(fieldSchemaMap, nullNamespace),
+			codec:        fieldCodec,
+			defaultValue: defaultValue,
+			hasDefault:   hasDefault,
+			order:        order,
+		})
 	}
 
 	c.binaryFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
-		valueMap, ok := datum.(map[string]interface{})
+		valueMap, ok := recordValueMap(datum)
 		if !ok {
 			return nil, fmt.Errorf("cannot encode binary record %q: expected map[string]interface{}; received: %T", c.typeName, datum)
 		}
@@ -155,42 +258,70 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 			var err error
 			buf, err = fieldCodec.binaryFromNative(buf, fieldValue)
 			if err != nil {
-				return nil, fmt.Errorf("cannot encode binary record %q field %q: value does not match its schema: %s", c.typeName, fieldName, err)
+				return nil, fmt.Errorf("cannot encode binary record %q field %q: value does not match its schema: %w", c.typeName, fieldName, err)
 			}
 		}
 		return buf, nil
 	}
 
-	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
-		recordMap := make(map[string]interface{}, len(codecFromIndex))
+	recordFromBinary := func(buf []byte, recordMap map[string]interface{}) ([]byte, error) {
+		remaining := buf
 		for i, fieldCodec := range codecFromIndex {
 			name := nameFromIndex[i]
-			var value interface{}
-			var err error
-			value, buf, err = fieldCodec.nativeFromBinary(buf)
+			value, newBuf, err := fieldCodec.nativeFromBinary(remaining)
 			if err != nil {
-				return nil, nil, fmt.Errorf("cannot decode binary record %q field %q: %s", c.typeName, name, err)
+				return nil, newDecodeError(err, fmt.Errorf("cannot decode binary record %q field %q: %w", c.typeName, name, err), "."+name, len(buf)-len(remaining))
 			}
 			recordMap[name] = value
+			remaining = newBuf
 		}
-		return recordMap, buf, nil
+		return remaining, nil
 	}
+	c.recordFromBinary = recordFromBinary
 
-	c.nativeFromTextual = func(buf []byte) (interface{}, []byte, error) {
+	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
+		recordMap := make(map[string]interface{}, len(codecFromIndex))
+		remaining, err := recordFromBinary(buf, recordMap)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c.recordNative(recordMap), remaining, nil
+	}
+
+	// NOTE: The standard parameter selects whether union field values are
+	// decoded from textual Avro data or from standard JSON.
+	nativeFromTextual := func(buf []byte, standard bool) (interface{}, []byte, error) {
 		var mapValues map[string]interface{}
+		var keys *[]string
 		var err error
+		if c.orderedRecords {
+			keys = new([]string)
+		}
 		// NOTE: Setting `defaultCodec == nil` instructs genericMapTextDecoder
 		// to return an error when a field name is not found in the
 		// codecFromFieldName map.
-		mapValues, buf, err = genericMapTextDecoder(buf, nil, codecFromFieldName)
+		mapValues, buf, err = genericMapTextKeysDecoder(buf, nil, codecFromFieldName, keys, standard)
 		if err != nil {
-			return nil, nil, fmt.Errorf("cannot decode textual record %q: %s", c.typeName, err)
+			return nil, nil, fmt.Errorf("cannot decode textual record %q: %w", c.typeName, err)
 		}
 		if actual, expected := len(mapValues), len(codecFromFieldName); actual != expected {
 			// set missing field keys to their respective default values, then
 			// re-check number of keys
-			for fieldName, defaultValue := range defaultValueFromName {
-				if _, ok := mapValues[fieldName]; !ok {
+			for fieldName, defaultBinary := range defaultBinaryFromName {
+				if _, ok := mapValues[fieldName]; ok {
+					continue
+				}
+				fieldCodec := codecFromFieldName[fieldName]
+				defaultValue, _, err := fieldCodec.nativeFromBinary(defaultBinary)
+				if err != nil {
+					return nil, nil, fmt.Errorf("cannot decode textual record %q field %q default value: %w", c.typeName, fieldName, err)
+				}
+				if fieldCodec.unionInfo != nil {
+					// NOTE: Match the form genericMapTextDecoder uses for
+					// union field values.
+					defaultValue = unionMapFromNative(fieldCodec.unionInfo, defaultValue)
+					mapValues[fieldName] = &defaultValue
+				} else {
 					mapValues[fieldName] = defaultValue
 				}
 			}
@@ -198,25 +329,55 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 				return nil, nil, fmt.Errorf("cannot decode textual record %q: only found %d of %d fields", c.typeName, actual, expected)
 			}
 		}
+		if keys != nil {
+			// NOTE: Fields take the order of their keys in the text, followed
+			// by the fields given their default values.
+			names := c.orderedFieldNames(*keys)
+			record := make(Record, len(names))
+			for i, name := range names {
+				record[i] = RecordField{Name: name, Value: mapValues[name]}
+			}
+			return record, buf, nil
+		}
 		return mapValues, buf, nil
 	}
 
-	c.textualFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
+	c.nativeFromTextual = func(buf []byte) (interface{}, []byte, error) {
+		return nativeFromTextual(buf, false)
+	}
+	c.nativeFromTextualStandard = func(buf []byte) (interface{}, []byte, error) {
+		return nativeFromTextual(buf, true)
+	}
+
+	// NOTE: The standard parameter selects whether union field values are
+	// encoded as textual Avro data or as standard JSON.
+	textualFromNative := func(buf []byte, datum interface{}, standard bool) ([]byte, error) {
 		// NOTE: Ensure only schema defined field names are encoded; and if
 		// missing in datum, either use the provided field default value or
 		// return an error.
-		destMap := make(map[string]interface{}, len(codecFromIndex))
-
 		isNil := datum == nil
-		sourceMap, ok := datum.(map[string]interface{})
+		sourceMap, ok := recordValueMap(datum)
 		if !ok && !isNil {
 			return nil, fmt.Errorf("cannot encode textual record %q: expected map[string]interface{}; received: %T", c.typeName, datum)
 		} else if isNil {
 			//https://birdco.atlassian.net/browse/ENG-11238
 			return nullTextualFromNative(buf, datum)
-			//return genericMapTextEncoder(buf, destMap, nil, codecFromFieldName)
+			//return genericMapTextEncoder(buf, destMap, nil, codecFromFieldName, standard)
 		}
-		for fieldName := range codecFromFieldName {
+		// NOTE: Fields are encoded in the order they were defined in the
+		// schema, rather than in the random order of the map, so the same
+		// record always encodes to the same text. The fields of a Record are
+		// encoded in its own order.
+		names := nameFromIndex
+		if record, isRecord := datum.(Record); isRecord {
+			keys := make([]string, len(record))
+			for i, field := range record {
+				keys[i] = field.Name
+			}
+			names = c.orderedFieldNames(keys)
+		}
+		buf = append(buf, '{')
+		for i, fieldName := range names {
 			fieldValue, ok := sourceMap[fieldName]
 			if !ok {
 				defaultValue, ok := defaultValueFromName[fieldName]
@@ -225,14 +386,275 @@ func makeRecordCodec(st map[string]*Codec, enclosingNamespace string, schemaMap
 				}
 				fieldValue = defaultValue
 			}
-			destMap[fieldName] = fieldValue
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			var err error
+			if buf, err = genericMapTextEntryEncoder(buf, fieldName, fieldValue, codecFromFieldName[fieldName], standard); err != nil {
+				return nil, err
+			}
 		}
-		datum = destMap
-		// NOTE: Setting `defaultCodec == nil` instructs genericMapTextEncoder
-		// to return an error when a field name is not found in the
-		// codecFromFieldName map.
-		return genericMapTextEncoder(buf, datum, nil, codecFromFieldName)
+		return append(buf, '}'), nil
+	}
+
+	c.textualFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
+		return textualFromNative(buf, datum, false)
+	}
+	c.textualStandardFromNative = func(buf []byte, datum interface{}) ([]byte, error) {
+		return textualFromNative(buf, datum, true)
 	}
 
 	return c, nil
 }
+
+// unknownFieldNames returns the sorted keys of valueMap which do not name a
+// field of the record, or nil when there are none.
+func (c *Codec) unknownFieldNames(valueMap map[string]interface{}) []string {
+	var names []string
+	for key := range valueMap {
+		known := false
+		for _, field := range c.recordFields {
+			if field.name == key {
+				known = true
+				break
+			}
+		}
+		if !known {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkRecordFields returns an error when datum, or a value nested within it,
+// holds a key which does not name a field of the record it encodes as. A Codec
+// created using WithStrictRecordFields checks the datum before encoding it, so
+// Codecs differing only by that option share the codecs of their schema.
+func (c *Codec) checkRecordFields(datum interface{}, encoding string) error {
+	if c.annotated != nil {
+		// NOTE: Check the value of the type a registered logical type
+		// annotates, which is what its encoders encode.
+		value, err := c.registeredDatum(encoding, datum)
+		if err != nil {
+			return nil // NOTE: the encoder of the logical type returns its own error
+		}
+		return c.annotated.checkRecordFields(value, encoding)
+	}
+	switch {
+	case c.unionInfo != nil:
+		index, value, err := unionMemberFromNative(c.unionInfo, datum)
+		if err != nil {
+			return nil // NOTE: the union encoder returns its own error
+		}
+		return c.unionInfo.codecFromIndex[index].checkRecordFields(value, encoding)
+	case c.schemaType == "record":
+		valueMap, ok := recordValueMap(datum)
+		if !ok {
+			return nil // NOTE: the record encoder returns its own error
+		}
+		if names := c.unknownFieldNames(valueMap); names != nil {
+			return fmt.Errorf("cannot encode %s record %q: fields ought to be defined in schema: %q", encoding, c.typeName, names)
+		}
+		for _, field := range c.recordFields {
+			if value, ok := valueMap[field.name]; ok {
+				if err := field.codec.checkRecordFields(value, encoding); err != nil {
+					return fmt.Errorf("cannot encode %s record %q field %q: %w", encoding, c.typeName, field.name, err)
+				}
+			}
+		}
+	case c.itemCodec != nil && c.typeName.fullName == "array":
+		items, err := convertArray(datum)
+		if err != nil {
+			return nil // NOTE: the array encoder returns its own error
+		}
+		for i, item := range items {
+			if err := c.itemCodec.checkRecordFields(item, encoding); err != nil {
+				return fmt.Errorf("cannot encode %s array item %d: %v: %w", encoding, i+1, item, err)
+			}
+		}
+	case c.itemCodec != nil && c.typeName.fullName == "map":
+		values, err := convertMap(datum)
+		if err != nil {
+			return nil // NOTE: the map encoder returns its own error
+		}
+		for key, value := range values {
+			if err := c.itemCodec.checkRecordFields(value, encoding); err != nil {
+				return fmt.Errorf("cannot encode %s map value for key %q: %v: %w", encoding, key, value, err)
+			}
+		}
+	}
+	return nil
+}
+
+// decodeDefault returns the native value of a default value from the JSON of a
+// schema, converted for the codec of the field it is the default of.
+//
+// NOTE: The Avro specification writes the defaults of bytes and fixed fields
+// as strings whose code points 0-255 are the bytes, the defaults of union
+// fields using the first member of the union, and the defaults of record
+// fields as JSON objects whose missing fields take their own default values.
+func decodeDefault(codec *Codec, rawDefault interface{}) (interface{}, error) {
+	if cr := codec.unionInfo; cr != nil {
+		// NOTE: The Avro specification requires the default value of a union
+		// to be a value of its first member, wherever null was declared in
+		// the union.
+		if cr.allowedTypes[0] == "null" {
+			if rawDefault == nil || rawDefault == "null" {
+				// NOTE: To support a null default value, the string literal
+				// "null" must be coerced to a `nil`
+				return nil, nil
+			}
+			return nil, fmt.Errorf("union default ought to be null, which is its first member; received: %T", rawDefault)
+		}
+		// NOTE: A default may explicitly name the first member.
+		if m, ok := rawDefault.(map[string]interface{}); ok && len(m) == 1 {
+			if value, ok := m[cr.allowedTypes[0]]; ok {
+				return unionDefault(cr, 0, value)
+			}
+		}
+		if rawDefault == nil {
+			return nil, fmt.Errorf("union default ought to be value of its first member: %q; received: null", cr.allowedTypes[0])
+		}
+		return unionDefault(cr, 0, rawDefault)
+	}
+
+	switch codec.schemaType {
+	case "record":
+		m, ok := rawDefault.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("record default ought to be JSON object; received: %T", rawDefault)
+		}
+		record := make(map[string]interface{}, len(codec.recordFields))
+		for _, field := range codec.recordFields {
+			raw, ok := m[field.name]
+			if !ok {
+				if !field.hasDefault {
+					return nil, fmt.Errorf("record %q default ought to have field %q, which has no default value", codec.typeName, field.name)
+				}
+				record[field.name] = field.defaultValue
+				continue
+			}
+			value, err := decodeDefault(field.codec, raw)
+			if err != nil {
+				return nil, fmt.Errorf("record %q default field %q: %w", codec.typeName, field.name, err)
+			}
+			record[field.name] = value
+		}
+		return record, nil
+	case "enum":
+		symbol, ok := rawDefault.(string)
+		if !ok {
+			return nil, fmt.Errorf("enum default ought to be string; received: %T", rawDefault)
+		}
+		return symbol, nil
+	case "fixed":
+		return bytesFromDefault(rawDefault)
+	}
+
+	if codec.itemCodec != nil {
+		switch codec.typeName.fullName {
+		case "array":
+			items, ok := rawDefault.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("array default ought to be JSON array; received: %T", rawDefault)
+			}
+			array := make([]interface{}, len(items))
+			for i, item := range items {
+				value, err := decodeDefault(codec.itemCodec, item)
+				if err != nil {
+					return nil, fmt.Errorf("array default item %d: %w", i+1, err)
+				}
+				array[i] = value
+			}
+			return array, nil
+		case "map":
+			values, ok := rawDefault.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("map default ought to be JSON object; received: %T", rawDefault)
+			}
+			m := make(map[string]interface{}, len(values))
+			for key, raw := range values {
+				value, err := decodeDefault(codec.itemCodec, raw)
+				if err != nil {
+					return nil, fmt.Errorf("map default value %q: %w", key, err)
+				}
+				m[key] = value
+			}
+			return m, nil
+		}
+	}
+
+	switch codec.typeName.short() {
+	case "boolean":
+		if v, ok := rawDefault.(bool); ok {
+			return v, nil
+		}
+	case "bytes":
+		return bytesFromDefault(rawDefault)
+	case "double":
+		if v, ok := rawDefault.(float64); ok {
+			return v, nil
+		}
+	case "float":
+		if v, ok := rawDefault.(float64); ok {
+			return float32(v), nil
+		}
+	case "int":
+		if v, ok := rawDefault.(float64); ok {
+			return int32(v), nil
+		}
+	case "long":
+		if v, ok := rawDefault.(float64); ok {
+			return int64(v), nil
+		}
+	case "string":
+		if v, ok := rawDefault.(string); ok {
+			return v, nil
+		}
+	default:
+		// NOTE: The encoders of logical types accept the values of their
+		// underlying types.
+		return rawDefault, nil
+	}
+	return nil, fmt.Errorf("%s default ought to be valid JSON value; received: %T", codec.typeName.short(), rawDefault)
+}
+
+// unionDefault returns the native value of the default value of a union, which
+// encodes using the member at index.
+func unionDefault(cr *codecInfo, index int, rawDefault interface{}) (interface{}, error) {
+	value, err := decodeDefault(cr.codecFromIndex[index], rawDefault)
+	if err != nil {
+		return nil, err
+	}
+	return Union(cr.allowedTypes[index], value), nil
+}
+
+// bytesFromDefault returns the bytes of a bytes or fixed default value, which is
+// a string whose code points 0-255 are the bytes.
+func bytesFromDefault(rawDefault interface{}) ([]byte, error) {
+	s, ok := rawDefault.(string)
+	if !ok {
+		return nil, fmt.Errorf("bytes default ought to be string; received: %T", rawDefault)
+	}
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xff {
+			return nil, fmt.Errorf("bytes default ought to have code points between 0 and 255; received: %#U", r)
+		}
+		b = append(b, byte(r))
+	}
+	return b, nil
+}
+
+// unionMapFromNative returns the single key map form of a union value decoded
+// from binary, which returns the value of a nullable union as a pointer.
+func unionMapFromNative(cr *codecInfo, datum interface{}) interface{} {
+	if datum == nil {
+		return nil
+	}
+	if index, ok := cr.nullableIndex(); ok {
+		return Union(cr.allowedTypes[index], reflect.ValueOf(datum).Elem().Interface())
+	}
+	return datum
+}
@@ -12,6 +12,7 @@ package goavro
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -296,6 +297,34 @@ func TestSchemaRecordNamespaceFixed(t *testing.T) {
 ]}`)
 }
 
+func TestSchemaRecordNamespaceShortNamePrecedence(t *testing.T) {
+	// "X" refers to com.example.X within the com.example namespace, even
+	// though a type named X is also defined in the null namespace.
+	schema := `{"type":"record","name":"r1","fields":[
+  {"name":"a","type":{"type":"fixed","name":"X","size":1}},
+  {"name":"b","type":{"type":"record","name":"Inner","namespace":"com.example","fields":[
+    {"name":"c","type":{"type":"fixed","name":"X","size":2}},
+    {"name":"d","type":"X"}
+  ]}},
+  {"name":"e","type":"X"}
+]}`
+	testBinaryCodecPass(t, schema, map[string]interface{}{
+		"a": []byte("a"),
+		"b": map[string]interface{}{"c": []byte("cc"), "d": []byte("dd")},
+		"e": []byte("e"),
+	}, []byte("accdde"))
+
+	codec, err := NewCodec(schema)
+	ensureError(t, err)
+	inner := codec.ParsedSchema().(*RecordSchema).Fields[1].Type.(*RecordSchema)
+	if actual, expected := inner.Fields[1].Type.(*FixedSchema).FullName, "com.example.X"; actual != expected {
+		t.Errorf("GOT: %q; WANT: %q", actual, expected)
+	}
+	if actual, expected := codec.CanonicalSchema(), `{"name":"r1","type":"record","fields":[{"name":"a","type":{"name":"X","type":"fixed","size":1}},{"name":"b","type":{"name":"com.example.Inner","type":"record","fields":[{"name":"c","type":{"name":"com.example.X","type":"fixed","size":2}},{"name":"d","type":"com.example.X"}]}},{"name":"e","type":"X"}]}`; actual != expected {
+		t.Errorf("GOT: %s; WANT: %s", actual, expected)
+	}
+}
+
 func TestRecordNamespace(t *testing.T) {
 	c, err := NewCodec(`{
   "type": "record",
@@ -396,6 +425,23 @@ func TestRecordFieldUnionDefaultValue(t *testing.T) {
 	testSchemaValid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":["null","int"],"default":null}]}`)
 }
 
+func TestRecordError(t *testing.T) {
+	schema := `{"type":"error","name":"Oops","fields":[{"name":"message","type":"string"}]}`
+	testBinaryCodecPass(t, schema, map[string]interface{}{"message": "boom"}, []byte("\x08boom"))
+	testTextCodecPass(t, schema, map[string]interface{}{"message": "boom"}, []byte(`{"message":"boom"}`))
+
+	// an error may be referenced by name like any other record
+	testBinaryCodecPass(t, `{"type":"record","name":"r1","fields":[{"name":"e","type":["string",`+schema+`]},{"name":"f","type":"Oops"}]}`,
+		map[string]interface{}{"e": Union("Oops", map[string]interface{}{"message": "boom"}), "f": map[string]interface{}{"message": "bang"}},
+		[]byte("\x02\x08boom\x08bang"))
+
+	codec, err := NewCodec(schema)
+	ensureError(t, err)
+	if actual, expected := codec.ParsedSchema().Type(), "record"; actual != expected {
+		t.Errorf("GOT: %q; WANT: %q", actual, expected)
+	}
+}
+
 func TestRecordRecursiveRoundTrip(t *testing.T) {
 	codec, err := NewCodec(`
 {
@@ -435,6 +481,62 @@ func TestRecordRecursiveRoundTrip(t *testing.T) {
 	}
 }
 
+func TestRecordRecursiveDepth(t *testing.T) {
+	const schema = `{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`
+	cases := []struct {
+		datum    interface{}
+		binary   string
+		standard string
+		textual  string
+	}{
+		{
+			datum:    map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": nil})},
+			binary:   "\x02\x00",
+			standard: `{"next":{"next":null}}`,
+			textual:  `{"next":{"LongList":{"next":null}}}`,
+		},
+		{
+			datum:    map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": nil})})},
+			binary:   "\x02\x02\x00",
+			standard: `{"next":{"next":{"next":null}}}`,
+			textual:  `{"next":{"LongList":{"next":{"LongList":{"next":null}}}}}`,
+		},
+	}
+
+	codec, err := NewCodec(schema)
+	ensureError(t, err)
+	standardCodec, err := NewCodecForStandardJSON(schema)
+	ensureError(t, err)
+
+	for _, c := range cases {
+		buf, err := codec.BinaryFromNative(nil, c.datum)
+		ensureError(t, err)
+		if actual, expected := string(buf), c.binary; actual != expected {
+			t.Errorf("GOT: %q; WANT: %q", actual, expected)
+		}
+		datum, _, err := codec.NativeFromBinary(buf)
+		ensureError(t, err)
+		buf, err = codec.TextualFromNative(nil, datum)
+		ensureError(t, err)
+		if actual, expected := string(buf), c.textual; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+
+		datum, _, err = standardCodec.NativeFromTextual([]byte(c.standard))
+		ensureError(t, err)
+		buf, err = standardCodec.BinaryFromNative(nil, datum)
+		ensureError(t, err)
+		if actual, expected := string(buf), c.binary; actual != expected {
+			t.Errorf("GOT: %q; WANT: %q", actual, expected)
+		}
+		buf, err = standardCodec.TextualFromNativeStandard(nil, datum)
+		ensureError(t, err)
+		if actual, expected := string(buf), c.standard; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	}
+}
+
 func ExampleRecordRecursiveRoundTrip() {
 	codec, err := NewCodec(`
 {
@@ -672,8 +774,6 @@ func TestRecordFieldDefaultValueTypes(t *testing.T) {
 	})
 
 	t.Run("union of int and long", func(t *testing.T) {
-		t.Skip("FIXME: should encode default value as int64 rather than float64")
-
 		codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":["int","long"],"default":13}]}`)
 		ensureError(t, err)
 
@@ -682,28 +782,283 @@ func TestRecordFieldDefaultValueTypes(t *testing.T) {
 
 		r1m := r1.(map[string]interface{})
 
-		someUnion := r1m["f1"]
-		someMap, ok := someUnion.(map[string]interface{})
+		// NOTE: Union field values are decoded as a pointer to their value,
+		// whether provided or set to the default value.
+		someUnion, ok := r1m["f1"].(*interface{})
+		if !ok {
+			t.Fatalf("GOT: %T; WANT: *interface{}", r1m["f1"])
+		}
+		someMap, ok := (*someUnion).(map[string]interface{})
 		if !ok {
-			t.Fatalf("GOT: %T; WANT: map[string]interface{}", someUnion)
+			t.Fatalf("GOT: %T; WANT: map[string]interface{}", *someUnion)
 		}
 		if got, want := len(someMap), 1; got != want {
 			t.Errorf("GOT: %v; WANT: %v", got, want)
 		}
-		t.Logf("someMap: %#v", someMap)
 		for k, v := range someMap {
 			// The "int" type is the first type option of the union.
 			if got, want := k, "int"; got != want {
 				t.Errorf("GOT: %v; WANT: %v", got, want)
 			}
 			switch tv := v.(type) {
-			case int64:
-				if got, want := tv, int64(13); got != want {
+			case int32:
+				if got, want := tv, int32(13); got != want {
 					t.Errorf("GOT: %v; WANT: %v", got, want)
 				}
 			default:
-				t.Errorf("GOT: %T; WANT: int64", v)
+				t.Errorf("GOT: %T; WANT: int32", v)
 			}
 		}
 	})
+
+	t.Run("nested record", func(t *testing.T) {
+		codec, err := NewCodec(`{"type":"record","name":"r1","fields":[
+			{"name":"f1","type":{"type":"record","name":"r2","fields":[{"name":"f2","type":"int","default":3},{"name":"f3","type":["null","string"],"default":null}]},"default":{"f2":7}},
+			{"name":"f4","type":"r2"}
+		]}`)
+		ensureError(t, err)
+
+		r1, _, err := codec.NativeFromTextual([]byte(`{"f4":{}}`))
+		ensureError(t, err)
+
+		r1m := r1.(map[string]interface{})
+		if got, want := fmt.Sprintf("%v", dereferenceUnionFields(r1m)), "map[f1:map[f2:7 f3:<nil>] f4:map[f2:3 f3:<nil>]]"; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+		if got, ok := r1m["f1"].(map[string]interface{})["f2"].(int32); !ok || got != 7 {
+			t.Errorf("GOT: %T(%v); WANT: int32(7)", got, got)
+		}
+	})
+
+	t.Run("default value not shared", func(t *testing.T) {
+		codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"array","items":"int"},"default":[1]}]}`)
+		ensureError(t, err)
+
+		first, _, err := codec.NativeFromTextual([]byte("{}"))
+		ensureError(t, err)
+		first.(map[string]interface{})["f1"].([]interface{})[0] = int32(42)
+
+		second, _, err := codec.NativeFromTextual([]byte("{}"))
+		ensureError(t, err)
+		if got, want := fmt.Sprintf("%v", second), "map[f1:[1]]"; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+	})
+
+	t.Run("all types", func(t *testing.T) {
+		const reader = `{"type":"record","name":"r1","fields":[
+			{"name":"someArray","type":{"type":"array","items":"long"},"default":[1,2]},
+			{"name":"emptyArray","type":{"type":"array","items":"string"},"default":[]},
+			{"name":"someMap","type":{"type":"map","values":"float"},"default":{"a":1.5}},
+			{"name":"emptyMap","type":{"type":"map","values":"int"},"default":{}},
+			{"name":"someRecord","type":{"type":"record","name":"r2","fields":[{"name":"a","type":"int"},{"name":"b","type":"string","default":"bee"}]},"default":{"a":3}},
+			{"name":"someEnum","type":{"type":"enum","name":"e1","symbols":["alpha","bravo"]},"default":"bravo"},
+			{"name":"someFixed","type":{"type":"fixed","name":"f1","size":2},"default":"\u00ffa"},
+			{"name":"someBytes","type":"bytes","default":"\u00ff\u0000"},
+			{"name":"someUnion","type":[{"type":"array","items":"int"},"null"],"default":[4]}
+		]}`
+		const expected = "map[emptyArray:[] emptyMap:map[] someArray:[1 2] someBytes:[255 0] someEnum:bravo someFixed:[255 97] someMap:map[a:1.5] someRecord:map[a:3 b:bee] someUnion:map[array:[4]]]"
+
+		codec, err := NewCodec(reader)
+		ensureError(t, err)
+		r1, _, err := codec.NativeFromTextual([]byte("{}"))
+		ensureError(t, err)
+		if got := fmt.Sprintf("%v", dereferenceUnionFields(r1.(map[string]interface{}))); got != expected {
+			t.Errorf("GOT: %v; WANT: %v", got, expected)
+		}
+
+		// reader fields missing from the writer take the same default values
+		resolved, err := NewCodecForReaderWriter(reader, `{"type":"record","name":"r1","fields":[]}`)
+		ensureError(t, err)
+		r1, _, err = resolved.NativeFromBinary(nil)
+		ensureError(t, err)
+		r1m := r1.(map[string]interface{})
+		r1m["someUnion"] = unionMapFromNative(resolved.recordFields[8].codec.unionInfo, r1m["someUnion"])
+		if got := fmt.Sprintf("%v", r1m); got != expected {
+			t.Errorf("GOT: %v; WANT: %v", got, expected)
+		}
+
+		// encoding a record without values uses the default values
+		buf, err := codec.BinaryFromNative(nil, map[string]interface{}{})
+		ensureError(t, err)
+		if want := []byte("\x04\x02\x04\x00\x00\x02\x02a\x00\x00\xc0?\x00\x00\x06\x06bee\x02\xffa\x04\xff\x00\x00\x02\x08\x00"); !bytes.Equal(buf, want) {
+			t.Errorf("GOT: %#v; WANT: %#v", buf, want)
+		}
+	})
+
+	t.Run("invalid complex defaults", func(t *testing.T) {
+		testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"array","items":"int"},"default":{}}]}`,
+			`field "f1": default value ought to encode using field schema: array default ought to be JSON array; received: map[string]interface {}`)
+		testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"map","values":"int"},"default":{"a":"b"}}]}`,
+			`map default value "a": int default ought to be valid JSON value; received: string`)
+		testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"record","name":"r2","fields":[{"name":"a","type":"int"}]},"default":{}}]}`,
+			`record "r2" default ought to have field "a", which has no default value`)
+		testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"fixed","name":"f1","size":1},"default":"\u0100"}]}`,
+			`bytes default ought to have code points between 0 and 255; received: U+0100`)
+		testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"fixed","name":"f1","size":2},"default":"a"}]}`,
+			`field "f1": default value ought to encode using field schema`)
+		testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"enum","name":"e1","symbols":["a"]},"default":"b"}]}`,
+			`field "f1": default value ought to encode using field schema`)
+	})
+}
+
+func TestRecordTextualFieldOrder(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[
+		{"name":"zulu","type":"int"},
+		{"name":"alpha","type":"string"},
+		{"name":"mike","type":["null","long"]},
+		{"name":"bravo","type":{"type":"record","name":"r2","fields":[{"name":"yankee","type":"int"},{"name":"charlie","type":"int"}]}},
+		{"name":"xray","type":"boolean","default":true},
+		{"name":"delta","type":"double"},
+		{"name":"echo","type":"float"},
+		{"name":"foxtrot","type":"bytes"}
+	]}`)
+	ensureError(t, err)
+
+	datum := map[string]interface{}{
+		"foxtrot": []byte("f"),
+		"echo":    1.5,
+		"delta":   2.5,
+		"bravo":   map[string]interface{}{"charlie": 3, "yankee": 4},
+		"mike":    Union("long", 5),
+		"alpha":   "a",
+		"zulu":    6,
+	}
+
+	// fields are encoded in the order of the schema, every time
+	for i := 0; i < 50; i++ {
+		buf, err := codec.TextualFromNative(nil, datum)
+		ensureError(t, err)
+		if actual, expected := string(buf), `{"zulu":6,"alpha":"a","mike":{"long":5},"bravo":{"yankee":4,"charlie":3},"xray":true,"delta":2.5,"echo":1.5,"foxtrot":"f"}`; actual != expected {
+			t.Fatalf("GOT: %s; WANT: %s", actual, expected)
+		}
+		buf, err = codec.TextualFromNativeStandard(nil, datum)
+		ensureError(t, err)
+		if actual, expected := string(buf), `{"zulu":6,"alpha":"a","mike":5,"bravo":{"yankee":4,"charlie":3},"xray":true,"delta":2.5,"echo":1.5,"foxtrot":"f"}`; actual != expected {
+			t.Fatalf("GOT: %s; WANT: %s", actual, expected)
+		}
+	}
+}
+
+func TestRecordStrictFields(t *testing.T) {
+	schema := `{"type":"record","name":"r1","fields":[
+		{"name":"name","type":"string"},
+		{"name":"nickname","type":"string","default":""},
+		{"name":"friends","type":{"type":"array","items":"r1"},"default":[]}
+	]}`
+	// a misspelled key is ignored by default, so its field uses its default
+	datum := map[string]interface{}{"name": "Ann", "nickanme": "A", "age": 42}
+	codec, err := NewCodec(schema)
+	ensureError(t, err)
+	_, err = codec.BinaryFromNative(nil, datum)
+	ensureError(t, err)
+
+	strict, err := NewCodecWithOptions(schema, WithStrictRecordFields(true))
+	ensureError(t, err)
+	_, err = strict.BinaryFromNative(nil, datum)
+	ensureError(t, err, `cannot encode binary record "r1": fields ought to be defined in schema: ["age" "nickanme"]`)
+	_, err = strict.TextualFromNative(nil, datum)
+	ensureError(t, err, `cannot encode textual record "r1": fields ought to be defined in schema: ["age" "nickanme"]`)
+
+	// nested records are strict as well
+	nested := map[string]interface{}{"name": "Ann", "friends": []interface{}{map[string]interface{}{"name": "Bob", "nickanme": "B"}}}
+	_, err = strict.BinaryFromNative(nil, nested)
+	ensureError(t, err, `field "friends"`, `fields ought to be defined in schema: ["nickanme"]`)
+
+	valid := map[string]interface{}{"name": "Ann", "nickname": "A"}
+	_, err = strict.BinaryFromNative(nil, valid)
+	ensureError(t, err)
+	_, err = strict.TextualFromNative(nil, valid)
+	ensureError(t, err)
+
+	// records annotated by a registered logical type are checked as encoded
+	RegisterLogicalType("test-strict-point", func(native interface{}) (interface{}, error) {
+		xy := native.([2]int)
+		if xy[1] == 0 {
+			return map[string]interface{}{"x": xy[0]}, nil
+		}
+		return map[string]interface{}{"x": xy[0], "z": xy[1]}, nil
+	}, nil)
+	strict, err = NewCodecWithOptions(`{"type":"record","name":"r1","fields":[{"name":"p","type":{"type":"record","name":"point","fields":[{"name":"x","type":"int"}],"logicalType":"test-strict-point"}}]}`, WithStrictRecordFields(true))
+	ensureError(t, err)
+	_, err = strict.BinaryFromNative(nil, map[string]interface{}{"p": [2]int{1, 0}})
+	ensureError(t, err)
+	_, err = strict.BinaryFromNative(nil, map[string]interface{}{"p": [2]int{1, 2}})
+	ensureError(t, err, `cannot encode binary record "r1" field "p"`, `fields ought to be defined in schema: ["z"]`)
+}
+
+func TestRecordOrdered(t *testing.T) {
+	schema := `{"type":"record","name":"r1","fields":[
+		{"name":"a","type":"int"},
+		{"name":"b","type":"string","default":"none"},
+		{"name":"c","type":{"type":"array","items":{"type":"record","name":"r2","fields":[{"name":"x","type":"int"},{"name":"y","type":"int"}]}}}
+	]}`
+	codec, err := NewCodecWithOptions(schema, WithOrderedRecords())
+	ensureError(t, err)
+
+	// textual records keep the order of their keys, and round trip unchanged
+	text := []byte(`{"c":[{"y":2,"x":1}],"b":"some string","a":3}`)
+	native, _, err := codec.NativeFromTextual(text)
+	ensureError(t, err)
+	expected := Record{
+		{Name: "c", Value: []interface{}{Record{{Name: "y", Value: int32(2)}, {Name: "x", Value: int32(1)}}}},
+		{Name: "b", Value: "some string"},
+		{Name: "a", Value: int32(3)},
+	}
+	if !reflect.DeepEqual(native, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", native, expected)
+	}
+	buf, err := codec.TextualFromNative(nil, native)
+	ensureError(t, err)
+	if actual, expected := string(buf), string(text); actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	// fields given their default values follow those in the text
+	native, _, err = codec.NativeFromTextual([]byte(`{"c":[],"a":3}`))
+	ensureError(t, err)
+	buf, err = codec.TextualFromNative(nil, native)
+	ensureError(t, err)
+	if actual, expected := string(buf), `{"c":[],"a":3,"b":"none"}`; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	// binary records are encoded in schema order, and decode in that order
+	buf, err = codec.BinaryFromNative(nil, expected)
+	ensureError(t, err)
+	plain, err := NewCodec(schema)
+	ensureError(t, err)
+	mapBuf, err := plain.BinaryFromNative(nil, map[string]interface{}{
+		"a": 3, "b": "some string", "c": []interface{}{map[string]interface{}{"x": 1, "y": 2}},
+	})
+	ensureError(t, err)
+	if !bytes.Equal(buf, mapBuf) {
+		t.Errorf("GOT: %v; WANT: %v", buf, mapBuf)
+	}
+	native, _, err = codec.NativeFromBinary(buf)
+	ensureError(t, err)
+	if actual, expected := native, (Record{
+		{Name: "a", Value: int32(3)},
+		{Name: "b", Value: "some string"},
+		{Name: "c", Value: []interface{}{Record{{Name: "x", Value: int32(1)}, {Name: "y", Value: int32(2)}}}},
+	}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+	}
+	if value, ok := native.(Record).Get("b"); !ok || value != "some string" {
+		t.Errorf("GOT: %v, %v; WANT: %v, %v", value, ok, "some string", true)
+	}
+
+	// With adds the option to a Codec created without it, which decodes maps
+	ordered, err := plain.With(WithOrderedRecords())
+	ensureError(t, err)
+	native, _, err = ordered.NativeFromBinary(buf)
+	ensureError(t, err)
+	if _, ok := native.(Record); !ok {
+		t.Errorf("GOT: %T; WANT: Record", native)
+	}
+	native, _, err = plain.NativeFromBinary(buf)
+	ensureError(t, err)
+	if _, ok := native.(map[string]interface{}); !ok {
+		t.Errorf("GOT: %T; WANT: map[string]interface{}", native)
+	}
 }
@@ -0,0 +1,485 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// NewCodecForReaderWriter returns a Codec for the reader schema, whose
+// NativeFromBinary method decodes binary data encoded using the writer schema,
+// applying the Avro schema resolution rules. Reader named types and record
+// fields match those of the writer by name or by alias. Writer record fields
+// missing from the reader schema are skipped, reader record fields missing from
+// the writer schema are set to their default values, writer enum symbols
+// missing from the reader schema are replaced by the default symbol of the
+// reader enum, and numeric values are promoted as allowed by the Avro
+// specification. The remaining methods of the returned Codec use the reader
+// schema.
+//
+// An error is returned when the writer schema cannot be resolved with the
+// reader schema, for instance when a reader record field without a default
+// value is missing from the writer schema. However, an error involving a union
+// member or enum symbol of the writer schema is only returned when data using
+// that member or symbol is decoded.
+//
+//     reader, err := goavro.NewCodecForReaderWriter(
+//         `{"type":"record","name":"r","fields":[{"name":"a","type":"long"},{"name":"b","type":"string","default":"none"}]}`,
+//         `{"type":"record","name":"r","fields":[{"name":"c","type":"boolean"},{"name":"a","type":"int"}]}`)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     datum, _, err := reader.NativeFromBinary([]byte{0x01, 0x06})
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Println(datum)
+//     // Output: map[a:3 b:none]
+func NewCodecForReaderWriter(readerSchema, writerSchema string) (*Codec, error) {
+	return newCodecForReaderWriter(readerSchema, writerSchema, codecOptions{})
+}
+
+func newCodecForReaderWriter(readerSchema, writerSchema string, o codecOptions) (*Codec, error) {
+	reader, err := newCodecWithOptions(readerSchema, o)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create reader codec: %w", err)
+	}
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create writer codec: %w", err)
+	}
+	r := &resolver{
+		primitives: newSymbolTable(),
+		records:    make(map[[2]*Codec]*resolvedDecoder),
+	}
+	resolvedFromBinary, err := r.resolve(reader, writer)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve writer schema with reader schema: %w", err)
+	}
+	// NOTE: Copy the reader codec rather than modify it, because a named
+	// reader codec is also referenced by its own symbol table.
+	c := *reader
+	c.resolvedFromBinary = resolvedFromBinary
+	c.nativeFromBinary = func(buf []byte) (interface{}, []byte, error) {
+		return resolvedFromBinary(&contextDecoder{ctx: context.Background()}, buf)
+	}
+	c.recordFromBinary = nil
+	// NOTE: Records of the writer schema missing from the reader schema are
+	// also decoded, so either schema being recursive bounds the depth.
+	c.recursive = reader.recursive || writer.recursive
+	c.writerSchema = writerSchema
+	return &c, nil
+}
+
+// resolvedDecoder decodes binary data encoded using a writer schema, and
+// returns it in the native form of the reader schema, tracking the items and
+// depth of the decoded value using cd.
+type resolvedDecoder func(cd *contextDecoder, buf []byte) (interface{}, []byte, error)
+
+// resolver builds binary decoders that read data encoded using a writer schema,
+// and return it in the native form of the reader schema.
+type resolver struct {
+	// primitives holds the primitive codecs, used to decode a writer value
+	// without its logical type before promoting it to the reader type.
+	primitives map[string]*Codec
+
+	// records holds the decoders of record pairs being resolved, so recursive
+	// schemas refer to the decoder rather than resolving without end.
+	records map[[2]*Codec]*resolvedDecoder
+}
+
+// baseType returns the Avro type of a codec, which for a logical type is the
+// type it annotates.
+func (c *Codec) baseType() string {
+	if c.schemaType != "" {
+		return c.schemaType
+	}
+	if index := strings.IndexByte(c.typeName.fullName, '.'); index > -1 {
+		return c.typeName.fullName[:index]
+	}
+	return c.typeName.fullName
+}
+
+// matches returns true when the reader and writer codecs are the same Avro
+// type, and named types have the same unqualified name, or the unqualified name
+// of the writer is that of one of the aliases of the reader.
+func matches(reader, writer *Codec) bool {
+	readerType := reader.baseType()
+	if readerType != writer.baseType() {
+		return false
+	}
+	switch readerType {
+	case "enum", "fixed", "record":
+		writerName := writer.typeName.short()
+		if reader.typeName.short() == writerName {
+			return true
+		}
+		for _, alias := range reader.aliases {
+			if (&name{fullName: alias}).short() == writerName {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// matchFields returns the reader record field matching each writer record
+// field, or nil for writer fields missing from the reader, along with the set of
+// matched reader fields. Writer fields are matched to reader fields by name
+// first, and only then by the aliases of the reader fields.
+func matchFields(reader, writer *Codec) ([]*recordField, map[*recordField]struct{}) {
+	readerFields := make([]*recordField, len(writer.recordFields))
+	matchedFields := make(map[*recordField]struct{}, len(reader.recordFields))
+	for i, writerField := range writer.recordFields {
+		for _, readerField := range reader.recordFields {
+			if readerField.name == writerField.name {
+				readerFields[i] = readerField
+				matchedFields[readerField] = struct{}{}
+				break
+			}
+		}
+	}
+	for i, writerField := range writer.recordFields {
+		if readerFields[i] != nil {
+			continue
+		}
+	aliases:
+		for _, readerField := range reader.recordFields {
+			if _, ok := matchedFields[readerField]; ok {
+				continue
+			}
+			for _, alias := range readerField.aliases {
+				if alias == writerField.name {
+					readerFields[i] = readerField
+					matchedFields[readerField] = struct{}{}
+					break aliases
+				}
+			}
+		}
+	}
+	return readerFields, matchedFields
+}
+
+func (r *resolver) resolve(reader, writer *Codec) (resolvedDecoder, error) {
+	if writer.unionInfo != nil {
+		return r.resolveWriterUnion(reader, writer)
+	}
+	if reader.unionInfo != nil {
+		return r.resolveReaderUnion(reader, writer)
+	}
+
+	readerType, writerType := reader.baseType(), writer.baseType()
+	if !matches(reader, writer) {
+		if promote := promotion(readerType, writerType); promote != nil {
+			return r.resolvePromotion(reader, writer, promote), nil
+		}
+		if readerType == writerType {
+			return nil, fmt.Errorf("writer %s %q does not match reader %s %q", writerType, writer.typeName, readerType, reader.typeName)
+		}
+		return nil, fmt.Errorf("writer type %q cannot be resolved with reader type %q", writerType, readerType)
+	}
+
+	switch readerType {
+	case "array":
+		itemFromBinary, err := r.resolve(reader.itemCodec, writer.itemCodec)
+		if err != nil {
+			return nil, fmt.Errorf("array items: %w", err)
+		}
+		return func(cd *contextDecoder, buf []byte) (interface{}, []byte, error) {
+			if err := cd.enter(); err != nil {
+				return nil, nil, err
+			}
+			defer cd.leave()
+			return genericArrayBinaryDecoder(buf, cd.itemDecoder(itemFromBinary), cd.itemLimits(reader))
+		}, nil
+	case "map":
+		valueFromBinary, err := r.resolve(reader.itemCodec, writer.itemCodec)
+		if err != nil {
+			return nil, fmt.Errorf("map values: %w", err)
+		}
+		return func(cd *contextDecoder, buf []byte) (interface{}, []byte, error) {
+			if err := cd.enter(); err != nil {
+				return nil, nil, err
+			}
+			defer cd.leave()
+			return genericMapBinaryDecoder(buf, cd.itemDecoder(valueFromBinary), cd.itemLimits(reader))
+		}, nil
+	case "enum":
+		return r.resolveEnum(reader, writer), nil
+	case "fixed":
+		if reader.fixedSize != writer.fixedSize {
+			return nil, fmt.Errorf("writer fixed %q size does not match reader size: %d != %d", writer.typeName, writer.fixedSize, reader.fixedSize)
+		}
+		return flatDecoder(reader), nil
+	case "record":
+		return r.resolveRecord(reader, writer)
+	default:
+		// NOTE: Primitive types encode the same way regardless of their
+		// logical types, so the reader decodes the data as its own.
+		return flatDecoder(reader), nil
+	}
+}
+
+// flatDecoder returns a resolvedDecoder which decodes the data using the
+// decoder of the codec, whose values hold no other values to track.
+func flatDecoder(c *Codec) resolvedDecoder {
+	return func(_ *contextDecoder, buf []byte) (interface{}, []byte, error) {
+		return c.nativeFromBinary(buf)
+	}
+}
+
+// promotion returns the function that converts a decoded value of the writer
+// type to the reader type, or nil when the specification does not allow the
+// writer type to be promoted to the reader type.
+func promotion(readerType, writerType string) func(interface{}) interface{} {
+	switch writerType + ":" + readerType {
+	case "int:long":
+		return func(v interface{}) interface{} { return int64(v.(int32)) }
+	case "int:float":
+		return func(v interface{}) interface{} { return float32(v.(int32)) }
+	case "int:double":
+		return func(v interface{}) interface{} { return float64(v.(int32)) }
+	case "long:float":
+		return func(v interface{}) interface{} { return float32(v.(int64)) }
+	case "long:double":
+		return func(v interface{}) interface{} { return float64(v.(int64)) }
+	case "float:double":
+		return func(v interface{}) interface{} { return float64(v.(float32)) }
+	case "string:bytes":
+		return func(v interface{}) interface{} { return []byte(v.(string)) }
+	case "bytes:string":
+		return func(v interface{}) interface{} { return string(v.([]byte)) }
+	}
+	return nil
+}
+
+func (r *resolver) resolvePromotion(reader, writer *Codec, promote func(interface{}) interface{}) resolvedDecoder {
+	writerNativeFromBinary := r.primitives[writer.baseType()].nativeFromBinary
+	logical := reader.typeName.fullName != reader.baseType() || reader.bytesAsString
+
+	return func(_ *contextDecoder, buf []byte) (interface{}, []byte, error) {
+		value, buf, err := writerNativeFromBinary(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		value = promote(value)
+		if logical {
+			// NOTE: Round trip the promoted value through the reader codec so
+			// it is returned in the native form of the reader logical type,
+			// or as a string by a bytes codec created using WithBytesAsString.
+			b, err := reader.binaryFromNative(nil, value)
+			if err != nil {
+				return nil, nil, err
+			}
+			if value, _, err = reader.nativeFromBinary(b); err != nil {
+				return nil, nil, err
+			}
+		}
+		return value, buf, nil
+	}
+}
+
+func (r *resolver) resolveEnum(reader, writer *Codec) resolvedDecoder {
+	readerSymbols := make(map[string]int, len(reader.enumSymbols))
+	for i, symbol := range reader.enumSymbols {
+		readerSymbols[symbol] = i
+	}
+	writerSymbols := writer.enumSymbols
+
+	return func(_ *contextDecoder, buf []byte) (interface{}, []byte, error) {
+		value, buf, err := longNativeFromBinary(buf)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot decode binary enum %q index: %w", writer.typeName, err)
+		}
+		index := value.(int64)
+		if index < 0 || index >= int64(len(writerSymbols)) {
+			return nil, nil, fmt.Errorf("cannot decode binary enum %q: index ought to be between 0 and %d; read index: %d", writer.typeName, len(writerSymbols)-1, index)
+		}
+		symbol := writerSymbols[index]
+		readerIndex, ok := readerSymbols[symbol]
+		if !ok {
+			if reader.enumDefault == "" {
+				return nil, nil, fmt.Errorf("cannot decode binary enum %q: writer symbol ought to be member of reader symbols: %v; %q", reader.typeName, reader.enumSymbols, symbol)
+			}
+			readerIndex = readerSymbols[reader.enumDefault]
+		}
+		return reader.enumNative(readerIndex), buf, nil
+	}
+}
+
+func (r *resolver) resolveRecord(reader, writer *Codec) (resolvedDecoder, error) {
+	key := [2]*Codec{reader, writer}
+	if recordFromBinary, ok := r.records[key]; ok {
+		return func(cd *contextDecoder, buf []byte) (interface{}, []byte, error) {
+			return (*recordFromBinary)(cd, buf)
+		}, nil
+	}
+	// NOTE: Register the decoder before resolving the fields, and fill it in
+	// afterwards, to support recursive data types.
+	var recordFromBinary resolvedDecoder
+	r.records[key] = &recordFromBinary
+
+	readerFields, matchedFields := matchFields(reader, writer)
+
+	// Writer fields are decoded in the order they were written. Those missing
+	// from the reader are decoded and discarded.
+	fieldNames := make([]string, len(writer.recordFields))
+	fieldDecoders := make([]resolvedDecoder, len(writer.recordFields))
+	for i, writerField := range writer.recordFields {
+		readerField := readerFields[i]
+		if readerField == nil {
+			writerCodec := writerField.codec
+			fieldDecoders[i] = func(cd *contextDecoder, buf []byte) (interface{}, []byte, error) {
+				return cd.nativeFromBinary(writerCodec, buf)
+			}
+			continue
+		}
+		fieldDecoder, err := r.resolve(readerField.codec, writerField.codec)
+		if err != nil {
+			return nil, fmt.Errorf("record %q field %q: %w", reader.typeName, readerField.name, err)
+		}
+		fieldNames[i] = readerField.name
+		fieldDecoders[i] = fieldDecoder
+	}
+
+	// Reader fields missing from the writer are set to their default values,
+	// which are kept in binary form, so each decoded record has its own copy.
+	var defaultFields []*recordField
+	var defaultValues [][]byte
+	for _, readerField := range reader.recordFields {
+		if _, ok := matchedFields[readerField]; ok {
+			continue
+		}
+		if !readerField.hasDefault {
+			return nil, fmt.Errorf("record %q field %q: reader field is missing from writer and has no default value", reader.typeName, readerField.name)
+		}
+		b, err := readerField.codec.binaryFromNative(nil, readerField.defaultValue)
+		if err != nil {
+			return nil, fmt.Errorf("record %q field %q: cannot encode default value: %w", reader.typeName, readerField.name, err)
+		}
+		defaultFields = append(defaultFields, readerField)
+		defaultValues = append(defaultValues, b)
+	}
+
+	recordFromBinary = func(cd *contextDecoder, buf []byte) (interface{}, []byte, error) {
+		if err := cd.enter(); err != nil {
+			return nil, nil, err
+		}
+		defer cd.leave()
+		recordMap := make(map[string]interface{}, len(reader.recordFields))
+		remaining := buf
+		for i, fieldDecoder := range fieldDecoders {
+			if err := cd.err(); err != nil {
+				return nil, nil, err
+			}
+			value, newBuf, err := fieldDecoder(cd, remaining)
+			if err != nil {
+				name := writer.recordFields[i].name
+				return nil, nil, newDecodeError(err, fmt.Errorf("cannot decode binary record %q field %q: %w", writer.typeName, name, err), "."+name, len(buf)-len(remaining))
+			}
+			if fieldNames[i] != "" {
+				recordMap[fieldNames[i]] = value
+			}
+			remaining = newBuf
+		}
+		for i, field := range defaultFields {
+			value, _, err := field.codec.nativeFromBinary(defaultValues[i])
+			if err != nil {
+				return nil, nil, fmt.Errorf("cannot decode binary record %q field %q default value: %w", reader.typeName, field.name, err)
+			}
+			recordMap[field.name] = value
+		}
+		return reader.recordNative(recordMap), remaining, nil
+	}
+	return recordFromBinary, nil
+}
+
+// resolveWriterUnion resolves each member of the writer union with the reader
+// schema. Members which cannot be resolved only cause an error when data
+// encoded using them is decoded.
+func (r *resolver) resolveWriterUnion(reader, writer *Codec) (resolvedDecoder, error) {
+	cr := writer.unionInfo
+	memberDecoders := make([]resolvedDecoder, len(cr.codecFromIndex))
+	memberErrors := make([]error, len(cr.codecFromIndex))
+	for i, memberCodec := range cr.codecFromIndex {
+		memberDecoders[i], memberErrors[i] = r.resolve(reader, memberCodec)
+		if memberErrors[i] != nil && reader.unionInfo != nil && len(reader.unionInfo.codecFromIndex) < len(cr.codecFromIndex) {
+			// NOTE: Most likely the member was added to the union after the
+			// reader schema was written, rather than the data being corrupt.
+			memberErrors[i] = fmt.Errorf("reader union has fewer members than writer union: %d < %d; %s", len(reader.unionInfo.codecFromIndex), len(cr.codecFromIndex), memberErrors[i])
+		}
+	}
+
+	return func(cd *contextDecoder, buf []byte) (interface{}, []byte, error) {
+		if err := cd.enter(); err != nil {
+			return nil, nil, err
+		}
+		defer cd.leave()
+		value, remaining, err := longNativeFromBinary(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		index := value.(int64) // longDecoder always returns int64, so elide error checking
+		if index < 0 || index >= int64(len(memberDecoders)) {
+			return nil, nil, fmt.Errorf("cannot decode binary union: index ought to be between 0 and %d; read index: %d", len(memberDecoders)-1, index)
+		}
+		if err = memberErrors[index]; err != nil {
+			return nil, nil, fmt.Errorf("cannot decode binary union item %d: %w", index+1, err)
+		}
+		value, newBuf, err := memberDecoders[index](cd, remaining)
+		if err != nil {
+			return nil, nil, newDecodeError(err, fmt.Errorf("cannot decode binary union item %d: %w", index+1, err), "", len(buf)-len(remaining))
+		}
+		return value, newBuf, nil
+	}, nil
+}
+
+// resolveReaderUnion resolves a writer schema which is not a union with the
+// first member of the reader union having the same type, or failing that, with
+// the first member the writer schema can be promoted to.
+func (r *resolver) resolveReaderUnion(reader, writer *Codec) (resolvedDecoder, error) {
+	cr := reader.unionInfo
+	index := readerUnionIndex(cr, writer)
+	if index == -1 {
+		return nil, fmt.Errorf("writer type %q does not match any reader union member: %v", writer.typeName, cr.allowedTypes)
+	}
+	memberFromBinary, err := r.resolve(cr.codecFromIndex[index], writer)
+	if err != nil {
+		return nil, fmt.Errorf("reader union item %d: %w", index+1, err)
+	}
+
+	return func(cd *contextDecoder, buf []byte) (interface{}, []byte, error) {
+		value, buf, err := memberFromBinary(cd, buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		return unionNativeFromMember(cr, index, value), buf, nil
+	}, nil
+}
+
+// readerUnionIndex returns the index of the first member of the reader union
+// having the same type as the writer schema, or failing that, of the first
+// member the writer schema can be promoted to, or -1 when there is none.
+func readerUnionIndex(cr *codecInfo, writer *Codec) int {
+	for i, memberCodec := range cr.codecFromIndex {
+		if memberCodec.unionInfo == nil && matches(memberCodec, writer) {
+			return i
+		}
+	}
+	for i, memberCodec := range cr.codecFromIndex {
+		if promotion(memberCodec.baseType(), writer.baseType()) != nil {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,398 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testResolutionPass encodes datum using the writer schema, then ensures the
+// codec for the reader and writer schemas decodes it as expected.
+func testResolutionPass(t *testing.T, readerSchema, writerSchema string, datum, expected interface{}) {
+	t.Helper()
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := writer.BinaryFromNative(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := NewCodecForReaderWriter(readerSchema, writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, remaining, err := reader.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatalf("reader: %s; writer: %s; %s", readerSchema, writerSchema, err)
+	}
+	if actual, expected := len(remaining), 0; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := testResolutionFormat(value), testResolutionFormat(expected); actual != expected {
+		t.Errorf("reader: %s; writer: %s; GOT: %v; WANT: %v", readerSchema, writerSchema, actual, expected)
+	}
+}
+
+// testResolutionFormat formats a native value along with its type, and the value
+// a pointer refers to rather than its address.
+func testResolutionFormat(value interface{}) string {
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		value = rv.Elem().Interface()
+		return fmt.Sprintf("&%T(%v)", value, value)
+	}
+	return fmt.Sprintf("%T(%v)", value, value)
+}
+
+// testResolutionDecodeFail encodes datum using the writer schema, then ensures
+// the codec for the reader and writer schemas fails to decode it.
+func testResolutionDecodeFail(t *testing.T, readerSchema, writerSchema string, datum interface{}, errorMessage string) {
+	t.Helper()
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := writer.BinaryFromNative(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := NewCodecForReaderWriter(readerSchema, writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = reader.NativeFromBinary(buf)
+	ensureError(t, err, errorMessage)
+}
+
+func testResolutionSchemaFail(t *testing.T, readerSchema, writerSchema string, errorMessage string) {
+	t.Helper()
+	_, err := NewCodecForReaderWriter(readerSchema, writerSchema)
+	ensureError(t, err, errorMessage)
+}
+
+func ExampleNewCodecForReaderWriter() {
+	reader, err := NewCodecForReaderWriter(
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"long"},{"name":"b","type":"string","default":"none"}]}`,
+		`{"type":"record","name":"r","fields":[{"name":"c","type":"boolean"},{"name":"a","type":"int"}]}`)
+	if err != nil {
+		fmt.Println(err)
+	}
+	datum, _, err := reader.NativeFromBinary([]byte{0x01, 0x06})
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(datum)
+	// Output: map[a:3 b:none]
+}
+
+func TestResolutionInvalidSchema(t *testing.T) {
+	testResolutionSchemaFail(t, `"nope"`, `"int"`, "cannot create reader codec")
+	testResolutionSchemaFail(t, `"int"`, `"nope"`, "cannot create writer codec")
+}
+
+func TestResolutionPrimitivePromotion(t *testing.T) {
+	testResolutionPass(t, `"int"`, `"int"`, int32(13), int32(13))
+	testResolutionPass(t, `"long"`, `"int"`, int32(13), int64(13))
+	testResolutionPass(t, `"float"`, `"int"`, int32(13), float32(13))
+	testResolutionPass(t, `"double"`, `"int"`, int32(13), float64(13))
+	testResolutionPass(t, `"float"`, `"long"`, int64(-13), float32(-13))
+	testResolutionPass(t, `"double"`, `"long"`, int64(-13), float64(-13))
+	testResolutionPass(t, `"double"`, `"float"`, float32(3.5), float64(3.5))
+	testResolutionPass(t, `"bytes"`, `"string"`, "abc", []byte("abc"))
+	testResolutionPass(t, `"string"`, `"bytes"`, []byte("abc"), "abc")
+}
+
+func TestResolutionPrimitiveMismatch(t *testing.T) {
+	testResolutionSchemaFail(t, `"int"`, `"long"`, `writer type "long" cannot be resolved with reader type "int"`)
+	testResolutionSchemaFail(t, `"float"`, `"double"`, `writer type "double" cannot be resolved with reader type "float"`)
+	testResolutionSchemaFail(t, `"boolean"`, `"string"`, `writer type "string" cannot be resolved with reader type "boolean"`)
+	testResolutionSchemaFail(t, `{"type":"array","items":"int"}`, `{"type":"map","values":"int"}`, `writer type "map" cannot be resolved with reader type "array"`)
+}
+
+func TestResolutionPromotionToLogicalType(t *testing.T) {
+	testResolutionPass(t, `{"type":"long","logicalType":"timestamp-millis"}`, `"int"`, int32(1000), time.Unix(1, 0).UTC())
+}
+
+func TestResolutionRecordFields(t *testing.T) {
+	writerSchema := `{"type":"record","name":"r","fields":[
+		{"name":"a","type":"int"},
+		{"name":"skipped","type":{"type":"array","items":"string"}},
+		{"name":"b","type":"string"}
+	]}`
+	readerSchema := `{"type":"record","name":"r","fields":[
+		{"name":"b","type":"string"},
+		{"name":"added","type":{"type":"map","values":"long"},"default":{"x":1}},
+		{"name":"a","type":"long"},
+		{"name":"optional","type":["null","string"],"default":null}
+	]}`
+	datum := map[string]interface{}{"a": 3, "skipped": []interface{}{"x", "y"}, "b": "hello"}
+	testResolutionPass(t, readerSchema, writerSchema, datum, map[string]interface{}{
+		"a":        int64(3),
+		"added":    map[string]interface{}{"x": int64(1)},
+		"b":        "hello",
+		"optional": nil,
+	})
+}
+
+func TestResolutionRecordDefaultNotShared(t *testing.T) {
+	reader, err := NewCodecForReaderWriter(
+		`{"type":"record","name":"r","fields":[{"name":"a","type":{"type":"array","items":"int"},"default":[1]}]}`,
+		`{"type":"record","name":"r","fields":[]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _, err := reader.NativeFromBinary(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.(map[string]interface{})["a"].([]interface{})[0] = int32(42)
+	second, _, err := reader.NativeFromBinary(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := fmt.Sprintf("%v", second), "map[a:[1]]"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
+
+func TestResolutionRecordMissingDefault(t *testing.T) {
+	testResolutionSchemaFail(t,
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"int"},{"name":"b","type":"int"}]}`,
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"int"}]}`,
+		`record "r" field "b": reader field is missing from writer and has no default value`)
+}
+
+func TestResolutionRecordName(t *testing.T) {
+	// unqualified names match, regardless of namespace
+	testResolutionPass(t,
+		`{"type":"record","name":"r","namespace":"com.reader","fields":[{"name":"a","type":"int"}]}`,
+		`{"type":"record","name":"r","namespace":"com.writer","fields":[{"name":"a","type":"int"}]}`,
+		map[string]interface{}{"a": 1}, map[string]interface{}{"a": int32(1)})
+	testResolutionSchemaFail(t,
+		`{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]}`,
+		`{"type":"record","name":"r2","fields":[{"name":"a","type":"int"}]}`,
+		`writer record "r2" does not match reader record "r1"`)
+}
+
+func TestResolutionAliases(t *testing.T) {
+	t.Run("field", func(t *testing.T) {
+		testResolutionPass(t,
+			`{"type":"record","name":"r","fields":[{"name":"newName","type":"int","aliases":["oldName"]}]}`,
+			`{"type":"record","name":"r","fields":[{"name":"oldName","type":"int"}]}`,
+			map[string]interface{}{"oldName": 3}, map[string]interface{}{"newName": int32(3)})
+		// NOTE: A reader field is matched by its name before its aliases,
+		// whichever writer field comes first.
+		testResolutionPass(t,
+			`{"type":"record","name":"r","fields":[{"name":"newName","type":"int","aliases":["oldName"]}]}`,
+			`{"type":"record","name":"r","fields":[{"name":"oldName","type":"int"},{"name":"newName","type":"int"}]}`,
+			map[string]interface{}{"oldName": 3, "newName": 4}, map[string]interface{}{"newName": int32(4)})
+		// without the alias, the reader field has no value
+		testResolutionSchemaFail(t,
+			`{"type":"record","name":"r","fields":[{"name":"newName","type":"int"}]}`,
+			`{"type":"record","name":"r","fields":[{"name":"oldName","type":"int"}]}`,
+			`record "r" field "newName": reader field is missing from writer and has no default value`)
+	})
+
+	t.Run("record", func(t *testing.T) {
+		testResolutionPass(t,
+			`{"type":"record","name":"r2","namespace":"com.example","aliases":["r1"],"fields":[{"name":"a","type":"int"}]}`,
+			`{"type":"record","name":"r1","namespace":"com.example","fields":[{"name":"a","type":"int"}]}`,
+			map[string]interface{}{"a": 3}, map[string]interface{}{"a": int32(3)})
+		testResolutionPass(t,
+			`{"type":"record","name":"r2","aliases":["com.other.r1"],"fields":[{"name":"a","type":"int"}]}`,
+			`{"type":"record","name":"r1","namespace":"com.other","fields":[{"name":"a","type":"int"}]}`,
+			map[string]interface{}{"a": 3}, map[string]interface{}{"a": int32(3)})
+	})
+
+	t.Run("enum", func(t *testing.T) {
+		testResolutionPass(t,
+			`{"type":"enum","name":"e2","aliases":["e1"],"symbols":["A","B"]}`,
+			`{"type":"enum","name":"e1","symbols":["A","B"]}`,
+			"B", "B")
+	})
+
+	t.Run("fixed", func(t *testing.T) {
+		testResolutionPass(t,
+			`{"type":"fixed","name":"f2","aliases":["f1"],"size":2}`,
+			`{"type":"fixed","name":"f1","size":2}`,
+			[]byte("ab"), []byte("ab"))
+		testResolutionSchemaFail(t,
+			`{"type":"fixed","name":"f2","aliases":["f3"],"size":2}`,
+			`{"type":"fixed","name":"f1","size":2}`,
+			`writer fixed "f1" does not match reader fixed "f2"`)
+	})
+
+	t.Run("union member", func(t *testing.T) {
+		value := map[string]interface{}{"a": int32(3)}
+		testResolutionPass(t,
+			`["null",{"type":"record","name":"r2","aliases":["r1"],"fields":[{"name":"a","type":"int"}]}]`,
+			`["null",{"type":"record","name":"r1","fields":[{"name":"a","type":"int"}]}]`,
+			Union("r1", map[string]interface{}{"a": 3}), &value)
+	})
+}
+
+func TestResolutionRecordFieldMismatch(t *testing.T) {
+	testResolutionSchemaFail(t,
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"int"}]}`,
+		`{"type":"record","name":"r","fields":[{"name":"a","type":"string"}]}`,
+		`record "r" field "a": writer type "string" cannot be resolved with reader type "int"`)
+}
+
+func TestResolutionRecursiveRecord(t *testing.T) {
+	writerSchema := `{"type":"record","name":"LongList","fields":[
+		{"name":"value","type":"int"},
+		{"name":"next","type":["null","LongList"],"default":null}
+	]}`
+	readerSchema := `{"type":"record","name":"LongList","fields":[
+		{"name":"value","type":"long"},
+		{"name":"next","type":["null","LongList"],"default":null}
+	]}`
+	datum := map[string]interface{}{
+		"value": 1,
+		"next": Union("LongList", map[string]interface{}{
+			"value": 2,
+			"next":  nil,
+		}),
+	}
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := writer.BinaryFromNative(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := NewCodecForReaderWriter(readerSchema, writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, _, err := reader.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := value.(map[string]interface{})
+	if actual, expected := record["value"], int64(1); actual != expected {
+		t.Errorf("GOT: %T(%v); WANT: %T(%v)", actual, actual, expected, expected)
+	}
+	next := *(record["next"].(*map[string]interface{}))
+	if actual, expected := next["value"], int64(2); actual != expected {
+		t.Errorf("GOT: %T(%v); WANT: %T(%v)", actual, actual, expected, expected)
+	}
+	if actual := next["next"]; actual != nil {
+		t.Errorf("GOT: %v; WANT: %v", actual, nil)
+	}
+}
+
+func TestResolutionArrayAndMap(t *testing.T) {
+	testResolutionPass(t, `{"type":"array","items":"long"}`, `{"type":"array","items":"int"}`,
+		[]interface{}{1, 2, 3}, []interface{}{int64(1), int64(2), int64(3)})
+	testResolutionPass(t, `{"type":"map","values":"double"}`, `{"type":"map","values":"float"}`,
+		map[string]interface{}{"k": float32(1.5)}, map[string]interface{}{"k": float64(1.5)})
+}
+
+func TestResolutionEnum(t *testing.T) {
+	readerSchema := `{"type":"enum","name":"e","symbols":["B","A"]}`
+	writerSchema := `{"type":"enum","name":"e","symbols":["A","B","C"]}`
+	testResolutionPass(t, readerSchema, writerSchema, "A", "A")
+	testResolutionPass(t, readerSchema, writerSchema, "B", "B")
+	testResolutionDecodeFail(t, readerSchema, writerSchema, "C", `writer symbol ought to be member of reader symbols: [B A]; "C"`)
+}
+
+func TestResolutionEnumDefault(t *testing.T) {
+	writerSchema := `{"type":"enum","name":"e","symbols":["A","B","C"]}`
+
+	// writer index 2 is beyond the symbols of the reader
+	testResolutionPass(t, `{"type":"enum","name":"e","symbols":["B","A"],"default":"A"}`, writerSchema, "C", "A")
+	testResolutionPass(t, `{"type":"enum","name":"e","symbols":["B","A"],"default":"A"}`, writerSchema, "B", "B")
+	testResolutionDecodeFail(t, `{"type":"enum","name":"e","symbols":["B","A"]}`, writerSchema, "C", `writer symbol ought to be member of reader symbols: [B A]; "C"`)
+
+	// the default does not apply to indexes beyond the symbols of the writer
+	codec, err := NewCodecForReaderWriter(`{"type":"enum","name":"e","symbols":["B","A"],"default":"A"}`, writerSchema)
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinary([]byte{0x06})
+	ensureError(t, err, "index ought to be between 0 and 2; read index: 3")
+}
+
+func TestResolutionFixed(t *testing.T) {
+	testResolutionPass(t, `{"type":"fixed","name":"f","size":2}`, `{"type":"fixed","name":"f","size":2}`, []byte("ab"), []byte("ab"))
+	testResolutionSchemaFail(t, `{"type":"fixed","name":"f","size":2}`, `{"type":"fixed","name":"f","size":3}`, "size does not match reader size: 3 != 2")
+}
+
+func TestResolutionWriterUnion(t *testing.T) {
+	readerSchema := `"long"`
+	writerSchema := `["null","int","string"]`
+	testResolutionPass(t, readerSchema, writerSchema, Union("int", 3), int64(3))
+	// unresolvable members fail only when decoded
+	testResolutionDecodeFail(t, readerSchema, writerSchema, Union("string", "x"), `cannot decode binary union item 3: writer type "string" cannot be resolved with reader type "long"`)
+	testResolutionDecodeFail(t, readerSchema, writerSchema, nil, `cannot decode binary union item 1: writer type "null" cannot be resolved with reader type "long"`)
+}
+
+func TestResolutionReaderUnion(t *testing.T) {
+	// nullable reader union returns a pointer to the value
+	value := int64(3)
+	testResolutionPass(t, `["null","long"]`, `"int"`, 3, &value)
+	// exact member match is preferred to promotion
+	testResolutionPass(t, `["double","null","int"]`, `"int"`, 3, Union("int", int32(3)))
+	testResolutionPass(t, `["double","null","string"]`, `"int"`, 3, Union("double", float64(3)))
+	testResolutionSchemaFail(t, `["null","string"]`, `"int"`, `writer type "int" does not match any reader union member: [null string]`)
+}
+
+func TestResolutionUnionToUnion(t *testing.T) {
+	readerSchema := `["null","string","long"]`
+	writerSchema := `["int","null"]`
+	testResolutionPass(t, readerSchema, writerSchema, Union("int", 7), Union("long", int64(7)))
+	testResolutionPass(t, readerSchema, writerSchema, nil, nil)
+}
+
+func TestResolutionUnionSizeMismatch(t *testing.T) {
+	readerSchema := `["null","int"]`
+	writerSchema := `["null","int","string"]`
+	value := int32(3)
+	testResolutionPass(t, readerSchema, writerSchema, Union("int", 3), &value)
+	// a member the reader does not know of is reported as a schema evolution
+	// problem rather than as corrupt data
+	testResolutionDecodeFail(t, readerSchema, writerSchema, Union("string", "x"), `cannot decode binary union item 3: reader union has fewer members than writer union: 2 < 3; writer type "string" does not match any reader union member: [null int]`)
+	// an index beyond the writer union is still corrupt data
+	reader, err := NewCodecForReaderWriter(readerSchema, writerSchema)
+	ensureError(t, err)
+	_, _, err = reader.NativeFromBinary([]byte{0x06})
+	ensureError(t, err, "index ought to be between 0 and 2; read index: 3")
+}
+
+func TestResolutionMaxDecodeDepth(t *testing.T) {
+	// LongList values nested n deep, as in TestCodecMaxDecodeDepth
+	longList := func(n int) []byte {
+		return append(bytes.Repeat([]byte{2}, n), 0)
+	}
+
+	reader, err := NewCodecForReaderWriter(testSafeLinkedList, testSafeLinkedList)
+	ensureError(t, err)
+	limited, err := reader.With(WithMaxDecodeDepth(10))
+	ensureError(t, err)
+	_, _, err = limited.NativeFromBinary(longList(4))
+	ensureError(t, err)
+	_, _, err = limited.NativeFromBinary(longList(5))
+	ensureError(t, err, "nesting depth exceeds limit: 10")
+	_, _, err = limited.NativeFromBinaryContext(context.Background(), longList(5))
+	ensureError(t, err, "nesting depth exceeds limit: 10")
+	_, _, err = reader.NativeFromBinary(longList(10000000))
+	ensureError(t, err, fmt.Sprintf("nesting depth exceeds limit: %d", DefaultMaxDecodeDepth))
+
+	// writer fields missing from the reader are decoded within the limit
+	reader, err = NewCodecForReaderWriter(`{"type":"record","name":"LongList","fields":[]}`, testSafeLinkedList)
+	ensureError(t, err)
+	_, _, err = reader.NativeFromBinary(longList(10000000))
+	ensureError(t, err, fmt.Sprintf("nesting depth exceeds limit: %d", DefaultMaxDecodeDepth))
+}
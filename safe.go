@@ -0,0 +1,77 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultMaxItemCount is the number of array items and map values which may be
+// decoded in total by NativeFromBinarySafe, unless its DecodeLimits set
+// MaxItemCount.
+const DefaultMaxItemCount = 1 << 20
+
+// DecodeLimits bounds the resources used by NativeFromBinarySafe to decode a
+// single binary value.
+type DecodeLimits struct {
+	// MaxBlockCount and MaxBlockSize limit the number of items and bytes of a
+	// single block of an array or map. Zero uses the package variables of the
+	// same names.
+	MaxBlockCount int64
+	MaxBlockSize  int64
+
+	// MaxItemCount limits the number of array items and map values decoded in
+	// total, across all arrays and maps of the value, including items encoded
+	// using no bytes, such as nulls. Zero uses DefaultMaxItemCount.
+	MaxItemCount int64
+
+	// MaxDepth limits the number of unions, arrays, maps, and records nested
+	// within one another, which bounds the decoding of recursive schemas. Zero
+	// uses the limit of the Codec, as set by WithMaxDecodeDepth.
+	MaxDepth int
+}
+
+// NativeFromBinarySafe decodes a binary value like NativeFromBinary does, but
+// is meant for untrusted input. The value is decoded within limits, which
+// bound the items and bytes of each array and map block, the total number of
+// array items and map values, and the nesting depth of the value. Memory for
+// arrays and maps is allocated only as their items are read. Rather than
+// panicking on malformed input, it returns an error.
+//
+//     native, _, err := codec.NativeFromBinarySafe(buf, goavro.DecodeLimits{MaxDepth: 32})
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+func (c *Codec) NativeFromBinarySafe(buf []byte, limits DecodeLimits) (value interface{}, newBuf []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, newBuf, err = nil, buf, fmt.Errorf("cannot decode binary: %v", r)
+		}
+	}()
+
+	cd := &contextDecoder{
+		ctx:      context.Background(),
+		limits:   &decodeLimits{maxBlockCount: limits.MaxBlockCount, maxBlockSize: limits.MaxBlockSize},
+		maxItems: limits.MaxItemCount,
+		maxDepth: limits.MaxDepth,
+	}
+	if cd.maxItems <= 0 {
+		cd.maxItems = DefaultMaxItemCount
+	}
+	if cd.maxDepth <= 0 {
+		cd.maxDepth = c.maxDecodeDepth()
+	}
+	value, newBuf, err = cd.nativeFromBinary(c, buf)
+	if err != nil {
+		return nil, buf, c.located(err, 0) // if error, return original byte slice
+	}
+	return value, newBuf, nil
+}
@@ -0,0 +1,143 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const testSafeLinkedList = `{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"]}]}`
+
+func TestNativeFromBinarySafe(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":{"type":"array","items":{"type":"map","values":["null","long","string"]}}}]}`)
+	ensureError(t, err)
+	datum := map[string]interface{}{"a": []interface{}{
+		map[string]interface{}{"x": Union("long", int64(1)), "y": nil},
+	}}
+	buf, err := codec.BinaryFromNative(nil, datum)
+	ensureError(t, err)
+	buf = append(buf, 0xff)
+
+	native, remaining, err := codec.NativeFromBinarySafe(buf, DecodeLimits{})
+	ensureError(t, err)
+	if !reflect.DeepEqual(native, datum) {
+		t.Errorf("GOT: %#v; WANT: %#v", native, datum)
+	}
+	if actual, expected := remaining, []byte{0xff}; !bytes.Equal(actual, expected) {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
+
+func TestNativeFromBinarySafeDepth(t *testing.T) {
+	codec, err := NewCodec(testSafeLinkedList)
+	ensureError(t, err)
+	// each union index 1 is another LongList, and the final 0 is its null
+	buf := append(bytes.Repeat([]byte{2}, 10), 0)
+
+	_, _, err = codec.NativeFromBinarySafe(buf, DecodeLimits{})
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinarySafe(buf, DecodeLimits{MaxDepth: 20})
+	ensureError(t, err, "nesting depth exceeds limit: 20")
+
+	// a deeply nested value ought to return an error rather than exhaust the
+	// stack
+	_, remaining, err := codec.NativeFromBinarySafe(bytes.Repeat([]byte{2}, 1000000), DecodeLimits{})
+	ensureError(t, err, "nesting depth exceeds limit")
+	if actual, expected := len(remaining), 1000000; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	// the limits also apply to a codec resolving a writer schema
+	codec, err = NewCodecForReaderWriter(testSafeLinkedList, testSafeLinkedList)
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinarySafe(bytes.Repeat([]byte{2}, 20000000), DecodeLimits{MaxDepth: 10})
+	ensureError(t, err, "nesting depth exceeds limit: 10")
+	codec, err = NewCodecForReaderWriter(`{"type":"array","items":"long"}`, `{"type":"array","items":"int"}`)
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinarySafe([]byte{0x04, 0x06, 0x08, 0x00}, DecodeLimits{MaxItemCount: 1})
+	ensureError(t, err, "total item count exceeds limit: 1")
+}
+
+func TestNativeFromBinarySafeItemCount(t *testing.T) {
+	codec, err := NewCodec(`{"type":"array","items":"null"}`)
+	ensureError(t, err)
+	buf, err := codec.BinaryFromNative(nil, []interface{}{nil, nil, nil})
+	ensureError(t, err)
+	// a block count of 1,000,000,000 nulls is encoded in only 5 bytes
+	huge, err := longBinaryFromNative(nil, int64(1000000000))
+	ensureError(t, err)
+
+	// items encoded using no bytes are limited by count, not by the length of
+	// the buffer
+	native, _, err := codec.NativeFromBinarySafe(buf, DecodeLimits{})
+	ensureError(t, err)
+	if actual, expected := len(native.([]interface{})), 3; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	_, _, err = codec.NativeFromBinarySafe(buf, DecodeLimits{MaxItemCount: 2})
+	ensureError(t, err, "total item count exceeds limit: 2")
+	_, _, err = codec.NativeFromBinarySafe(huge, DecodeLimits{})
+	ensureError(t, err, fmt.Sprintf("total item count exceeds limit: %d", DefaultMaxItemCount))
+
+	// records without fields are also encoded using no bytes
+	codec, err = NewCodec(`{"type":"array","items":{"type":"record","name":"r1","fields":[]}}`)
+	ensureError(t, err)
+	buf, err = codec.BinaryFromNative(nil, []interface{}{map[string]interface{}{}, map[string]interface{}{}, map[string]interface{}{}})
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinarySafe(buf, DecodeLimits{})
+	ensureError(t, err)
+
+	codec, err = NewCodec(`{"type":"array","items":{"type":"array","items":"null"}}`)
+	ensureError(t, err)
+	buf, err = codec.BinaryFromNative(nil, []interface{}{
+		[]interface{}{nil, nil},
+		[]interface{}{nil, nil},
+	})
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinarySafe(buf, DecodeLimits{MaxItemCount: 5})
+	ensureError(t, err, "total item count exceeds limit: 5")
+	_, _, err = codec.NativeFromBinarySafe(buf, DecodeLimits{MaxItemCount: 6})
+	ensureError(t, err)
+}
+
+func TestNativeFromBinarySafeBlockLimits(t *testing.T) {
+	codec, err := NewCodec(`{"type":"map","values":"long"}`)
+	ensureError(t, err)
+	buf, err := codec.BinaryFromNative(nil, map[string]interface{}{"a": int64(1), "b": int64(2)})
+	ensureError(t, err)
+
+	_, _, err = codec.NativeFromBinarySafe(buf, DecodeLimits{MaxBlockCount: 1})
+	ensureError(t, err, "block count exceeds MaxBlockCount")
+
+	// a block count far exceeding the remaining bytes ought to return an error
+	// without allocating for that many values
+	huge, err := longBinaryFromNative(nil, MaxBlockCount)
+	ensureError(t, err)
+	_, _, err = codec.NativeFromBinarySafe(huge, DecodeLimits{})
+	ensureError(t, err, "cannot decode binary map")
+}
+
+func TestNativeFromBinarySafeTruncated(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"s","type":"string"},{"name":"f","type":{"type":"fixed","name":"f1","size":4}},{"name":"d","type":"double"}]}`)
+	ensureError(t, err)
+	buf, err := codec.BinaryFromNative(nil, map[string]interface{}{"s": "hello", "f": []byte("abcd"), "d": 3.5})
+	ensureError(t, err)
+
+	for i := 0; i < len(buf); i++ {
+		_, remaining, err := codec.NativeFromBinarySafe(buf[:i], DecodeLimits{})
+		ensureError(t, err, "cannot decode binary")
+		if actual, expected := len(remaining), i; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	}
+}
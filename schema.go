@@ -0,0 +1,342 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Schema is a node of the tree describing the Avro schema of a Codec, as
+// returned by its ParsedSchema method. Its concrete type is one of
+// *PrimitiveSchema, *RecordSchema, *EnumSchema, *FixedSchema, *ArraySchema,
+// *MapSchema, or *UnionSchema.
+//
+// Named types are represented by a single node, wherever they are referenced
+// in the schema, so the tree of a recursive schema contains cycles.
+//
+//     codec, err := goavro.NewCodec(`{"type":"record","name":"r1","namespace":"com.example","fields":[{"name":"f1","type":["null","string"],"default":null}]}`)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     record := codec.ParsedSchema().(*goavro.RecordSchema)
+//     for _, field := range record.Fields {
+//         fmt.Println(record.FullName, field.Name, field.Type.Type(), field.Default)
+//     }
+//     // Output: com.example.r1 f1 union <nil>
+type Schema interface {
+	// Type returns the Avro type of the schema, such as "record", "array", or
+	// "int".
+	Type() string
+}
+
+// PrimitiveSchema describes one of the Avro primitive types, along with the
+// logical type annotating it, if any.
+type PrimitiveSchema struct {
+	Name        string // null, boolean, int, long, float, double, bytes, or string
+	LogicalType string
+
+	// Properties holds the remaining schema attributes, such as the precision
+	// and scale of a decimal logical type.
+	Properties map[string]interface{}
+}
+
+// RecordSchema describes an Avro record.
+type RecordSchema struct {
+	FullName   string
+	Aliases    []string // fully qualified
+	Doc        string
+	Fields     []*Field
+	Properties map[string]interface{}
+}
+
+// Field describes a field of an Avro record.
+type Field struct {
+	Name       string
+	Aliases    []string
+	Doc        string
+	Type       Schema
+	Default    interface{} // as decoded from the schema JSON
+	HasDefault bool
+	Order      string // ascending, descending, or ignore; ascending when not specified
+	Properties map[string]interface{}
+}
+
+// EnumSchema describes an Avro enum.
+type EnumSchema struct {
+	FullName   string
+	Aliases    []string // fully qualified
+	Doc        string
+	Symbols    []string
+	Default    string // empty when the enum declares no default symbol
+	Properties map[string]interface{}
+}
+
+// FixedSchema describes an Avro fixed, along with the logical type annotating
+// it, if any.
+type FixedSchema struct {
+	FullName    string   // empty for an anonymous fixed of a logical type
+	Aliases     []string // fully qualified
+	Size        uint
+	LogicalType string
+	Properties  map[string]interface{}
+}
+
+// ArraySchema describes an Avro array.
+type ArraySchema struct {
+	Items      Schema
+	Properties map[string]interface{}
+}
+
+// MapSchema describes an Avro map.
+type MapSchema struct {
+	Values     Schema
+	Properties map[string]interface{}
+}
+
+// UnionSchema describes an Avro union.
+type UnionSchema struct {
+	Members []Schema
+}
+
+// Type returns the name of the primitive type.
+func (s *PrimitiveSchema) Type() string { return s.Name }
+
+// Type returns "record".
+func (s *RecordSchema) Type() string { return "record" }
+
+// Type returns "enum".
+func (s *EnumSchema) Type() string { return "enum" }
+
+// Type returns "fixed".
+func (s *FixedSchema) Type() string { return "fixed" }
+
+// Type returns "array".
+func (s *ArraySchema) Type() string { return "array" }
+
+// Type returns "map".
+func (s *MapSchema) Type() string { return "map" }
+
+// Type returns "union".
+func (s *UnionSchema) Type() string { return "union" }
+
+// ParsedSchema returns the tree describing the schema used to create the Codec,
+// which may be walked to inspect its types, record fields, and default values.
+// The tree is shared by every call, and ought not be modified.
+func (c *Codec) ParsedSchema() Schema {
+	return c.schema
+}
+
+// schemaTreeBuilder builds a Schema tree from a schema decoded from JSON,
+// following the same name resolution rules as buildCodec.
+type schemaTreeBuilder struct {
+	named map[string]Schema // named types, by full name
+}
+
+func newSchemaTree(schema interface{}) (Schema, error) {
+	b := &schemaTreeBuilder{named: make(map[string]Schema)}
+	return b.build(nullNamespace, schema)
+}
+
+func (b *schemaTreeBuilder) build(enclosingNamespace string, schema interface{}) (Schema, error) {
+	switch v := schema.(type) {
+	case string:
+		return b.buildFromString(enclosingNamespace, v, nil)
+	case map[string]interface{}:
+		return b.buildFromMap(enclosingNamespace, v)
+	case []interface{}:
+		members := make([]Schema, len(v))
+		for i, member := range v {
+			s, err := b.build(enclosingNamespace, member)
+			if err != nil {
+				return nil, err
+			}
+			members[i] = s
+		}
+		return &UnionSchema{Members: members}, nil
+	default:
+		return nil, fmt.Errorf("type ought to be either string, map[string]interface{}, or []interface{}; received: %T", schema)
+	}
+}
+
+func (b *schemaTreeBuilder) buildFromMap(enclosingNamespace string, schemaMap map[string]interface{}) (Schema, error) {
+	switch v := schemaMap["type"].(type) {
+	case string:
+		return b.buildFromString(enclosingNamespace, v, schemaMap)
+	case map[string]interface{}, []interface{}:
+		return b.build(enclosingNamespace, v)
+	default:
+		return nil, fmt.Errorf("type ought to be either string, map[string]interface{}, or []interface{}; received: %T", v)
+	}
+}
+
+func (b *schemaTreeBuilder) buildFromString(enclosingNamespace, typeName string, schemaMap map[string]interface{}) (Schema, error) {
+	switch typeName {
+	case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+		s := &PrimitiveSchema{Name: typeName}
+		s.LogicalType, _ = schemaMap["logicalType"].(string)
+		s.Properties = schemaProperties(schemaMap, "type", "logicalType")
+		return s, nil
+	case "array":
+		s := &ArraySchema{Properties: schemaProperties(schemaMap, "type", "items")}
+		items, err := b.build(enclosingNamespace, schemaMap["items"])
+		if err != nil {
+			return nil, fmt.Errorf("Array items ought to be valid Avro type: %w", err)
+		}
+		s.Items = items
+		return s, nil
+	case "map":
+		s := &MapSchema{Properties: schemaProperties(schemaMap, "type", "values")}
+		values, err := b.build(enclosingNamespace, schemaMap["values"])
+		if err != nil {
+			return nil, fmt.Errorf("Map values ought to be valid Avro type: %w", err)
+		}
+		s.Values = values
+		return s, nil
+	case "enum", "fixed", "record", "error":
+		return b.buildNamed(enclosingNamespace, typeName, schemaMap)
+	}
+
+	// Same lookup order as buildCodecForTypeDescribedByString.
+	if enclosingNamespace != nullNamespace && !strings.ContainsRune(typeName, '.') {
+		if s, ok := b.named[enclosingNamespace+"."+typeName]; ok {
+			return s, nil
+		}
+	}
+	if s, ok := b.named[typeName]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("unknown type name: %q", typeName)
+}
+
+func (b *schemaTreeBuilder) buildNamed(enclosingNamespace, typeName string, schemaMap map[string]interface{}) (Schema, error) {
+	if _, ok := schemaMap["name"]; !ok && typeName == "fixed" && schemaMap["logicalType"] != nil {
+		// NOTE: Codecs for logical types allow an anonymous fixed, which
+		// cannot be referenced elsewhere in the schema.
+		return b.buildFixed(&name{}, schemaMap)
+	}
+	n, err := newNameFromSchemaMap(enclosingNamespace, schemaMap)
+	if err != nil {
+		return nil, err
+	}
+	aliases := schemaAliases(schemaMap, n.namespace)
+	doc, _ := schemaMap["doc"].(string)
+
+	switch typeName {
+	case "enum":
+		s := &EnumSchema{
+			FullName:   n.fullName,
+			Aliases:    aliases,
+			Doc:        doc,
+			Properties: schemaProperties(schemaMap, "type", "name", "namespace", "aliases", "doc", "symbols", "default"),
+		}
+		s.Default, _ = schemaMap["default"].(string)
+		symbols, _ := schemaMap["symbols"].([]interface{})
+		for _, symbol := range symbols {
+			if symbol, ok := symbol.(string); ok {
+				s.Symbols = append(s.Symbols, symbol)
+			}
+		}
+		b.named[n.fullName] = s
+		return s, nil
+	case "fixed":
+		s, err := b.buildFixed(n, schemaMap)
+		if err != nil {
+			return nil, err
+		}
+		s.Aliases = aliases
+		b.named[n.fullName] = s
+		return s, nil
+	}
+
+	s := &RecordSchema{
+		FullName:   n.fullName,
+		Aliases:    aliases,
+		Doc:        doc,
+		Properties: schemaProperties(schemaMap, "type", "name", "namespace", "aliases", "doc", "fields"),
+	}
+	// NOTE: Register the record before its fields, to support recursive data
+	// types.
+	b.named[n.fullName] = s
+
+	fields, _ := schemaMap["fields"].([]interface{})
+	for i, field := range fields {
+		fieldMap, ok := field.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Record %q field %d ought to be valid Avro named type; received: %v", n, i+1, field)
+		}
+		fieldType, err := b.buildFromMap(n.namespace, fieldMap)
+		if err != nil {
+			return nil, fmt.Errorf("Record %q field %d ought to be valid Avro named type: %w", n, i+1, err)
+		}
+		f := &Field{
+			Type:       fieldType,
+			Properties: schemaProperties(fieldMap, "type", "name", "aliases", "doc", "default", "order"),
+		}
+		f.Name, _ = fieldMap["name"].(string)
+		f.Doc, _ = fieldMap["doc"].(string)
+		f.Default, f.HasDefault = fieldMap["default"]
+		f.Order, _ = recordFieldOrder(fieldMap) // already validated by the codec
+		f.Aliases = schemaAliases(fieldMap, nullNamespace)
+		s.Fields = append(s.Fields, f)
+	}
+	return s, nil
+}
+
+func (b *schemaTreeBuilder) buildFixed(n *name, schemaMap map[string]interface{}) (*FixedSchema, error) {
+	size, err := sizeFromSchemaMap(n, schemaMap)
+	if err != nil {
+		return nil, err
+	}
+	s := &FixedSchema{
+		FullName:   n.fullName,
+		Size:       size,
+		Properties: schemaProperties(schemaMap, "type", "name", "namespace", "aliases", "size", "logicalType"),
+	}
+	s.LogicalType, _ = schemaMap["logicalType"].(string)
+	return s, nil
+}
+
+// schemaAliases returns the aliases of a schema, qualified using namespace when
+// they are not already fully qualified.
+func schemaAliases(schemaMap map[string]interface{}, namespace string) []string {
+	raw, _ := schemaMap["aliases"].([]interface{})
+	var aliases []string
+	for _, alias := range raw {
+		alias, ok := alias.(string)
+		if !ok {
+			continue
+		}
+		if namespace != nullNamespace && !strings.ContainsRune(alias, '.') {
+			alias = namespace + "." + alias
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases
+}
+
+// schemaProperties returns the attributes of schemaMap other than the reserved
+// ones, or nil when there are none.
+func schemaProperties(schemaMap map[string]interface{}, reserved ...string) map[string]interface{} {
+	var properties map[string]interface{}
+outer:
+	for key, value := range schemaMap {
+		for _, r := range reserved {
+			if key == r {
+				continue outer
+			}
+		}
+		if properties == nil {
+			properties = make(map[string]interface{})
+		}
+		properties[key] = value
+	}
+	return properties
+}
@@ -11,6 +11,7 @@ package goavro
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -269,3 +270,177 @@ func TestUnionOfRecordsDefaultValueOughtToEncodeUsingFieldSchemaOK(t *testing.T)
 	}`)
 
 }
+
+func ExampleCodec_ParsedSchema() {
+	codec, err := NewCodec(`{"type":"record","name":"r1","namespace":"com.example","fields":[{"name":"f1","type":["null","string"],"default":null}]}`)
+	if err != nil {
+		fmt.Println(err)
+	}
+	record := codec.ParsedSchema().(*RecordSchema)
+	for _, field := range record.Fields {
+		fmt.Println(record.FullName, field.Name, field.Type.Type(), field.Default)
+	}
+	// Output: com.example.r1 f1 union <nil>
+}
+
+func TestParsedSchemaPrimitive(t *testing.T) {
+	for _, typeName := range []string{"null", "boolean", "int", "long", "float", "double", "bytes", "string"} {
+		for _, schema := range []string{`"` + typeName + `"`, `{"type":"` + typeName + `"}`} {
+			codec, err := NewCodec(schema)
+			ensureError(t, err)
+			s, ok := codec.ParsedSchema().(*PrimitiveSchema)
+			if !ok {
+				t.Fatalf("GOT: %T; WANT: *PrimitiveSchema", codec.ParsedSchema())
+			}
+			if actual, expected := s.Type(), typeName; actual != expected {
+				t.Errorf("GOT: %v; WANT: %v", actual, expected)
+			}
+		}
+	}
+}
+
+func TestParsedSchemaLogicalType(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[
+		{"name":"f1","type":{"type":"bytes","logicalType":"decimal","precision":4,"scale":2}},
+		{"name":"f2","type":"long","logicalType":"timestamp-millis"},
+		{"name":"f3","type":{"type":"fixed","size":12,"logicalType":"duration"}}
+	]}`)
+	ensureError(t, err)
+	fields := codec.ParsedSchema().(*RecordSchema).Fields
+
+	decimal := fields[0].Type.(*PrimitiveSchema)
+	if actual, expected := decimal.LogicalType, "decimal"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := decimal.Properties, map[string]interface{}{"precision": 4.0, "scale": 2.0}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	// logicalType may be given as an attribute of the field itself
+	if actual, expected := fields[1].Type.(*PrimitiveSchema).LogicalType, "timestamp-millis"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	duration := fields[2].Type.(*FixedSchema)
+	if actual, expected := duration.LogicalType, "duration"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := duration.FullName, ""; actual != expected {
+		t.Errorf("GOT: %q; WANT: %q", actual, expected)
+	}
+	if actual, expected := duration.Size, uint(12); actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
+
+func TestParsedSchemaRecord(t *testing.T) {
+	codec, err := NewCodec(`{
+		"type":"record",
+		"name":"r1",
+		"namespace":"com.example",
+		"aliases":["r0","org.example.r0"],
+		"doc":"some record",
+		"fields":[
+			{"name":"f1","type":{"type":"enum","name":"e1","symbols":["alpha","bravo"],"default":"alpha"},"default":"bravo","aliases":["g1"]},
+			{"name":"f2","type":{"type":"fixed","name":"other.x1","size":4},"doc":"some fixed","order":"descending"},
+			{"name":"f3","type":{"type":"array","items":"e1"}},
+			{"name":"f4","type":{"type":"map","values":"other.x1"},"default":{}},
+			{"name":"f5","type":["null","r1"],"default":null}
+		]
+	}`)
+	ensureError(t, err)
+
+	record, ok := codec.ParsedSchema().(*RecordSchema)
+	if !ok {
+		t.Fatalf("GOT: %T; WANT: *RecordSchema", codec.ParsedSchema())
+	}
+	if actual, expected := record.FullName, "com.example.r1"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := record.Aliases, []string{"com.example.r0", "org.example.r0"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := record.Doc, "some record"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := len(record.Fields), 5; actual != expected {
+		t.Fatalf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	f1 := record.Fields[0]
+	enum := f1.Type.(*EnumSchema)
+	if actual, expected := enum.FullName, "com.example.e1"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := enum.Symbols, []string{"alpha", "bravo"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := enum.Default, "alpha"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if !f1.HasDefault || f1.Default != "bravo" {
+		t.Errorf("GOT: %v, %v; WANT: true, bravo", f1.HasDefault, f1.Default)
+	}
+	if actual, expected := f1.Aliases, []string{"g1"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+
+	f2 := record.Fields[1]
+	fixed := f2.Type.(*FixedSchema)
+	if actual, expected := fixed.FullName, "other.x1"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := fixed.Size, uint(4); actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if f2.HasDefault {
+		t.Errorf("GOT: %v; WANT: %v", f2.HasDefault, false)
+	}
+	if actual, expected := f2.Doc, "some fixed"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := f2.Order, "descending"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := f1.Order, "ascending"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if _, ok := f2.Properties["order"]; ok {
+		t.Errorf("GOT: %v; WANT: no order property", f2.Properties)
+	}
+
+	// references to named types resolve to the same node
+	if actual, expected := record.Fields[2].Type.(*ArraySchema).Items, Schema(enum); actual != expected {
+		t.Errorf("GOT: %p; WANT: %p", actual, expected)
+	}
+	if actual, expected := record.Fields[3].Type.(*MapSchema).Values, Schema(fixed); actual != expected {
+		t.Errorf("GOT: %p; WANT: %p", actual, expected)
+	}
+
+	union := record.Fields[4].Type.(*UnionSchema)
+	if actual, expected := len(union.Members), 2; actual != expected {
+		t.Fatalf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := union.Members[0].Type(), "null"; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	if actual, expected := union.Members[1], Schema(record); actual != expected {
+		t.Errorf("GOT: %p; WANT: %p", actual, expected)
+	}
+	if !record.Fields[4].HasDefault || record.Fields[4].Default != nil {
+		t.Errorf("GOT: %v, %v; WANT: true, <nil>", record.Fields[4].HasDefault, record.Fields[4].Default)
+	}
+}
+
+func TestParsedSchemaProperties(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","custom":"some value","fields":[{"name":"f1","type":"int","order":"descending","custom":"field value"}]}`)
+	ensureError(t, err)
+	record := codec.ParsedSchema().(*RecordSchema)
+	if actual, expected := record.Properties, map[string]interface{}{"custom": "some value"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	// order is an attribute of the field rather than a custom property
+	if actual, expected := record.Fields[0].Properties, map[string]interface{}{"custom": "field value"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+}
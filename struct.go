@@ -0,0 +1,486 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// WithStrictStructFields returns a CodecOption which, when enabled is true,
+// makes BinaryFromStruct return an error when a struct has an exported field
+// which does not match any field of its record schema, rather than ignoring
+// that field. When enabled is false, such fields are ignored.
+func WithStrictStructFields(enabled bool) CodecOption {
+	return func(o *codecOptions) {
+		o.strictStructFields = enabled
+	}
+}
+
+// BinaryFromStruct appends the binary encoded byte slice representation of the
+// provided Go struct to buf, like BinaryFromNative does for its native form.
+//
+// Each exported struct field is encoded as the record field of the same name,
+// or of the name given by its `avro:"name"` tag. Fields tagged `avro:"-"` are
+// ignored, as are other fields which do not match any record field, unless the
+// Codec was created using the WithStrictStructFields option. Fields of exported
+// embedded structs without a tag are treated as fields of the outer struct. Record
+// fields without a matching struct field are encoded using their default
+// values.
+//
+// Nested structs, slices, and maps are converted likewise, following the schema
+// of the record field they are encoded as. Values of types derived from string,
+// such as an enum type, are passed to BinaryFromNative as strings, and byte
+// arrays, such as those used for fixed fields, as byte slices. Other values are
+// passed to BinaryFromNative as they are. For a union, a nil pointer encodes
+// null, a struct selects the record member whose name is that of the struct
+// type, or failing that, the first record member, a slice selects the array
+// member, and a map selects the map member.
+//
+//     type Address struct {
+//         City string `avro:"city"`
+//     }
+//     type Person struct {
+//         Name    string   `avro:"name"`
+//         Address *Address `avro:"address"`
+//     }
+//
+//     codec, err := goavro.NewCodec(`{"type":"record","name":"Person","fields":[{"name":"name","type":"string"},{"name":"address","type":["null",{"type":"record","name":"Address","fields":[{"name":"city","type":"string"}]}]}]}`)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     buf, err := codec.BinaryFromStruct(nil, Person{Name: "Ann", Address: &Address{City: "Oslo"}})
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     fmt.Printf("%#v", buf)
+//     // Output: []byte{0x6, 0x41, 0x6e, 0x6e, 0x2, 0x8, 0x4f, 0x73, 0x6c, 0x6f}
+func (c *Codec) BinaryFromStruct(buf []byte, v interface{}) ([]byte, error) {
+	datum, err := nativeFromStruct(c, reflect.ValueOf(v), c.strictStructFields)
+	if err != nil {
+		return buf, err // if error, return original byte slice
+	}
+	return c.BinaryFromNative(buf, datum)
+}
+
+// nativeFromStruct returns the native form of rv for codec c, converting structs
+// to the maps record codecs encode, and the slices and maps containing them to
+// the forms array and map codecs encode.
+func nativeFromStruct(c *Codec, rv reflect.Value, strict bool) (interface{}, error) {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	if c.unionInfo != nil {
+		return unionNativeFromStruct(c.unionInfo, rv, strict)
+	}
+
+	switch c.baseType() {
+	case "record":
+		if rv.Kind() == reflect.Struct {
+			return recordNativeFromStruct(c, rv, strict)
+		}
+	case "array":
+		if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8 {
+			items := make([]interface{}, rv.Len())
+			for i := range items {
+				item, err := nativeFromStruct(c.itemCodec, rv.Index(i), strict)
+				if err != nil {
+					return nil, fmt.Errorf("cannot encode binary array item %d: %w", i+1, err)
+				}
+				items[i] = item
+			}
+			return items, nil
+		}
+	case "map":
+		if rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String {
+			values := make(map[string]interface{}, rv.Len())
+			iter := rv.MapRange()
+			for iter.Next() {
+				key := iter.Key().String()
+				value, err := nativeFromStruct(c.itemCodec, iter.Value(), strict)
+				if err != nil {
+					return nil, fmt.Errorf("cannot encode binary map value for %q: %w", key, err)
+				}
+				values[key] = value
+			}
+			return values, nil
+		}
+	}
+	return scalarNativeFromStruct(rv), nil
+}
+
+// scalarNativeFromStruct returns the native form of rv, converting values of
+// types derived from string, such as those used for enum symbols, to strings,
+// and byte arrays and slices, such as those used for fixed values, to byte
+// slices, which the codecs of those types encode. Other values are returned as
+// they are.
+func scalarNativeFromStruct(rv reflect.Value) interface{} {
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return b
+		}
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Bytes()
+		}
+	}
+	return rv.Interface()
+}
+
+// unionNativeFromStruct returns the native form of rv for the union member
+// selected by its Go kind, wrapped as returned by the Union function, or rv as
+// it is when it does not select a composite member.
+func unionNativeFromStruct(cr *codecInfo, rv reflect.Value, strict bool) (interface{}, error) {
+	var member string
+	switch rv.Kind() {
+	case reflect.Struct:
+		if rv.Type() == timeType {
+			break // NOTE: the native form of timestamp logical types
+		}
+		for _, name := range cr.allowedTypes {
+			memberCodec := cr.codecFromName[name]
+			if memberCodec.schemaType != "record" {
+				continue
+			}
+			if memberCodec.typeName.short() == rv.Type().Name() {
+				member = name
+				break
+			}
+			if member == "" {
+				member = name
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			member = "array"
+		}
+	case reflect.Map:
+		member = "map"
+	}
+	memberCodec, ok := cr.codecFromName[member]
+	if !ok {
+		return scalarNativeFromStruct(rv), nil
+	}
+	value, err := nativeFromStruct(memberCodec, rv, strict)
+	if err != nil {
+		return nil, err
+	}
+	return Union(member, value), nil
+}
+
+func recordNativeFromStruct(c *Codec, rv reflect.Value, strict bool) (interface{}, error) {
+	fieldCodecs := make(map[string]*Codec, len(c.recordFields))
+	for _, field := range c.recordFields {
+		fieldCodecs[field.name] = field.codec
+	}
+
+	var fields []structField
+	if err := appendStructFields(&fields, rv.Type(), nil); err != nil {
+		return nil, fmt.Errorf("cannot encode binary record %q: %w", c.typeName, err)
+	}
+
+	recordMap := make(map[string]interface{}, len(c.recordFields))
+	for _, field := range fields {
+		fieldCodec, ok := fieldCodecs[field.name]
+		if !ok {
+			if strict {
+				return nil, fmt.Errorf("cannot encode binary record %q: struct field %q does not match any record field", c.typeName, field.goName)
+			}
+			continue
+		}
+		fv, err := rv.FieldByIndexErr(field.index)
+		if err != nil {
+			continue // NOTE: field of a nil embedded struct pointer
+		}
+		value, err := nativeFromStruct(fieldCodec, fv, strict)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode binary record %q field %q: %w", c.typeName, field.name, err)
+		}
+		recordMap[field.name] = value
+	}
+	return recordMap, nil
+}
+
+// structField describes an exported struct field, and the record field name it
+// is encoded as.
+type structField struct {
+	name   string // record field name
+	goName string
+	index  []int
+}
+
+// appendStructFields appends the fields of struct type t to fields, including
+// those of its embedded structs without a tag, whose index is prefixed by
+// index.
+func appendStructFields(fields *[]structField, t reflect.Type, index []int) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("avro")
+		if tag == "-" {
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := appendStructFields(fields, ft, fieldIndex); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		name := tag
+		if name == "" {
+			name = f.Name
+		}
+		for _, other := range *fields {
+			if other.name == name {
+				return fmt.Errorf("struct fields %q and %q ought to have unique names: %q", other.goName, f.Name, name)
+			}
+		}
+		*fields = append(*fields, structField{name: name, goName: f.Name, index: fieldIndex})
+	}
+	return nil
+}
+
+// NativeToStruct stores the native datum of the Codec, as returned by
+// NativeFromBinary or NativeFromTextual, in the Go value out points to, which
+// is usually a pointer to a struct. It is the converse of BinaryFromStruct, and
+// matches struct fields to record fields the same way.
+//
+// Record fields without a matching struct field are ignored, and struct fields
+// without a matching record field are left unchanged. Union values are
+// unwrapped from the single key map naming their member, and a null value sets
+// the field to its zero value, such as nil for a pointer. Pointers are allocated
+// as needed. Numeric values are converted to the Go type of the field when it
+// can hold them, and strings and bytes to types derived from them.
+//
+//     var person Person
+//     datum, _, err := codec.NativeFromBinary(buf)
+//     if err != nil {
+//         return err
+//     }
+//     if err = codec.NativeToStruct(datum, &person); err != nil {
+//         return err
+//     }
+func (c *Codec) NativeToStruct(datum interface{}, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot decode struct: expected non-nil pointer; received: %T", out)
+	}
+	return structFromNative(c, datum, rv.Elem())
+}
+
+// structFromNative stores the native datum of codec c in rv.
+func structFromNative(c *Codec, datum interface{}, rv reflect.Value) error {
+	// NOTE: Values of nullable unions are decoded as pointers.
+	if dv := reflect.ValueOf(datum); dv.Kind() == reflect.Ptr {
+		if dv.IsNil() {
+			datum = nil
+		} else {
+			datum = dv.Elem().Interface()
+		}
+	}
+
+	if c.unionInfo != nil && datum != nil {
+		cr := c.unionInfo
+		if m, ok := datum.(map[string]interface{}); ok && len(m) == 1 {
+			for name, value := range m {
+				if memberCodec, ok := cr.codecFromName[name]; ok {
+					return structFromNative(memberCodec, value, rv)
+				}
+			}
+		}
+		// NOTE: A value which does not name its member is the bare value of
+		// the other member of a nullable union.
+		if index, ok := cr.nullableIndex(); ok {
+			return structFromNative(cr.codecFromIndex[index], datum, rv)
+		}
+		return fmt.Errorf("cannot decode union: expected single key map naming a member schema type: allowed types: %v; received: %T", cr.allowedTypes, datum)
+	}
+
+	if datum == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return structFromNative(c, datum, rv.Elem())
+	case reflect.Interface:
+		if dv := reflect.ValueOf(datum); dv.Type().AssignableTo(rv.Type()) {
+			rv.Set(dv)
+			return nil
+		}
+		return fmt.Errorf("cannot decode %T into %s", datum, rv.Type())
+	}
+
+	switch c.baseType() {
+	case "record":
+		if m, ok := datum.(map[string]interface{}); ok && rv.Kind() == reflect.Struct {
+			return recordToStruct(c, m, rv)
+		}
+	case "array":
+		if items, ok := datum.([]interface{}); ok && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) {
+			if rv.Kind() == reflect.Slice {
+				rv.Set(reflect.MakeSlice(rv.Type(), len(items), len(items)))
+			} else if rv.Len() != len(items) {
+				return fmt.Errorf("cannot decode array of %d items into %s", len(items), rv.Type())
+			}
+			for i, item := range items {
+				if err := structFromNative(c.itemCodec, item, rv.Index(i)); err != nil {
+					return fmt.Errorf("cannot decode array item %d: %w", i+1, err)
+				}
+			}
+			return nil
+		}
+	case "map":
+		if values, ok := datum.(map[string]interface{}); ok && rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String {
+			rv.Set(reflect.MakeMapWithSize(rv.Type(), len(values)))
+			for key, value := range values {
+				elem := reflect.New(rv.Type().Elem()).Elem()
+				if err := structFromNative(c.itemCodec, value, elem); err != nil {
+					return fmt.Errorf("cannot decode map value for %q: %w", key, err)
+				}
+				rv.SetMapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()), elem)
+			}
+			return nil
+		}
+	}
+	return assignNative(datum, rv)
+}
+
+func recordToStruct(c *Codec, m map[string]interface{}, rv reflect.Value) error {
+	fieldCodecs := make(map[string]*Codec, len(c.recordFields))
+	for _, field := range c.recordFields {
+		fieldCodecs[field.name] = field.codec
+	}
+
+	var fields []structField
+	if err := appendStructFields(&fields, rv.Type(), nil); err != nil {
+		return fmt.Errorf("cannot decode record %q: %w", c.typeName, err)
+	}
+	for _, field := range fields {
+		fieldCodec, ok := fieldCodecs[field.name]
+		if !ok {
+			continue
+		}
+		value, ok := m[field.name]
+		if !ok {
+			continue
+		}
+		fv, err := fieldByIndexAlloc(rv, field.index)
+		if err != nil {
+			return fmt.Errorf("cannot decode record %q field %q: %w", c.typeName, field.name, err)
+		}
+		if err = structFromNative(fieldCodec, value, fv); err != nil {
+			return fmt.Errorf("cannot decode record %q field %q: %w", c.typeName, field.name, err)
+		}
+	}
+	return nil
+}
+
+// fieldByIndexAlloc returns the nested field of rv at index, allocating the
+// embedded struct pointers it goes through.
+func fieldByIndexAlloc(rv reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %s", rv.Type().Elem())
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, nil
+}
+
+// assignNative stores a native value in rv, converting numeric values to the
+// numeric type of rv when it can hold them, and strings and bytes to types
+// derived from them.
+func assignNative(datum interface{}, rv reflect.Value) error {
+	dv := reflect.ValueOf(datum)
+	if dv.Type().AssignableTo(rv.Type()) {
+		rv.Set(dv)
+		return nil
+	}
+	if e, ok := datum.(avroEnum); ok && rv.Kind() == reflect.String {
+		rv.SetString(e.Str())
+		return nil
+	}
+	switch dk, rk := dv.Kind(), rv.Kind(); {
+	case isIntKind(dk):
+		i := dv.Int()
+		switch {
+		case isIntKind(rk) && !rv.OverflowInt(i):
+			rv.SetInt(i)
+			return nil
+		case isUintKind(rk) && i >= 0 && !rv.OverflowUint(uint64(i)):
+			rv.SetUint(uint64(i))
+			return nil
+		case rk == reflect.Float32 || rk == reflect.Float64:
+			rv.SetFloat(float64(i))
+			return nil
+		}
+	case dk == reflect.Float32 || dk == reflect.Float64:
+		if rk == reflect.Float32 || rk == reflect.Float64 {
+			rv.SetFloat(dv.Float())
+			return nil
+		}
+	case dk == reflect.String && rk == reflect.String:
+		rv.SetString(dv.String())
+		return nil
+	case dk == reflect.Slice && (rk == reflect.Slice || rk == reflect.Array) && dv.Type().Elem().Kind() == reflect.Uint8 && rv.Type().Elem().Kind() == reflect.Uint8:
+		if rk == reflect.Slice {
+			rv.SetBytes(dv.Bytes())
+			return nil
+		}
+		if rk == reflect.Array && rv.Len() == dv.Len() {
+			reflect.Copy(rv, dv)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot decode %T into %s", datum, rv.Type())
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
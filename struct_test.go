@@ -0,0 +1,304 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testStructSchema = `{"type":"record","name":"Person","fields":[
+	{"name":"name","type":"string"},
+	{"name":"age","type":"int"},
+	{"name":"nickname","type":"string","default":"none"},
+	{"name":"born","type":{"type":"long","logicalType":"timestamp-millis"}},
+	{"name":"address","type":["null",{"type":"record","name":"Address","fields":[{"name":"city","type":"string"},{"name":"zip","type":["null","string"],"default":null}]}],"default":null},
+	{"name":"previous","type":{"type":"array","items":"Address"}},
+	{"name":"labels","type":{"type":"map","values":"Address"}},
+	{"name":"ID","type":"long"}
+]}`
+
+type testAddress struct {
+	City string  `avro:"city"`
+	Zip  *string `avro:"zip"`
+}
+
+type StructIdentity struct {
+	ID int64
+}
+
+type testPerson struct {
+	StructIdentity
+	Name     string                 `avro:"name"`
+	Age      int                    `avro:"age"`
+	Born     time.Time              `avro:"born"`
+	Address  *testAddress           `avro:"address"`
+	Previous []testAddress          `avro:"previous"`
+	Labels   map[string]testAddress `avro:"labels"`
+	Ignored  string                 `avro:"-"`
+	secret   string
+}
+
+// testStructPass ensures v encodes the same as datum does.
+func testStructPass(t *testing.T, codec *Codec, v interface{}, datum interface{}) {
+	t.Helper()
+	expected, err := codec.BinaryFromNative(nil, datum)
+	ensureError(t, err)
+	actual, err := codec.BinaryFromStruct(nil, v)
+	ensureError(t, err)
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+	}
+}
+
+func TestBinaryFromStruct(t *testing.T) {
+	codec, err := NewCodec(testStructSchema)
+	ensureError(t, err)
+
+	born := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+	zip := "0150"
+	person := testPerson{
+		StructIdentity: StructIdentity{ID: 13},
+		Name:           "Ann",
+		Age:            42,
+		Born:           born,
+		Address:        &testAddress{City: "Oslo", Zip: &zip},
+		Previous:       []testAddress{{City: "Bergen"}},
+		Labels:         map[string]testAddress{"home": {City: "Oslo"}},
+		Ignored:        "ignored",
+		secret:         "secret",
+	}
+	testStructPass(t, codec, person, map[string]interface{}{
+		"ID":       int64(13),
+		"name":     "Ann",
+		"age":      42,
+		"nickname": "none",
+		"born":     born,
+		"address":  Union("Address", map[string]interface{}{"city": "Oslo", "zip": Union("string", "0150")}),
+		"previous": []interface{}{map[string]interface{}{"city": "Bergen", "zip": nil}},
+		"labels":   map[string]interface{}{"home": map[string]interface{}{"city": "Oslo", "zip": nil}},
+	})
+
+	// a pointer to a struct encodes the same, and nil pointers encode null
+	person.Address = nil
+	testStructPass(t, codec, &person, map[string]interface{}{
+		"ID":       int64(13),
+		"name":     "Ann",
+		"age":      42,
+		"born":     born,
+		"address":  nil,
+		"previous": []interface{}{map[string]interface{}{"city": "Bergen"}},
+		"labels":   map[string]interface{}{"home": map[string]interface{}{"city": "Oslo"}},
+	})
+}
+
+func TestBinaryFromStructFail(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"a","type":"int"},{"name":"b","type":{"type":"array","items":{"type":"record","name":"r2","fields":[{"name":"c","type":"string"}]}}}]}`)
+	ensureError(t, err)
+
+	type inner struct {
+		C int `avro:"c"`
+	}
+	type outer struct {
+		A     int     `avro:"a"`
+		B     []inner `avro:"b"`
+		Extra bool
+	}
+
+	buf := []byte("prefix")
+	actual, err := codec.BinaryFromStruct(buf, outer{A: 1, B: []inner{{C: 3}}})
+	ensureError(t, err, `cannot encode binary record "r1" field "b"`, "string")
+	if !bytes.Equal(actual, buf) {
+		t.Errorf("GOT: %q; WANT: %q", actual, buf)
+	}
+
+	type missing struct {
+		B []inner `avro:"b"`
+	}
+	_, err = codec.BinaryFromStruct(nil, missing{})
+	ensureError(t, err, `field "a": schema does not specify default value and no value provided`)
+
+	type duplicate struct {
+		A int `avro:"a"`
+		B int `avro:"a"`
+	}
+	_, err = codec.BinaryFromStruct(nil, duplicate{})
+	ensureError(t, err, `struct fields "A" and "B" ought to have unique names: "a"`)
+
+	// unmapped struct fields are ignored, unless the codec is strict
+	_, err = codec.BinaryFromStruct(nil, outer{A: 1})
+	ensureError(t, err)
+	strict, err := NewCodecWithOptions(codec.Schema(), WithStrictStructFields(true))
+	ensureError(t, err)
+	_, err = strict.BinaryFromStruct(nil, outer{A: 1})
+	ensureError(t, err, `cannot encode binary record "r1": struct field "Extra" does not match any record field`)
+}
+
+func TestBinaryFromStructUnionRecordMembers(t *testing.T) {
+	codec, err := NewCodec(`["null",{"type":"record","name":"first","fields":[{"name":"a","type":"int"}]},{"type":"record","name":"second","fields":[{"name":"a","type":"int"}]}]`)
+	ensureError(t, err)
+
+	type second struct {
+		A int `avro:"a"`
+	}
+	type other struct {
+		A int `avro:"a"`
+	}
+	// a struct selects the record member of the same name, or the first one
+	testStructPass(t, codec, second{A: 3}, Union("second", map[string]interface{}{"a": 3}))
+	testStructPass(t, codec, &other{A: 3}, Union("first", map[string]interface{}{"a": 3}))
+	testStructPass(t, codec, (*other)(nil), nil)
+}
+
+func TestNativeToStructRoundTrip(t *testing.T) {
+	codec, err := NewCodec(testStructSchema)
+	ensureError(t, err)
+
+	zip := "0150"
+	for _, person := range []testPerson{
+		{
+			StructIdentity: StructIdentity{ID: 13},
+			Name:           "Ann",
+			Age:            42,
+			Born:           time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC),
+			Address:        &testAddress{City: "Oslo", Zip: &zip},
+			Previous:       []testAddress{{City: "Bergen"}, {City: "Tromsø", Zip: &zip}},
+			Labels:         map[string]testAddress{"home": {City: "Oslo"}},
+		},
+		{Name: "Bob", Born: time.Unix(0, 0).UTC(), Previous: []testAddress{}, Labels: map[string]testAddress{}},
+	} {
+		buf, err := codec.BinaryFromStruct(nil, person)
+		ensureError(t, err)
+		datum, _, err := codec.NativeFromBinary(buf)
+		ensureError(t, err)
+		var binary testPerson
+		ensureError(t, codec.NativeToStruct(datum, &binary))
+		if !reflect.DeepEqual(binary, person) {
+			t.Errorf("GOT: %#v; WANT: %#v", binary, person)
+		}
+
+		// textual data decodes union values differently
+		text, err := codec.TextualFromNative(nil, datum)
+		ensureError(t, err)
+		datum, _, err = codec.NativeFromTextual(text)
+		ensureError(t, err)
+		var textual testPerson
+		ensureError(t, codec.NativeToStruct(datum, &textual))
+		if !reflect.DeepEqual(textual, person) {
+			t.Errorf("GOT: %#v; WANT: %#v", textual, person)
+		}
+	}
+}
+
+type testColor string
+
+func TestNativeToStructRoundTripEnumFixed(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[
+		{"name":"color","type":{"type":"enum","name":"e1","symbols":["red","blue"]}},
+		{"name":"other","type":["null","e1"]},
+		{"name":"id","type":{"type":"fixed","name":"f1","size":4}}
+	]}`)
+	ensureError(t, err)
+
+	type value struct {
+		Color testColor  `avro:"color"`
+		Other *testColor `avro:"other"`
+		ID    [4]byte    `avro:"id"`
+	}
+	blue := testColor("blue")
+	for _, v := range []value{
+		{Color: "red", Other: &blue, ID: [4]byte{1, 2, 3, 4}},
+		{Color: "blue"},
+	} {
+		buf, err := codec.BinaryFromStruct(nil, v)
+		ensureError(t, err)
+		datum, _, err := codec.NativeFromBinary(buf)
+		ensureError(t, err)
+		var actual value
+		ensureError(t, codec.NativeToStruct(datum, &actual))
+		if !reflect.DeepEqual(actual, v) {
+			t.Errorf("GOT: %#v; WANT: %#v", actual, v)
+		}
+	}
+}
+
+func TestNativeToStruct(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[
+		{"name":"color","type":{"type":"enum","name":"e1","symbols":["red","blue"]}},
+		{"name":"hash","type":{"type":"fixed","name":"f1","size":4}},
+		{"name":"count","type":["null","long","string"]},
+		{"name":"any","type":["null","long","string"]},
+		{"name":"small","type":"int"},
+		{"name":"unmapped","type":"int"}
+	]}`)
+	ensureError(t, err)
+
+	type color string
+	type out struct {
+		Color color       `avro:"color"`
+		Hash  [4]byte     `avro:"hash"`
+		Count *uint16     `avro:"count"`
+		Any   interface{} `avro:"any"`
+		Small float64     `avro:"small"`
+		Other string
+	}
+	datum := map[string]interface{}{
+		"color":    "blue",
+		"hash":     []byte("abcd"),
+		"count":    Union("long", int64(7)),
+		"any":      Union("string", "x"),
+		"small":    int32(3),
+		"unmapped": int32(5),
+	}
+	actual := out{Other: "unchanged"}
+	ensureError(t, codec.NativeToStruct(datum, &actual))
+	count := uint16(7)
+	if expected := (out{Color: "blue", Hash: [4]byte{'a', 'b', 'c', 'd'}, Count: &count, Any: "x", Small: 3, Other: "unchanged"}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+	}
+
+	// null union values set pointers to nil
+	datum["count"], datum["any"] = nil, nil
+	ensureError(t, codec.NativeToStruct(datum, &actual))
+	if actual.Count != nil || actual.Any != nil {
+		t.Errorf("GOT: %v, %v; WANT: nil", actual.Count, actual.Any)
+	}
+
+	datum["count"] = Union("long", int64(70000))
+	ensureError(t, codec.NativeToStruct(datum, &actual), `cannot decode record "r1" field "count": cannot decode int64 into uint16`)
+	datum["count"] = Union("string", "seven")
+	ensureError(t, codec.NativeToStruct(datum, &actual), `cannot decode record "r1" field "count": cannot decode string into uint16`)
+
+	ensureError(t, codec.NativeToStruct(datum, actual), "cannot decode struct: expected non-nil pointer; received: goavro.out")
+}
+
+func ExampleCodec_BinaryFromStruct() {
+	type Address struct {
+		City string `avro:"city"`
+	}
+	type Person struct {
+		Name    string   `avro:"name"`
+		Address *Address `avro:"address"`
+	}
+
+	codec, err := NewCodec(`{"type":"record","name":"Person","fields":[{"name":"name","type":"string"},{"name":"address","type":["null",{"type":"record","name":"Address","fields":[{"name":"city","type":"string"}]}]}]}`)
+	if err != nil {
+		fmt.Println(err)
+	}
+	buf, err := codec.BinaryFromStruct(nil, Person{Name: "Ann", Address: &Address{City: "Oslo"}})
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("%#v", buf)
+	// Output: []byte{0x6, 0x41, 0x6e, 0x6e, 0x2, 0x8, 0x4f, 0x73, 0x6c, 0x6f}
+}
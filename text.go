@@ -11,7 +11,6 @@ package goavro
 
 import (
 	"fmt"
-	"io"
 	"unicode"
 )
 
@@ -37,5 +36,5 @@ func advanceToNonWhitespace(buf []byte) ([]byte, error) {
 			return buf[i:], nil
 		}
 	}
-	return nil, io.ErrShortBuffer
+	return nil, ErrShortBuffer{}
 }
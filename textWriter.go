@@ -0,0 +1,145 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"fmt"
+	"io"
+)
+
+// textualWriterFlushSize is the number of encoded bytes buffered before they
+// are written to the io.Writer.
+const textualWriterFlushSize = 4096
+
+// TextualFromNativeWriter encodes datum like TextualFromNative does, but writes
+// the Avro data in JSON text format to w as it is encoded, rather than
+// appending it to a byte slice. Arrays and maps are written item by item, so a
+// large array of records is never held in memory as a whole; only each of its
+// items which is not itself an array or map is encoded in memory before being
+// written.
+//
+// When datum cannot be encoded, or w returns an error, the error is returned,
+// and some of the text of datum may already have been written to w.
+//
+//     w.Header().Set("Content-Type", "application/json")
+//     if err := codec.TextualFromNativeWriter(w, records); err != nil {
+//         log.Println(err)
+//     }
+func (c *Codec) TextualFromNativeWriter(w io.Writer, datum interface{}) error {
+	if c.strictRecordFields {
+		if err := c.checkRecordFields(datum, "textual"); err != nil {
+			return err
+		}
+	}
+	tw := &textualWriter{w: w, standard: c.standardJSON}
+	if err := tw.write(c, datum); err != nil {
+		return err
+	}
+	return tw.flush()
+}
+
+// textualWriter writes textual values to an io.Writer, buffering the text of
+// the values encoded by codecs until there is enough of it to write.
+type textualWriter struct {
+	w        io.Writer
+	buf      []byte
+	standard bool // write standard JSON rather than textual Avro data
+}
+
+func (tw *textualWriter) write(c *Codec, datum interface{}) error {
+	if c.annotated != nil {
+		// NOTE: The encoders of a registered logical type wrap those of the
+		// type it annotates, so convert the datum here, and only then write
+		// it.
+		value, err := c.registeredDatum("textual", datum)
+		if err != nil {
+			return err
+		}
+		return tw.write(c.annotated, value)
+	}
+	var err error
+	switch {
+	case c.itemCodec != nil && c.typeName.fullName == "array":
+		arrayValues, err := convertArray(datum)
+		if err != nil {
+			return fmt.Errorf("cannot encode textual array: %w", err)
+		}
+		tw.buf = append(tw.buf, '[')
+		for i, item := range arrayValues {
+			if i > 0 {
+				tw.buf = append(tw.buf, ',')
+			}
+			if err = tw.write(c.itemCodec, item); err != nil {
+				return fmt.Errorf("cannot encode textual array item %d; %v: %w", i+1, item, err)
+			}
+		}
+		tw.buf = append(tw.buf, ']')
+	case c.itemCodec != nil && c.typeName.fullName == "map":
+		mapValues, err := convertMap(datum)
+		if err != nil {
+			return fmt.Errorf("cannot encode textual map: %w", err)
+		}
+		tw.buf = append(tw.buf, '{')
+		var atLeastOne bool
+		for _, key := range mapKeys(mapValues, c.sortMapKeys) {
+			value := mapValues[key]
+			if atLeastOne {
+				tw.buf = append(tw.buf, ',')
+			}
+			atLeastOne = true
+			if !isStreamed(c.itemCodec) {
+				if tw.buf, err = genericMapTextEntryEncoder(tw.buf, key, value, c.itemCodec, tw.standard); err != nil {
+					return err
+				}
+				continue
+			}
+			if tw.buf, err = stringTextualFromNative(tw.buf, key); err != nil {
+				return err
+			}
+			tw.buf = append(tw.buf, ':')
+			if err = tw.write(c.itemCodec, value); err != nil {
+				return fmt.Errorf("cannot encode textual map: value for %q does not match its schema: %w", key, err)
+			}
+		}
+		tw.buf = append(tw.buf, '}')
+	case tw.standard:
+		if tw.buf, err = c.textualStandard(tw.buf, datum); err != nil {
+			return err
+		}
+	default:
+		if tw.buf, err = c.textualFromNative(tw.buf, datum); err != nil {
+			return err
+		}
+	}
+	if len(tw.buf) >= textualWriterFlushSize {
+		return tw.flush()
+	}
+	return nil
+}
+
+// isStreamed returns true when values of the codec are written by the
+// textualWriter item by item.
+func isStreamed(c *Codec) bool {
+	if c.annotated != nil {
+		return isStreamed(c.annotated)
+	}
+	return c.itemCodec != nil && (c.typeName.fullName == "array" || c.typeName.fullName == "map")
+}
+
+func (tw *textualWriter) flush() error {
+	if len(tw.buf) == 0 {
+		return nil
+	}
+	if _, err := tw.w.Write(tw.buf); err != nil {
+		return fmt.Errorf("cannot write textual datum: %w", err)
+	}
+	tw.buf = tw.buf[:0]
+	return nil
+}
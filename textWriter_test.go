@@ -0,0 +1,101 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func testTextualFromNativeWriter(t *testing.T, schema string, datum interface{}) {
+	t.Helper()
+	codec, err := NewCodec(schema)
+	ensureError(t, err)
+	expected, err := codec.TextualFromNative(nil, datum)
+	ensureError(t, err)
+
+	bb := new(bytes.Buffer)
+	ensureError(t, codec.TextualFromNativeWriter(bb, datum))
+	if actual := bb.Bytes(); !bytes.Equal(actual, expected) {
+		t.Errorf("GOT: %s; WANT: %s", actual, expected)
+	}
+}
+
+func TestTextualFromNativeWriter(t *testing.T) {
+	testTextualFromNativeWriter(t, `"string"`, "some string")
+	testTextualFromNativeWriter(t, `{"type":"array","items":"int"}`, []interface{}{})
+	testTextualFromNativeWriter(t, `{"type":"map","values":"int"}`, map[string]interface{}{})
+	testTextualFromNativeWriter(t, `{"type":"map","values":["null","string"]}`, map[string]interface{}{"a": Union("string", "x")})
+	testTextualFromNativeWriter(t, `{"type":"map","values":{"type":"array","items":"long"}}`, map[string]interface{}{"a": []int64{1, 2, 3}})
+	testTextualFromNativeWriter(t, `{"type":"array","items":{"type":"map","values":{"type":"array","items":"long"}}}`, []interface{}{
+		map[string]interface{}{"a": []interface{}{int64(1)}},
+		map[string]interface{}{},
+		map[string]interface{}{"b": []interface{}{}},
+	})
+
+	// enough records to be written in several pieces
+	records := make([]interface{}, 1000)
+	for i := range records {
+		records[i] = map[string]interface{}{
+			"id":   int64(i),
+			"name": Union("string", fmt.Sprintf("record %d", i)),
+		}
+	}
+	testTextualFromNativeWriter(t, `{"type":"array","items":{"type":"record","name":"r1","fields":[{"name":"id","type":"long"},{"name":"name","type":["null","string"]}]}}`, records)
+
+	// the datum of a registered logical type annotating an array or map is
+	// converted before it is written
+	RegisterLogicalType("test-writer-range", func(native interface{}) (interface{}, error) {
+		items := make([]interface{}, native.(int))
+		for i := range items {
+			items[i] = i
+		}
+		return items, nil
+	}, nil)
+	testTextualFromNativeWriter(t, `{"type":"array","items":"int","logicalType":"test-writer-range"}`, 3)
+	testTextualFromNativeWriter(t, `{"type":"map","values":{"type":"array","items":"int","logicalType":"test-writer-range"}}`, map[string]interface{}{"a": 2})
+}
+
+type failingWriter struct {
+	remaining int
+}
+
+func (fw *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > fw.remaining {
+		return 0, errors.New("writer full")
+	}
+	fw.remaining -= len(p)
+	return len(p), nil
+}
+
+func TestTextualFromNativeWriterFail(t *testing.T) {
+	codec, err := NewCodec(`{"type":"array","items":"long"}`)
+	ensureError(t, err)
+
+	err = codec.TextualFromNativeWriter(new(bytes.Buffer), []interface{}{int64(1), "two"})
+	ensureError(t, err, "cannot encode textual array item 2")
+
+	err = codec.TextualFromNativeWriter(new(bytes.Buffer), "not an array")
+	ensureError(t, err, "cannot encode textual array")
+
+	items := make([]interface{}, 10000)
+	for i := range items {
+		items[i] = int64(i)
+	}
+	err = codec.TextualFromNativeWriter(&failingWriter{remaining: textualWriterFlushSize}, items)
+	ensureError(t, err, "cannot write textual datum: writer full")
+
+	codec, err = NewCodec(`{"type":"map","values":{"type":"array","items":"long"}}`)
+	ensureError(t, err)
+	err = codec.TextualFromNativeWriter(new(bytes.Buffer), map[string]interface{}{"a": []interface{}{"one"}})
+	ensureError(t, err, `value for "a" does not match its schema`)
+}
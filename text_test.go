@@ -11,6 +11,7 @@ package goavro
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"math"
 	"reflect"
@@ -221,8 +222,6 @@ func toNativeAndCompare(t *testing.T, schema string, datum interface{}, encoded
 				decodedLookupKey = "long"
 			} else if key == "float64" {
 				decodedLookupKey = "float"
-			} else if key == "double" {
-				decodedLookupKey = "float64"
 			}
 
 			decodedValue, ok := decodedMap[decodedLookupKey]
@@ -239,7 +238,7 @@ func toNativeAndCompare(t *testing.T, schema string, datum interface{}, encoded
 				return
 			}
 	
-			if actual, expected := fmt.Sprintf("%v", decodedValue), fmt.Sprintf("%v", datumValue); actual != expected {
+			if actual, expected := fmt.Sprintf("%v", dereferenceUnionFields(decodedValue)), fmt.Sprintf("%v", datumValue); actual != expected {
 				t.Errorf("map comparison: values differ for key: %q; Actual: %v; Expected: %v", key, actual, expected)
 			}
 		}
@@ -268,6 +267,28 @@ func toNativeAndCompare(t *testing.T, schema string, datum interface{}, encoded
 	}
 }
 
+// dereferenceUnionFields returns value with the pointers textual decoders use
+// for union field values replaced by the values they point to.
+func dereferenceUnionFields(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *interface{}:
+		return dereferenceUnionFields(*v)
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = dereferenceUnionFields(item)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, item := range v {
+			s[i] = dereferenceUnionFields(item)
+		}
+		return s
+	}
+	return value
+}
+
 func testTextEncodePass(t *testing.T, schema string, datum interface{}, expected []byte) {
 	t.Helper()
 	codec, err := NewCodec(schema)
@@ -291,3 +312,58 @@ func testTextCodecPass(t *testing.T, schema string, datum interface{}, buf []byt
 	testTextDecodePass(t, schema, datum, buf)
 	testTextEncodePass(t, schema, datum, buf)
 }
+
+func ExampleCodec_TextualFromNativeIndent() {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"array","items":["null","int"]}}]}`)
+	if err != nil {
+		fmt.Println(err)
+	}
+	text, err := codec.TextualFromNativeIndent(nil, map[string]interface{}{"f1": []interface{}{nil, Union("int", 3)}}, "", "  ")
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("%s\n", text)
+	// Output:
+	// {
+	//   "f1": [
+	//     null,
+	//     {
+	//       "int": 3
+	//     }
+	//   ]
+	// }
+}
+
+func TestTextualFromNativeIndent(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"map","values":"double"}}]}`)
+	ensureError(t, err)
+
+	t.Run("prefix", func(t *testing.T) {
+		buf, err := codec.TextualFromNativeIndent([]byte("> "), map[string]interface{}{"f1": map[string]interface{}{"k": math.Inf(1)}}, "> ", "\t")
+		ensureError(t, err)
+		if actual, expected := string(buf), "> {\n> \t\"f1\": {\n> \t\t\"k\": 1e999\n> \t}\n> }"; actual != expected {
+			t.Errorf("GOT: %q; WANT: %q", actual, expected)
+		}
+	})
+
+	t.Run("same data as compact", func(t *testing.T) {
+		datum := map[string]interface{}{"f1": map[string]interface{}{"k": 3.5}}
+		indented, err := codec.TextualFromNativeIndent(nil, datum, "", "  ")
+		ensureError(t, err)
+		compact, err := codec.TextualFromNative(nil, datum)
+		ensureError(t, err)
+		var bb bytes.Buffer
+		ensureError(t, json.Compact(&bb, indented))
+		if actual, expected := bb.String(), string(compact); actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		buf, err := codec.TextualFromNativeIndent([]byte("prefix"), map[string]interface{}{"f1": 13}, "", "  ")
+		ensureError(t, err, "cannot encode textual")
+		if actual, expected := string(buf), "prefix"; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	})
+}
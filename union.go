@@ -11,15 +11,81 @@ package goavro
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
-	"sort"
-
-	"golang.org/x/exp/maps"
+	"time"
 )
 
+// Union wraps a datum value in a map for encoding as a Union, as required by
+// the Union encoder.
+//
+// When providing a value for an Avro union, the encoder will accept `nil` for
+// a `null` value. If the value is non-`nil`, it may be a
+// `map[string]interface{}` with a single key-value pair, where the key is the
+// Avro type name and the value is the datum's value. As a convenience, the
+// `Union` function wraps any datum value in a map as specified above. When
+// name is the empty string, Union returns a nil map, which every union encoder
+// rejects because it does not name a member schema type.
+//
+//     func ExampleUnion() {
+//        codec, err := goavro.NewCodec(`["null","string","int"]`)
+//        if err != nil {
+//            fmt.Println(err)
+//        }
+//        buf, err := codec.BinaryFromNative(nil, goavro.Union("int", 3))
+//        if err != nil {
+//            fmt.Println(err)
+//        }
+//        fmt.Printf("%#v", buf)
+//        // Output: []byte{0x4, 0x6}
+//     }
+func Union(name string, datum interface{}) map[string]interface{} {
+	if name == "" {
+		return nil
+	}
+	return map[string]interface{}{name: datum}
+}
+
+// UnionMembers returns the names of the member schema types of a union Codec,
+// in schema order, which are the names Union accepts for it. Named types are
+// listed by their full names. The second return value is false when the Codec
+// is not a union. The returned slice is a copy, which the caller may modify.
+func (c *Codec) UnionMembers() ([]string, bool) {
+	if c.unionInfo == nil {
+		return nil, false
+	}
+	return append([]string(nil), c.unionInfo.allowedTypes...), true
+}
+
+// Nullable returns the Codec of the non-null member of a union Codec having
+// exactly two members, one of which is null, in either order, which is how
+// Avro schemas declare optional values. The second return value is false for
+// any other Codec. The returned Codec is shared by the union, and encodes and
+// decodes values of the member without the union index.
+//
+//     codec, err := goavro.NewCodec(`["null","int"]`)
+//     if err != nil {
+//         fmt.Println(err)
+//     }
+//     if inner, ok := codec.Nullable(); ok {
+//         buf, _ := inner.BinaryFromNative(nil, 3)
+//         fmt.Printf("%#v", buf) // []byte{0x6}
+//     }
+func (c *Codec) Nullable() (*Codec, bool) {
+	if c.unionInfo == nil {
+		return nil, false
+	}
+	index, ok := c.unionInfo.nullableIndex()
+	if !ok {
+		return nil, false
+	}
+	return c.unionInfo.codecFromIndex[index], true
+}
+
 // codecInfo is a set of quick lookups it holds all the lookup info for the
 // all the schemas we need to handle the list of types for this union
 type codecInfo struct {
@@ -27,6 +93,20 @@ type codecInfo struct {
 	codecFromIndex []*Codec
 	codecFromName  map[string]*Codec
 	indexFromName  map[string]int
+
+	// resolver optionally selects the member used to encode a datum, as
+	// registered using WithUnionResolver.
+	resolver func(datum interface{}) (string, bool)
+
+	// The following are the orders in which members are tried when decoding
+	// standard JSON, by kind of JSON value. They are computed once when the
+	// codec is built, and never modified afterwards, so a codec may decode
+	// from many goroutines.
+	integralOrder []string // JSON numbers without a fractional part
+	fractionOrder []string // other JSON numbers
+	arrayOrder    []string
+	objectOrder   []string
+	stringOrder   []string
 }
 
 // makeCodecInfo takes the schema array
@@ -41,11 +121,19 @@ func makeCodecInfo(st map[string]*Codec, enclosingNamespace string, schemaArray
 	for i, unionMemberSchema := range schemaArray {
 		unionMemberCodec, err := buildCodec(st, enclosingNamespace, unionMemberSchema, cb)
 		if err != nil {
-			return codecInfo{}, fmt.Errorf("Union item %d ought to be valid Avro type: %s", i+1, err)
+			return codecInfo{}, fmt.Errorf("Union item %d ought to be valid Avro type: %w", i+1, err)
+		}
+		if unionMemberCodec.unionInfo != nil {
+			// NOTE: The Avro specification forbids unions from immediately
+			// containing other unions, whose members would be ambiguous.
+			return codecInfo{}, fmt.Errorf("Union item %d ought not to be a union: %v", i+1, unionMemberSchema)
 		}
+		// NOTE: Members of unnamed types are named by their type, so a union
+		// may hold only one of each, such as one array and one map, while
+		// it may hold any number of named types with different names.
 		fullName := unionMemberCodec.typeName.fullName
-		if _, ok := indexFromName[fullName]; ok {
-			return codecInfo{}, fmt.Errorf("Union item %d ought to be unique type: %s", i+1, unionMemberCodec.typeName)
+		if index, ok := indexFromName[fullName]; ok {
+			return codecInfo{}, fmt.Errorf("Union item %d ought to be unique type: %s; same type as item %d", i+1, unionMemberCodec.typeName, index+1)
 		}
 		allowedTypes[i] = fullName
 		codecFromIndex[i] = unionMemberCodec
@@ -58,21 +146,33 @@ func makeCodecInfo(st map[string]*Codec, enclosingNamespace string, schemaArray
 		codecFromIndex: codecFromIndex,
 		codecFromName:  codecFromName,
 		indexFromName:  indexFromName,
+		integralOrder:  numericMemberOrder(allowedTypes, true),
+		fractionOrder:  numericMemberOrder(allowedTypes, false),
+		arrayOrder:     compositeMemberOrder(allowedTypes, true),
+		objectOrder:    compositeMemberOrder(allowedTypes, false),
+		stringOrder:    stringMemberOrder(allowedTypes),
 	}, nil
 
 }
 
+// nullableIndex returns the index of the non-null member of a union that has
+// exactly two members, one of which is null, regardless of the order in which
+// the members were declared.
+func (cr *codecInfo) nullableIndex() (int, bool) {
+	if len(cr.allowedTypes) != 2 {
+		return 0, false
+	}
+	nullIndex, ok := cr.indexFromName["null"]
+	if !ok {
+		return 0, false
+	}
+	return 1 - nullIndex, true
+}
+
 func nativeFromBinary(cr *codecInfo) func(buf []byte) (interface{}, []byte, error) {
 
 	return func(buf []byte) (interface{}, []byte, error) {
-		var decoded interface{}
-		var err error
-
-		if len(cr.allowedTypes) != 2 {
-			return nil, nil, fmt.Errorf("only null and one other type allowed in union")
-		}
-
-		decoded, buf, err = longNativeFromBinary(buf)
+		decoded, remaining, err := longNativeFromBinary(buf)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -81,58 +181,315 @@ func nativeFromBinary(cr *codecInfo) func(buf []byte) (interface{}, []byte, erro
 			return nil, nil, fmt.Errorf("cannot decode binary union: index ought to be between 0 and %d; read index: %d", len(cr.codecFromIndex)-1, index)
 		}
 		c := cr.codecFromIndex[index]
-		if len(buf) == 0 {
-			//nil pointer?
-			return nil, buf, nil
+		if cr.allowedTypes[index] == "null" {
+			return nil, remaining, nil
 		}
 
-		decoded, buf, err = c.nativeFromBinary(buf)
+		decoded, newBuf, err := c.nativeFromBinary(remaining)
 		if err != nil {
-			return nil, nil, fmt.Errorf("cannot decode binary union item %d: %s", index+1, err)
+			return nil, nil, newDecodeError(err, fmt.Errorf("cannot decode binary union item %d: %w", index+1, err), "", len(buf)-len(remaining))
 		}
-		if decoded == nil {
-			return nil, buf, nil
+		return unionNativeFromMember(cr, int(index), decoded), newBuf, nil
+	}
+}
+
+// unionNativeFromMember returns the native form of a decoded value of the union
+// member at index.
+func unionNativeFromMember(cr *codecInfo, index int, decoded interface{}) interface{} {
+	if cr.allowedTypes[index] == "null" {
+		return nil
+	}
+	if _, ok := cr.nullableIndex(); !ok {
+		// Unions other than a null and one other type are returned using
+		// the same single key map form used to encode them, so the caller
+		// knows which member was decoded.
+		return map[string]interface{}{cr.allowedTypes[index]: decoded}
+	}
+	if decoded == nil {
+		return nil
+	}
+	// Single value union values are returned as a pointer type
+	// the member codec did not return a pointer type. The interface holds
+	// a concrete type. We now need to get a pointer to the value held by the interface
+
+	// create a new pointer to the concrete type
+	ptrTyp := reflect.New(reflect.TypeOf(decoded))
+	ptrTyp.Elem().Set(reflect.ValueOf(decoded))
+	return ptrTyp.Interface()
+}
+
+// unionIndexFromMap returns the index of the union member named by the only
+// key of datum, provided datum is a map with a single key, and that key is the
+// name of one of the union members.
+func unionIndexFromMap(cr *codecInfo, datum interface{}) (int, interface{}, bool) {
+	m, ok := datum.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return 0, nil, false
+	}
+	for key, value := range m {
+		if index, ok := cr.indexFromName[key]; ok {
+			return index, value, true
 		}
-		// Single value union values are returned as a pointer type
-		// the above c.nativeFromBinary did not return a pointer type. The interface holds
-		// a concrete type. We now need to get a pointer to the value held by the interface
+	}
+	return 0, nil, false
+}
 
-		// create a new pointer to the concrete type
-		ptrTyp := reflect.New(reflect.TypeOf(decoded))
-		ptrTyp.Elem().Set(reflect.ValueOf(decoded))
-		return ptrTyp.Interface(), buf, nil
+// unionMemberNamesFromValue lists, in order of preference, the names of the
+// primitive union members able to encode a scalar datum passed by value rather
+// than by pointer. Times and durations are only encoded by the members of the
+// logical types representing them, never by the long or int they annotate.
+func unionMemberNamesFromValue(datum interface{}) []string {
+	switch datum.(type) {
+	case time.Time:
+		return []string{"long.timestamp-micros", "long.timestamp-millis", "int.date"}
+	case time.Duration:
+		return []string{"long.time-micros", "int.time-millis"}
+	case bool:
+		return []string{"boolean"}
+	case string:
+		return []string{"string"}
+	case []byte:
+		return []string{"bytes"}
+	case int8, int16, int32, uint8, uint16:
+		return []string{"int", "long"}
+	case int, int64, uint, uint32, uint64:
+		return []string{"long", "int"}
+	case float32:
+		return []string{"float", "double"}
+	case float64:
+		return []string{"double", "float"}
 	}
+	return nil
 }
-func binaryFromNative(cr *codecInfo) func(buf []byte, datum interface{}) ([]byte, error) {
-	return func(buf []byte, datum interface{}) ([]byte, error) {
 
-		switch v := datum.(type) {
-		case nil:
-			index, ok := cr.indexFromName["null"]
-			if !ok {
-				return nil, fmt.Errorf("cannot encode binary union: no member schema types support datum: allowed types: %v; received: %T", cr.allowedTypes, datum)
-			}
-			return longBinaryFromNative(buf, index)
-		default:
-			rVal := reflect.ValueOf(v)
-			if rVal.Kind() != reflect.Ptr {
-				return nil, fmt.Errorf("cannot encode binary union: unions must be passed as a single pointer type")
-			}
-			if rVal.IsNil() || v == nil {
-				index, ok := cr.indexFromName["null"]
-				if !ok {
-					return nil, fmt.Errorf("cannot encode binary union: no member schema types support datum: allowed types: %v; received: %T", cr.allowedTypes, datum)
-				}
-				return longBinaryFromNative(buf, index)
-			}
+var (
+	wholeNumberMemberNames      = []string{"long", "int", "double", "float"}
+	fractionalNumberMemberNames = []string{"double", "float"}
+)
+
+// unionMemberNamesFromNumber returns the member names to try for a json.Number,
+// preferring an integer member when n is whole.
+func unionMemberNamesFromNumber(n json.Number) []string {
+	if _, err := int64FromNumber(n); err == nil {
+		return wholeNumberMemberNames
+	}
+	return fractionalNumberMemberNames
+}
+
+// unionIndexFromValue returns the index of the union member to encode a scalar
+// datum passed by value rather than by pointer, based on its Go type.
+func unionIndexFromValue(cr *codecInfo, datum interface{}) (int, bool) {
+	names := unionMemberNamesFromValue(datum)
+	if n, ok := datum.(json.Number); ok {
+		names = unionMemberNamesFromNumber(n)
+	}
+	for _, name := range names {
+		if index, ok := cr.indexFromName[name]; ok {
+			return index, true
+		}
+	}
+	if someBytes, ok := datum.([]byte); ok {
+		return unionIndexFromFixedSize(cr, uint(len(someBytes)))
+	}
+	if _, ok := datum.(map[string]interface{}); ok {
+		return unionIndexFromBareMap(cr)
+	}
+	return 0, false
+}
 
-			c := cr.codecFromIndex[1]
-			buf, _ = longBinaryFromNative(buf, 1)
+// unionIndexFromBareMap returns the index of the union member to encode a map
+// which does not name a member schema type, which is the bare value of the other
+// member of a nullable union, or failing that, the value of the map member,
+// even when it has a single key.
+func unionIndexFromBareMap(cr *codecInfo) (int, bool) {
+	if index, ok := cr.nullableIndex(); ok {
+		return index, true
+	}
+	index, ok := cr.indexFromName["map"]
+	return index, ok
+}
 
-			return c.binaryFromNative(buf, rVal.Elem().Interface())
+// unionIndexFromFixedSize returns the index of the fixed union member whose size
+// equals size, or when there is none, the index of the first fixed member, so
+// its encoder reports the size mismatch.
+func unionIndexFromFixedSize(cr *codecInfo, size uint) (int, bool) {
+	first, found := 0, false
+	for index, c := range cr.codecFromIndex {
+		if c.schemaType != "fixed" {
+			continue
+		}
+		if c.fixedSize == size {
+			return index, true
+		}
+		if !found {
+			first, found = index, true
 		}
 	}
+	return first, found
+}
+
+// unionValueFromPointer returns the value rVal points to, or nil when rVal is a
+// nil pointer, or points to a nil interface.
+func unionValueFromPointer(rVal reflect.Value) interface{} {
+	if rVal.IsNil() {
+		return nil
+	}
+	return rVal.Elem().Interface()
 }
+
+// unionMemberFromNative returns the index of the union member which encodes
+// datum, along with the value that member encodes, or an error when datum
+// selects no member.
+func unionMemberFromNative(cr *codecInfo, datum interface{}) (int, interface{}, error) {
+	if index, ok, err := unionIndexFromResolver(cr, datum); err != nil {
+		return 0, nil, err
+	} else if ok {
+		return index, datum, nil
+	}
+	switch v := datum.(type) {
+	case nil:
+		index, ok := cr.indexFromName["null"]
+		if !ok {
+			return 0, nil, newErrUnionNoMatch(cr.allowedTypes, datum)
+		}
+		return index, nil, nil
+	case map[string]interface{}:
+		if index, value, ok := unionIndexFromMap(cr, v); ok {
+			return index, value, nil
+		}
+		index, ok := unionIndexFromBareMap(cr)
+		if !ok {
+			return 0, nil, fmt.Errorf("map ought to have a single key naming a member schema type: allowed types: %v; received: %v", cr.allowedTypes, datum)
+		}
+		return index, v, nil
+	}
+	rVal := reflect.ValueOf(datum)
+	if rVal.Kind() != reflect.Ptr {
+		index, ok := unionIndexFromValue(cr, datum)
+		if !ok {
+			index, ok = cr.nullableIndex()
+		}
+		if !ok {
+			return 0, nil, fmt.Errorf("unions must be passed as a single pointer type: allowed types: %v; received: %T", cr.allowedTypes, datum)
+		}
+		return index, datum, nil
+	}
+	// NOTE: Textual decoders return union field values as a pointer to their
+	// value, which may be a nil interface.
+	value := unionValueFromPointer(rVal)
+	if value == nil {
+		index, ok := cr.indexFromName["null"]
+		if !ok {
+			return 0, nil, newErrUnionNoMatch(cr.allowedTypes, datum)
+		}
+		return index, nil, nil
+	}
+	// NOTE: A pointer to a single key map naming a member schema type selects
+	// that member.
+	if index, value, ok := unionIndexFromMap(cr, value); ok {
+		return index, value, nil
+	}
+	index, ok := cr.nullableIndex()
+	if !ok {
+		return 0, nil, fmt.Errorf("unions other than null and one other type must be passed as a single key map: allowed types: %v; received: %T", cr.allowedTypes, datum)
+	}
+	return index, value, nil
+}
+
+// unionIndexFromResolver returns the index of the union member selected for
+// datum by the resolver of the union, if it has one and it selects a member.
+func unionIndexFromResolver(cr *codecInfo, datum interface{}) (int, bool, error) {
+	if cr.resolver == nil {
+		return 0, false, nil
+	}
+	branchName, ok := cr.resolver(datum)
+	if !ok {
+		return 0, false, nil
+	}
+	index, ok := cr.indexFromName[branchName]
+	if !ok {
+		return 0, false, fmt.Errorf("union resolver ought to return name of member schema type: allowed types: %v; received: %q", cr.allowedTypes, branchName)
+	}
+	return index, true, nil
+}
+
+// binaryFromScalarPointer encodes datum as binary of a nullable union, whose
+// other member is at index, when datum is a pointer to a Go scalar of the type
+// that member decodes to, or a nil pointer to any of those types. It returns
+// false for any other datum, which the general case of the union encoder
+// encodes using reflection, copying the value it points to onto the heap.
+func binaryFromScalarPointer(cr *codecInfo, index int, buf []byte, datum interface{}) ([]byte, bool) {
+	var isNil bool
+	member := cr.codecFromIndex[index].typeName.fullName
+	switch v := datum.(type) {
+	case *string:
+		if isNil = v == nil; !isNil && member == "string" {
+			buf = appendLong(appendLong(buf, int64(index)), int64(len(*v)))
+			return append(buf, *v...), true
+		}
+	case *[]byte:
+		if isNil = v == nil; !isNil && member == "bytes" {
+			buf = appendLong(appendLong(buf, int64(index)), int64(len(*v)))
+			return append(buf, *v...), true
+		}
+	case *bool:
+		if isNil = v == nil; !isNil && member == "boolean" {
+			var b byte
+			if *v {
+				b = 1
+			}
+			return append(appendLong(buf, int64(index)), b), true
+		}
+	case *int32:
+		if isNil = v == nil; !isNil && (member == "int" || member == "long") {
+			return appendLong(appendLong(buf, int64(index)), int64(*v)), true
+		}
+	case *int64:
+		if isNil = v == nil; !isNil && member == "long" {
+			return appendLong(appendLong(buf, int64(index)), *v), true
+		}
+	case *int:
+		if isNil = v == nil; !isNil && member == "long" {
+			return appendLong(appendLong(buf, int64(index)), int64(*v)), true
+		}
+	case *float32:
+		if isNil = v == nil; !isNil && member == "float" {
+			buf = append(appendLong(buf, int64(index)), 0, 0, 0, 0)
+			binary.LittleEndian.PutUint32(buf[len(buf)-floatEncodedLength:], math.Float32bits(*v))
+			return buf, true
+		}
+	case *float64:
+		if isNil = v == nil; !isNil && member == "double" {
+			buf = append(appendLong(buf, int64(index)), 0, 0, 0, 0, 0, 0, 0, 0)
+			binary.LittleEndian.PutUint64(buf[len(buf)-doubleEncodedLength:], math.Float64bits(*v))
+			return buf, true
+		}
+	default:
+		return nil, false
+	}
+	if isNil {
+		// NOTE: The null member is the other one.
+		return appendLong(buf, int64(1-index)), true
+	}
+	return nil, false
+}
+
+func binaryFromNative(cr *codecInfo) func(buf []byte, datum interface{}) ([]byte, error) {
+	return func(buf []byte, datum interface{}) ([]byte, error) {
+		if index, ok := cr.nullableIndex(); ok && cr.resolver == nil {
+			if newBuf, ok := binaryFromScalarPointer(cr, index, buf, datum); ok {
+				return newBuf, nil
+			}
+		}
+		index, value, err := unionMemberFromNative(cr, datum)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode binary union: %w", err)
+		}
+		buf, _ = longBinaryFromNative(buf, index)
+		return cr.codecFromIndex[index].binaryFromNative(buf, value)
+	}
+}
+
 func nativeFromTextual(cr *codecInfo) func(buf []byte) (interface{}, []byte, error) {
 	return func(buf []byte) (interface{}, []byte, error) {
 		if len(buf) >= 4 && bytes.Equal(buf[:4], []byte("null")) {
@@ -145,57 +502,155 @@ func nativeFromTextual(cr *codecInfo) func(buf []byte) (interface{}, []byte, err
 		var err error
 		datum, buf, err = genericMapTextDecoder(buf, nil, cr.codecFromName)
 		if err != nil {
-			return nil, nil, fmt.Errorf("cannot decode textual union: %s", err)
+			return nil, nil, fmt.Errorf("cannot decode textual union: %w", err)
 		}
 
 		return datum, buf, nil
 	}
 }
+// unionTextualFromMember encodes value as textual Avro data of the union member
+// at index, which is a bare null for the null member, and otherwise a single key
+// object naming the member.
+func unionTextualFromMember(cr *codecInfo, buf []byte, index int, value interface{}) ([]byte, error) {
+	c := cr.codecFromIndex[index]
+	var err error
+	if c.typeName.fullName == "null" {
+		if buf, err = c.textualFromNative(buf, value); err != nil {
+			return nil, fmt.Errorf("cannot encode textual union: %w", err)
+		}
+		return buf, nil
+	}
+	buf = append(buf, '{')
+	if buf, err = stringTextualFromNative(buf, cr.allowedTypes[index]); err != nil {
+		return nil, fmt.Errorf("cannot encode textual union: %w", err)
+	}
+	buf = append(buf, ':')
+	if buf, err = c.textualFromNative(buf, value); err != nil {
+		return nil, fmt.Errorf("cannot encode textual union: %w", err)
+	}
+	return append(buf, '}'), nil
+}
+
 func textualFromNative(cr *codecInfo) func(buf []byte, datum interface{}) ([]byte, error) {
 	return func(buf []byte, datum interface{}) ([]byte, error) {
+		if index, ok, err := unionIndexFromResolver(cr, datum); err != nil {
+			return nil, fmt.Errorf("cannot encode textual union: %w", err)
+		} else if ok {
+			return unionTextualFromMember(cr, buf, index, datum)
+		}
+
 		switch v := datum.(type) {
 		case nil:
 			_, ok := cr.indexFromName["null"]
 			if !ok {
-				return nil, fmt.Errorf("cannot encode textual union: no member schema types support datum: allowed types: %v; received: %T", cr.allowedTypes, datum)
+				return nil, fmt.Errorf("cannot encode textual union: %w", newErrUnionNoMatch(cr.allowedTypes, datum))
 			}
 			return append(buf, "null"...), nil
+		case map[string]interface{}:
+			index, value, ok := unionIndexFromMap(cr, v)
+			if !ok {
+				if index, ok = unionIndexFromBareMap(cr); !ok {
+					return nil, fmt.Errorf("cannot encode textual union: map ought to have a single key naming a member schema type: allowed types: %v; received: %v", cr.allowedTypes, datum)
+				}
+				value = v
+			}
+			return unionTextualFromMember(cr, buf, index, value)
 		default:
 			rVal := reflect.ValueOf(v)
 			if rVal.Kind() != reflect.Ptr {
-				return nil, fmt.Errorf("cannot encode textual union: unions must be passed as a single pointer type")
+				index, ok := unionIndexFromValue(cr, v)
+				if !ok {
+					index, ok = cr.nullableIndex()
+				}
+				if !ok {
+					return nil, fmt.Errorf("cannot encode textual union: unions must be passed as a single pointer type: allowed types: %v; received: %T", cr.allowedTypes, datum)
+				}
+				return unionTextualFromMember(cr, buf, index, v)
 			}
-			if rVal.IsNil() || v == nil {
+			// NOTE: Textual decoders return union field values as a
+			// pointer to their value, which may be a nil interface.
+			value := unionValueFromPointer(rVal)
+			if value == nil {
 				_, ok := cr.indexFromName["null"]
 				if !ok {
-					return nil, fmt.Errorf("cannot encode textual union: no member schema types support datum: allowed types: %v; received: %T", cr.allowedTypes, datum)
+					return nil, fmt.Errorf("cannot encode textual union: %w", newErrUnionNoMatch(cr.allowedTypes, datum))
 				}
 				return append(buf, "null"...), nil
 			}
+			// NOTE: A pointer to a single key map naming a member schema
+			// type selects that member.
+			if index, value, ok := unionIndexFromMap(cr, value); ok {
+				return unionTextualFromMember(cr, buf, index, value)
+			}
+			index, ok := cr.nullableIndex()
+			if !ok {
+				return nil, fmt.Errorf("cannot encode textual union: unions other than null and one other type are not supported: allowed types: %v; received: %T", cr.allowedTypes, datum)
+			}
+			return unionTextualFromMember(cr, buf, index, value)
+		}
+	}
+}
 
-			buf = append(buf, '{')
-			var err error
-			buf, err = stringTextualFromNative(buf, maps.Keys(cr.indexFromName)[1])
+// textualStandardFromNative encodes a union value as standard JSON, emitting the
+// bare value of the selected member rather than wrapping it in a single key
+// object naming the member type.
+func textualStandardFromNative(cr *codecInfo) func(buf []byte, datum interface{}) ([]byte, error) {
+	return func(buf []byte, datum interface{}) ([]byte, error) {
+		if index, ok, err := unionIndexFromResolver(cr, datum); err != nil {
+			return nil, fmt.Errorf("cannot encode textual union: %w", err)
+		} else if ok {
+			buf, err = cr.codecFromIndex[index].textualStandard(buf, datum)
 			if err != nil {
-				return nil, fmt.Errorf("cannot encode textual union: %s", err)
+				return nil, fmt.Errorf("cannot encode textual union: %w", err)
 			}
-			buf = append(buf, ':')
-			c := cr.codecFromIndex[1]
-			buf, err = c.textualFromNative(buf, rVal.Elem().Interface())
-			if err != nil {
-				return nil, fmt.Errorf("cannot encode textual union: %s", err)
+			return buf, nil
+		}
+
+		if datum == nil {
+			if _, ok := cr.indexFromName["null"]; !ok {
+				return nil, fmt.Errorf("cannot encode textual union: %w", newErrUnionNoMatch(cr.allowedTypes, datum))
+			}
+			return append(buf, "null"...), nil
+		}
+
+		index, value, ok := unionIndexFromMap(cr, datum)
+		if !ok {
+			rVal := reflect.ValueOf(datum)
+			switch {
+			case rVal.Kind() != reflect.Ptr:
+				if index, ok = unionIndexFromValue(cr, datum); !ok {
+					index, ok = cr.nullableIndex()
+				}
+				if !ok {
+					return nil, fmt.Errorf("cannot encode textual union: map ought to have a single key naming a member schema type: allowed types: %v; received: %v", cr.allowedTypes, datum)
+				}
+				value = datum
+			default:
+				if value = unionValueFromPointer(rVal); value == nil {
+					if _, ok := cr.indexFromName["null"]; !ok {
+						return nil, fmt.Errorf("cannot encode textual union: %w", newErrUnionNoMatch(cr.allowedTypes, datum))
+					}
+					return append(buf, "null"...), nil
+				}
+				if i, v, ok := unionIndexFromMap(cr, value); ok {
+					index, value = i, v
+				} else if index, ok = cr.nullableIndex(); !ok {
+					return nil, fmt.Errorf("cannot encode textual union: unions other than null and one other type must be passed as a single key map: allowed types: %v; received: %T", cr.allowedTypes, datum)
+				}
 			}
-			return append(buf, '}'), nil
 		}
+
+		buf, err := cr.codecFromIndex[index].textualStandard(buf, value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode textual union: %w", err)
+		}
+		return buf, nil
 	}
 }
-func buildCodecForTypeDescribedBySlice(st map[string]*Codec, enclosingNamespace string, schemaArray []interface{}, cb *codecBuilder) (*Codec, error) {
-	if len(schemaArray) != 2 {
-		return nil, errors.New("this compiler only supports unions with exactly two members")
-	}
 
-	if schemaArray[0] != "null" {
-		return nil, errors.New("this compiler only supports unions with null as the default")
+func buildCodecForTypeDescribedBySlice(st map[string]*Codec, enclosingNamespace string, schemaArray []interface{}, cb *codecBuilder) (*Codec, error) {
+	if len(schemaArray) == 0 {
+		return nil, errors.New("Union ought to have one or more members")
 	}
 
 	cr, err := makeCodecInfo(st, enclosingNamespace, schemaArray, cb)
@@ -205,15 +660,20 @@ func buildCodecForTypeDescribedBySlice(st map[string]*Codec, enclosingNamespace
 
 	rv := &Codec{
 		// NOTE: To support record field default values, union schema set to the
-		// type name of first member
-		// TODO: add/change to schemaCanonical below
+		// type name of first member, because the Avro specification requires
+		// a union default value to correspond to its first member, wherever
+		// null happens to be declared.
 		schemaOriginal: cr.codecFromIndex[0].typeName.fullName,
 
 		typeName:          &name{"union", nullNamespace},
+		unionInfo:         &cr,
 		nativeFromBinary:  nativeFromBinary(&cr),
 		binaryFromNative:  binaryFromNative(&cr),
 		nativeFromTextual: nativeFromTextual(&cr),
 		textualFromNative: textualFromNative(&cr),
+
+		textualStandardFromNative: textualStandardFromNative(&cr),
+		nativeFromTextualStandard: nativeAvroFromTextualJson(&cr),
 	}
 	return rv, nil
 }
@@ -241,8 +701,8 @@ func buildCodecForTypeDescribedBySlice(st map[string]*Codec, enclosingNamespace
 //
 // the json is morphed on the read side
 // and then it will remain avro-json object
-// avro data is not serialized back into standard json
-// the data goes to avro-json and stays that way
+// and on the write side TextualFromNative unwraps it again, emitting the
+// bare value of the union member, so standard json round trips
 func buildCodecForTypeDescribedBySliceJSON(st map[string]*Codec, enclosingNamespace string, schemaArray []interface{}, cb *codecBuilder) (*Codec, error) {
 	if len(schemaArray) == 0 {
 		return nil, errors.New("Union ought to have one or more members")
@@ -256,20 +716,147 @@ func buildCodecForTypeDescribedBySliceJSON(st map[string]*Codec, enclosingNamesp
 	rv := &Codec{
 		// NOTE: To support record field default values, union schema set to the
 		// type name of first member
-		// TODO: add/change to schemaCanonical below
 		schemaOriginal: cr.codecFromIndex[0].typeName.fullName,
 
 		typeName:          &name{"union", nullNamespace},
+		unionInfo:         &cr,
 		nativeFromBinary:  nativeFromBinary(&cr),
 		binaryFromNative:  binaryFromNative(&cr),
 		nativeFromTextual: nativeAvroFromTextualJson(&cr),
-		textualFromNative: textualFromNative(&cr),
+		textualFromNative: textualStandardFromNative(&cr),
+
+		textualStandardFromNative: textualStandardFromNative(&cr),
+		nativeFromTextualStandard: nativeAvroFromTextualJson(&cr),
 	}
 	return rv, nil
 }
 
-func checkAll(allowedTypes []string, cr *codecInfo, buf []byte) (interface{}, []byte, error) {
-	for _, name := range cr.allowedTypes {
+// numericMemberOrder returns a copy of allowedTypes with the numeric member
+// types moved to the front, in the order they ought to be tried when decoding
+// a JSON number, leaving the relative order of the remaining members intact.
+func numericMemberOrder(allowedTypes []string, integral bool) []string {
+	preferred := []string{"double", "float", "int", "long"}
+	if integral {
+		preferred = []string{"int", "long", "double", "float"}
+	}
+	ordered := make([]string, 0, len(allowedTypes))
+	for _, name := range preferred {
+		for _, allowed := range allowedTypes {
+			if allowed == name {
+				ordered = append(ordered, name)
+			}
+		}
+	}
+	for _, allowed := range allowedTypes {
+		switch allowed {
+		case "double", "float", "int", "long":
+		default:
+			ordered = append(ordered, allowed)
+		}
+	}
+	return ordered
+}
+
+// compositeMemberOrder returns the members of allowedTypes which might decode a
+// JSON array when array is true, or a JSON object otherwise. An array member is
+// the only candidate for a JSON array. For a JSON object, the named members,
+// which include any records, are tried in their declared order, followed by
+// the map member, which accepts any object whose values it can decode.
+func compositeMemberOrder(allowedTypes []string, array bool) []string {
+	var ordered []string
+	if array {
+		for _, allowed := range allowedTypes {
+			if allowed == "array" {
+				ordered = append(ordered, allowed)
+			}
+		}
+		return ordered
+	}
+	for _, allowed := range allowedTypes {
+		switch allowed {
+		case "array", "map", "null":
+		default:
+			ordered = append(ordered, allowed)
+		}
+	}
+	for _, allowed := range allowedTypes {
+		if allowed == "map" {
+			ordered = append(ordered, allowed)
+		}
+	}
+	return ordered
+}
+
+// stringMemberOrder returns the members of allowedTypes in the order they are
+// tried when decoding a JSON string. The float and double members, which only
+// decode the strings "NaN", "Infinity", and "-Infinity", are tried after every
+// other member, so such a string is decoded by a string member when the union
+// has one, whatever the order in which the members were declared.
+func stringMemberOrder(allowedTypes []string) []string {
+	ordered := make([]string, 0, len(allowedTypes))
+	for _, allowed := range allowedTypes {
+		switch allowed {
+		case "double", "float", "null":
+		default:
+			ordered = append(ordered, allowed)
+		}
+	}
+	for _, allowed := range allowedTypes {
+		if allowed == "double" || allowed == "float" {
+			ordered = append(ordered, allowed)
+		}
+	}
+	return ordered
+}
+
+// logicalNativeFromString parses s, the value of a JSON string, as the native
+// form of the timestamp, date, or time logical type of c. The parsed value is
+// encoded and decoded using c, so it has the same native form and range as a
+// decoded value. It returns false when c is not one of those logical types, or
+// s cannot be parsed as one.
+func logicalNativeFromString(c *Codec, s string) (interface{}, bool) {
+	var value interface{}
+	switch c.typeName.fullName {
+	case "long.timestamp-millis", "long.timestamp-micros":
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, false
+		}
+		value = t
+	case "int.date":
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, false
+		}
+		value = t
+	case "int.time-millis", "long.time-micros":
+		t, err := time.Parse("15:04:05.999999999", s)
+		if err != nil {
+			return nil, false
+		}
+		value = t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()))
+	default:
+		return nil, false
+	}
+	buf, err := c.binaryFromNative(nil, value)
+	if err != nil {
+		return nil, false
+	}
+	value, _, err = c.nativeFromBinary(buf)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// checkAll tries to decode buf using each member of allowedTypes in turn,
+// where valueLength is the number of bytes of buf occupied by the JSON value.
+// It returns the first member which consumes exactly the entire value, so a
+// member which decodes only a prefix of the value, such as an int member
+// reading `3e2`, does not win over a later member which consumes all of it.
+// The returned buffer therefore always starts immediately after the value.
+func checkAll(allowedTypes []string, cr *codecInfo, buf []byte, valueLength int) (interface{}, []byte, error) {
+	for _, name := range allowedTypes {
 		if name == "null" {
 			// skip null since we know we already got type float64
 			continue
@@ -278,19 +865,26 @@ func checkAll(allowedTypes []string, cr *codecInfo, buf []byte) (interface{}, []
 		if !ok {
 			continue
 		}
-		rv, rb, err := theCodec.NativeFromTextual(buf)
-		if err != nil {
+		rv, rb, err := theCodec.nativeStandard(buf)
+		if err != nil || len(buf)-len(rb) != valueLength {
 			continue
 		}
 		return map[string]interface{}{name: rv}, rb, nil
 	}
-	return nil, buf, fmt.Errorf("could not decode any json data in input %v", string(buf))
+	return nil, buf, fmt.Errorf("could not decode any json data in input %v", string(buf[:valueLength]))
 }
 func nativeAvroFromTextualJson(cr *codecInfo) func(buf []byte) (interface{}, []byte, error) {
 	return func(buf []byte) (interface{}, []byte, error) {
+		// NOTE: Skip leading whitespace so the decoder's input offset below is
+		// also the length of the JSON value at the front of buf.
+		buf, err := advanceToNonWhitespace(buf)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot decode textual union: %w", err)
+		}
 
 		reader := bytes.NewReader(buf)
 		dec := json.NewDecoder(reader)
+		dec.UseNumber() // NOTE: member codecs decode numbers from buf itself
 		var m interface{}
 
 		// i should be able to grab the next json "value" with decoder.Decode()
@@ -311,50 +905,82 @@ func nativeAvroFromTextualJson(cr *codecInfo) func(buf []byte) (interface{}, []b
 		// so right here, grab whatever this object is
 		// grab the object specified as the value
 		// and try to figure out what it is and handle it
-		err := dec.Decode(&m)
+		err = dec.Decode(&m)
 		if err != nil {
 			return nil, buf, err
 		}
 
+		// the decoder reads ahead, so only its input offset tells where the
+		// union value ends and the remainder of a larger document begins
+		valueLength := int(dec.InputOffset())
+
 		allowedTypes := cr.allowedTypes
 
-		switch m.(type) {
+		switch v := m.(type) {
 		case nil:
 			if len(buf) >= 4 && bytes.Equal(buf[:4], []byte("null")) {
 				if _, ok := cr.codecFromName["null"]; ok {
-					return nil, buf[4:], nil
+					return nil, buf[valueLength:], nil
 				}
 			}
-		case float64:
-			// dec.Decode turns them all into float64
-			// avro spec knows about int, long (variable length zig-zag)
-			// and then float and double (32 bits, 64 bits)
+		case json.Number:
+			// dec.Decode leaves numbers as their text, while the avro spec
+			// knows about int and long (variable length zig-zag) and then
+			// float and double (32 bits, 64 bits)
 			// https://avro.apache.org/docs/current/spec.html#binary_encode_primitive
 			//
+			// integral numbers prefer int, then long, so they are not widened
+			// into a floating point member, and other numbers prefer double,
+			// then float, so they do not lose precision. Only the order is
+			// chosen here; checkAll has each member decode the original text,
+			// so a long beyond 2^53 is never rounded through a float64.
+			if _, err := v.Int64(); err == nil {
+				allowedTypes = cr.integralOrder
+			} else if f, err := v.Float64(); err == nil && f == math.Trunc(f) {
+				allowedTypes = cr.integralOrder
+			} else {
+				allowedTypes = cr.fractionOrder
+			}
 
-			// double
-			// doubleNativeFromTextual
-			// float
-			// floatNativeFromTextual
-			// long
-			// longNativeFromTextual
-			// int
-			// intNativeFromTextual
+		case bool:
+			// only a boolean member can decode a JSON true or false
+			if _, ok := cr.codecFromName["boolean"]; ok {
+				return map[string]interface{}{"boolean": v}, buf[valueLength:], nil
+			}
+			return nil, buf, fmt.Errorf("could not decode any json data in input %v", string(buf[:valueLength]))
 
-			// sorted so it would be
-			// double, float, int, long
-			// that makes the priorities right by chance
-			sort.Strings(cr.allowedTypes)
+		case []interface{}:
+			// only an array member can decode a JSON array
+			allowedTypes = cr.arrayOrder
 
 		case map[string]interface{}:
 
-			// try to decode it as a map
-			// because a map should fail faster than a record
-			// if that fails assume record and return it
-			sort.Strings(cr.allowedTypes)
+			// try to decode it as a record first
+			// because a record is more specific than a map
+			// if no record fits assume map and return it
+			allowedTypes = cr.objectOrder
+
+		case string:
+			// members which decode a JSON string, such as a string member, are
+			// preferred, and only then are logical types whose standard JSON
+			// form is a formatted string, such as an RFC3339 timestamp, tried.
+			// Such members are tried in schema order, so the first of a bytes
+			// and a string member decodes a string both are able to decode,
+			// except for float and double members, which are tried last.
+			allowedTypes = cr.stringOrder
+			datum, rb, err := checkAll(allowedTypes, cr, buf, valueLength)
+			if err == nil {
+				return datum, rb, nil
+			}
+			for _, name := range allowedTypes {
+				if value, ok := logicalNativeFromString(cr.codecFromName[name], v); ok {
+					return map[string]interface{}{name: value}, buf[valueLength:], nil
+				}
+			}
+			return nil, buf, err
 		}
 
-		return checkAll(allowedTypes, cr, buf)
+		return checkAll(allowedTypes, cr, buf, valueLength)
 
 	}
 }
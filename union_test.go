@@ -10,9 +10,13 @@
 package goavro
 
 import (
+	"bytes"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 type colors struct {
@@ -57,6 +61,38 @@ func TestUnionRejectInvalidType(t *testing.T) {
 	testBinaryEncodeFail(t, `["null","int"]`, &floatPtr, "cannot encode binary int: provided Go float64 would lose precision: 3.500000")
 }
 
+func TestUnionRejectStruct(t *testing.T) {
+	type someStruct struct{ Name string }
+	datum := someStruct{Name: "some name"}
+	testBinaryEncodeFail(t, `["null","string","int"]`, datum, "cannot encode binary union: unions must be passed as a single pointer type: allowed types: [null string int]; received: goavro.someStruct")
+	testTextEncodeFail(t, `["null","string","int"]`, datum, "cannot encode textual union: unions must be passed as a single pointer type: allowed types: [null string int]; received: goavro.someStruct")
+
+	// the other member of a nullable union rejects it
+	testBinaryEncodeFail(t, `["null","string"]`, datum, "cannot encode binary string: expected: []byte or string; received: goavro.someStruct")
+}
+
+func TestUnionRejectNestedUnion(t *testing.T) {
+	testSchemaInvalid(t, `["null",["int","string"]]`, "Union item 2 ought not to be a union: [int string]")
+	testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":[["null","int"]]}]}`, "Union item 1 ought not to be a union: [null int]")
+
+	// a union may contain an array or map of unions
+	testSchemaValid(t, `["null",{"type":"array","items":["int","string"]}]`)
+	_, err := NewCodecForStandardJSON(`["null",["int","string"]]`)
+	ensureError(t, err, "Union item 2 ought not to be a union")
+}
+
+func TestUnionRejectDuplicateType(t *testing.T) {
+	testSchemaInvalid(t, `["int","int"]`, "Union item 2 ought to be unique type: int; same type as item 1")
+	testSchemaInvalid(t, `["null","string",{"type":"string"}]`, "Union item 3 ought to be unique type: string; same type as item 2")
+	testSchemaInvalid(t, `[{"type":"map","values":"int"},"null",{"type":"map","values":"string"}]`, "Union item 3 ought to be unique type: map; same type as item 1")
+	testSchemaInvalid(t, `[{"type":"array","items":"int"},{"type":"array","items":"int"}]`, "Union item 2 ought to be unique type: array; same type as item 1")
+	testSchemaInvalid(t, `[{"type":"fixed","name":"f1","size":4},{"type":"fixed","name":"f1","size":4}]`, "Union item 2 ought to be unique type: f1; same type as item 1")
+
+	// named types of the same kind are distinguished by their names
+	testSchemaValid(t, `[{"type":"fixed","name":"f1","size":4},{"type":"fixed","name":"f2","size":4}]`)
+	testSchemaValid(t, `[{"type":"array","items":"int"},{"type":"map","values":"int"}]`)
+}
+
 func TestUnionWillCoerceTypeIfPossible(t *testing.T) {
 	var int32val int32 = 3
 	testBinaryCodecPass(t, `["null","long"]`, &int32val, []byte("\x02\x06"))
@@ -66,6 +102,44 @@ func TestUnionWillCoerceTypeIfPossible(t *testing.T) {
 	testBinaryCodecPass(t, `["null","double"]`, &float32val, []byte("\x02\x00\x00\x00\x00\x00\x00\f@"))
 	var float64val float64 = 3.5
 	testBinaryCodecPass(t, `["null","float"]`, &float64val, []byte("\x02\x00\x00\x60\x40"))
+
+	var int8val int8 = -3
+	testBinaryEncodePass(t, `["null","int"]`, &int8val, []byte("\x02\x05"))
+	var int16val int16 = 3
+	testBinaryEncodePass(t, `["null","long"]`, &int16val, []byte("\x02\x06"))
+	var uint16val uint16 = 3
+	testBinaryEncodePass(t, `["null","int"]`, &uint16val, []byte("\x02\x06"))
+	var uint32val uint32 = 3
+	testBinaryEncodePass(t, `["null","int"]`, &uint32val, []byte("\x02\x06"))
+	var uint64val uint64 = 3
+	testBinaryEncodePass(t, `["null","int"]`, &uint64val, []byte("\x02\x06"))
+
+	// values passed without a pointer select an integer member
+	testBinaryEncodePass(t, `["null","string","int"]`, uint64(3), []byte("\x04\x06"))
+	testBinaryEncodePass(t, `["null","string","long"]`, int8(3), []byte("\x04\x06"))
+}
+
+func TestUnionLogicalTypeMembers(t *testing.T) {
+	schema := `["null","long",{"type":"long","logicalType":"timestamp-millis"},{"type":"int","logicalType":"time-millis"}]`
+	when := time.Date(1970, 1, 1, 0, 0, 1, 0, time.UTC)
+
+	// a time selects the timestamp member rather than the long it annotates,
+	// a duration the time member, and an integer the long member
+	testBinaryEncodePass(t, schema, when, []byte("\x04\xd0\x0f"))
+	testBinaryEncodePass(t, schema, 2*time.Second, []byte("\x06\xa0\x1f"))
+	testBinaryEncodePass(t, schema, int64(1000), []byte("\x02\xd0\x0f"))
+	testTextEncodePass(t, schema, when, []byte(`{"long.timestamp-millis":1000}`))
+	testTextEncodePass(t, schema, int64(1000), []byte(`{"long":1000}`))
+
+	// a time prefers a timestamp member to a date member, and the more precise
+	// of two timestamp members
+	testBinaryEncodePass(t, `[{"type":"int","logicalType":"date"},{"type":"long","logicalType":"timestamp-millis"}]`, when, []byte("\x02\xd0\x0f"))
+	testBinaryEncodePass(t, `[{"type":"long","logicalType":"timestamp-millis"},{"type":"long","logicalType":"timestamp-micros"}]`, when, []byte("\x02\x80\x89\x7a"))
+	testBinaryEncodePass(t, `["string",{"type":"int","logicalType":"date"}]`, time.Date(1970, 1, 3, 0, 0, 0, 0, time.UTC), []byte("\x02\x04"))
+
+	// a time or duration is not encoded by a plain long member
+	testBinaryEncodeFail(t, `["null","long","string"]`, when, "cannot encode binary union")
+	testBinaryEncodeFail(t, `["null","long","string"]`, time.Second, "cannot encode binary union")
 }
 
 func TestUnionWithArray(t *testing.T) {
@@ -88,6 +162,62 @@ func TestUnionWithMap(t *testing.T) {
 	testBinaryCodecPass(t, `["null",{"type":"map","values":"string"}]`, &heMap, []byte("\x02\x02\x04He\x0cHelium\x00"))
 }
 
+func TestUnionWithSingleKeyMap(t *testing.T) {
+	// a map whose single key does not name a member is map data
+	stringMap := map[string]interface{}{"string": "x"}
+	for schema, index := range map[string]string{
+		`["null",{"type":"map","values":"string"}]`:       "\x02",
+		`["null","int",{"type":"map","values":"string"}]`: "\x04",
+	} {
+		expected := []byte(index + "\x02\x0cstring\x02x\x00")
+		testBinaryEncodePass(t, schema, stringMap, expected)
+		testBinaryEncodePass(t, schema, Union("map", stringMap), expected)
+		testTextEncodePass(t, schema, stringMap, []byte(`{"map":{"string":"x"}}`))
+		testTextStandardEncodePass(t, schema, stringMap, []byte(`{"string":"x"}`))
+	}
+
+	// but a map whose single key names a member remains a union wrapper
+	testBinaryEncodePass(t, `["string",{"type":"map","values":"string"}]`, stringMap, []byte("\x00\x02x"))
+	testBinaryEncodePass(t, `["string",{"type":"map","values":"string"}]`, Union("map", stringMap), []byte("\x02\x02\x0cstring\x02x\x00"))
+
+	// unions without a map member still require a wrapper
+	testBinaryEncodeFail(t, `["null","int","string"]`, map[string]interface{}{"long": 3}, "map ought to have a single key naming a member schema type")
+}
+
+func TestUnionMoreThanTwoMembers(t *testing.T) {
+	testBinaryCodecPass(t, `["null","string","int"]`, nil, []byte("\x00"))
+	testBinaryCodecPass(t, `["null","string","int"]`, map[string]interface{}{"string": "hi"}, []byte("\x02\x04hi"))
+	testBinaryCodecPass(t, `["null","string","int"]`, map[string]interface{}{"int": int32(3)}, []byte("\x04\x06"))
+
+	testBinaryEncodeFail(t, `["null","string","int"]`, map[string]interface{}{"long": 3}, "map ought to have a single key naming a member schema type")
+	testBinaryDecodeFail(t, `["null","string","int"]`, []byte("\x06"), "index ought to be between 0 and 2")
+}
+
+func TestUnionNullNotFirst(t *testing.T) {
+	testBinaryCodecPass(t, `["string","null"]`, nil, []byte("\x02"))
+
+	str := "hi"
+	testBinaryCodecPass(t, `["string","null"]`, &str, []byte("\x00\x04hi"))
+
+	// unions without null return the single key map form
+	testBinaryCodecPass(t, `["int","string"]`, map[string]interface{}{"string": "hi"}, []byte("\x02\x04hi"))
+	testBinaryEncodeFail(t, `["int","string"]`, nil, "no member schema types support datum")
+
+	testBinaryCodecPass(t, `[{"type":"record","name":"r1","fields":[{"name":"f1","type":"int"}]},{"type":"record","name":"r2","fields":[{"name":"f2","type":"string"}]},"null"]`,
+		map[string]interface{}{"r2": map[string]interface{}{"f2": "x"}}, []byte("\x02\x02x"))
+}
+
+func TestUnionNullNotFirstDefaultValue(t *testing.T) {
+	schema := `{"type":"record","name":"r1","fields":[{"name":"f1","type":["string","null"],"default":"hello"}]}`
+	testBinaryEncodePass(t, schema, map[string]interface{}{}, []byte("\x00\x0ahello"))
+
+	// default value ought to be a value of the first member
+	testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":["int","null"],"default":null}]}`, "union default ought to be value of its first member")
+	testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":["null","int"],"default":13}]}`, "union default ought to be null")
+	testSchemaInvalid(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":["int","string"],"default":{"string":"hello"}}]}`, "default value ought to encode using field schema")
+	testBinaryEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":["int","string"],"default":{"int":13}}]}`, map[string]interface{}{}, []byte("\x00\x1a"))
+}
+
 func TestUnionRecordFieldWhenNull(t *testing.T) {
 	schema := `{
   "type": "record",
@@ -115,6 +245,310 @@ func TestUnionText(t *testing.T) {
 	testTextCodecPass(t, `["null","string"]`, &strVal, []byte(`{"string":"\u0001\uD83D\uDE02 "}`))
 }
 
+func TestUnionValueOrPointer(t *testing.T) {
+	x := "x"
+	for _, datum := range []interface{}{x, &x} {
+		testBinaryEncodePass(t, `["null","string"]`, datum, []byte("\x02\x02x"))
+		testTextEncodePass(t, `["null","string"]`, datum, []byte(`{"string":"x"}`))
+		testTextStandardEncodePass(t, `["null","string"]`, datum, []byte(`"x"`))
+	}
+
+	// values select the member by their Go type, even when the union has
+	// more than one member other than null
+	testBinaryEncodePass(t, `["null","string","long"]`, "x", []byte("\x02\x02x"))
+	testBinaryEncodePass(t, `["null","string","long"]`, 13, []byte("\x04\x1a"))
+	testBinaryEncodePass(t, `["null","string","int"]`, int64(13), []byte("\x04\x1a"))
+	testBinaryEncodePass(t, `["int","long"]`, int32(13), []byte("\x00\x1a"))
+	testBinaryEncodePass(t, `["int","long"]`, int64(13), []byte("\x02\x1a"))
+	testBinaryEncodePass(t, `["null","float","double"]`, 3.5, []byte("\x04\x00\x00\x00\x00\x00\x00\x0c\x40"))
+	testBinaryEncodePass(t, `["boolean","bytes"]`, []byte("x"), []byte("\x02\x02x"))
+	testTextEncodePass(t, `["null","boolean","string"]`, true, []byte(`{"boolean":true}`))
+
+	testBinaryEncodeFail(t, `["null","string","boolean"]`, 13, "unions must be passed as a single pointer type")
+}
+
+func TestUnionScalarPointer(t *testing.T) {
+	someString, someBytes, someBoolean := "some string", []byte("some bytes"), true
+	someInt, someInt32, someInt64 := -13, int32(-13), int64(1)<<40
+	someFloat32, someFloat64 := float32(3.5), -3.5
+
+	for _, tc := range []struct {
+		member string
+		datum  interface{}
+		value  interface{}
+	}{
+		{"string", &someString, someString},
+		{"bytes", &someBytes, someBytes},
+		{"boolean", &someBoolean, someBoolean},
+		{"int", &someInt32, someInt32},
+		{"long", &someInt32, someInt32},
+		{"long", &someInt64, someInt64},
+		{"long", &someInt, someInt},
+		{"float", &someFloat32, someFloat32},
+		{"double", &someFloat64, someFloat64},
+		// pointers to other Go types take the general case
+		{"int", &someInt, someInt},
+		{"double", &someFloat32, someFloat32},
+		{"string", &someBytes, someBytes},
+	} {
+		// pointers to scalars ought to encode the same as the values they
+		// point to, wherever null is declared in the union
+		for _, schema := range []string{`["null","` + tc.member + `"]`, `["` + tc.member + `","null"]`} {
+			codec, err := NewCodec(schema)
+			ensureError(t, err)
+			expected, err := codec.BinaryFromNative(nil, Union(tc.member, tc.value))
+			ensureError(t, err)
+			testBinaryEncodePass(t, schema, tc.datum, expected)
+		}
+	}
+
+	// nil pointers encode null
+	testBinaryEncodePass(t, `["null","long"]`, (*string)(nil), []byte("\x00"))
+	testBinaryEncodePass(t, `["double","null"]`, (*float64)(nil), []byte("\x02"))
+
+	testBinaryEncodeFail(t, `["null","int"]`, &someInt64, "cannot encode binary int: provided Go int64 would lose precision")
+}
+
+func TestUnionNullableBareValue(t *testing.T) {
+	const record = `{"type":"record","name":"r2","fields":[{"name":"a","type":"int"}]}`
+	cases := []struct {
+		member  string
+		bare    interface{}
+		binary  string
+		textual string
+	}{
+		{`"string"`, "hello", "\x02\x0ahello", `{"string":"hello"}`},
+		{`"double"`, 3, "\x02\x00\x00\x00\x00\x00\x00\x08\x40", `{"double":3}`},
+		{`"long"`, float64(3), "\x02\x06", `{"long":3}`},
+		{`{"type":"enum","name":"e1","symbols":["alpha","bravo"]}`, "bravo", "\x02\x02", `{"e1":"bravo"}`},
+		{record, map[string]interface{}{"a": 3}, "\x02\x06", `{"r2":{"a":3}}`},
+		{`{"type":"array","items":"int"}`, []int{3}, "\x02\x02\x06\x00", `{"array":[3]}`},
+		{`{"type":"map","values":"int"}`, map[string]interface{}{"k": 3}, "\x02\x02\x02k\x06\x00", `{"map":{"k":3}}`},
+	}
+	for _, c := range cases {
+		schema := `{"type":"record","name":"r1","fields":[{"name":"f1","type":["null",` + c.member + `]}]}`
+		codec, err := NewCodec(schema)
+		ensureError(t, err)
+		name := codec.recordFields[0].codec.unionInfo.allowedTypes[1]
+
+		// the bare value, its pointer, and the wrapped value encode alike
+		bare := c.bare
+		for _, value := range []interface{}{bare, &bare, Union(name, bare)} {
+			datum := map[string]interface{}{"f1": value}
+			buf, err := codec.BinaryFromNative(nil, datum)
+			ensureError(t, err)
+			if actual, expected := string(buf), c.binary; actual != expected {
+				t.Errorf("schema: %s; GOT: %q; WANT: %q", schema, actual, expected)
+			}
+			buf, err = codec.TextualFromNative(nil, datum)
+			ensureError(t, err)
+			if actual, expected := string(buf), `{"f1":`+c.textual+`}`; actual != expected {
+				t.Errorf("schema: %s; GOT: %v; WANT: %v", schema, actual, expected)
+			}
+		}
+	}
+
+	// a map naming the member is still the wrapped form
+	testBinaryEncodePass(t, `["null",`+record+`]`, map[string]interface{}{"r2": map[string]interface{}{"a": 3}}, []byte("\x02\x06"))
+	testTextStandardEncodePass(t, `["null",`+record+`]`, map[string]interface{}{"a": 3}, []byte(`{"a":3}`))
+}
+
+func TestUnionTypedNilPointer(t *testing.T) {
+	var nilInt *int
+	testBinaryEncodePass(t, `["null","int"]`, nilInt, []byte("\x00"))
+	testTextEncodePass(t, `["null","int"]`, nilInt, []byte("null"))
+	testBinaryEncodeFail(t, `["int","string"]`, nilInt, "cannot encode binary union: no member schema types support datum: allowed types: [int string]; received: *int")
+	testTextEncodeFail(t, `["int","string"]`, nilInt, "cannot encode textual union: no member schema types support datum: allowed types: [int string]; received: *int")
+}
+
+func TestUnionFixed(t *testing.T) {
+	const schema = `["null",{"type":"fixed","name":"f1","size":4}]`
+	abcd := []byte("abcd")
+	testBinaryEncodePass(t, schema, Union("f1", abcd), []byte("\x02abcd"))
+	testBinaryDecodePass(t, schema, &abcd, []byte("\x02abcd"))
+	testTextCodecPass(t, schema, Union("f1", []byte("abcd")), []byte(`{"f1":"abcd"}`))
+	testTextStandardEncodePass(t, schema, Union("f1", []byte("abcd")), []byte(`"abcd"`))
+
+	// a byte slice passed by value selects the fixed member of its size
+	testBinaryEncodePass(t, schema, []byte("abcd"), []byte("\x02abcd"))
+	testTextEncodePass(t, schema, []byte("abcd"), []byte(`{"f1":"abcd"}`))
+	testTextStandardEncodePass(t, schema, []byte("abcd"), []byte(`"abcd"`))
+	testBinaryEncodePass(t, `[{"type":"fixed","name":"f2","size":2},{"type":"fixed","name":"f4","size":4}]`, []byte("abcd"), []byte("\x02abcd"))
+	testBinaryEncodePass(t, `["bytes",{"type":"fixed","name":"f4","size":4}]`, []byte("abcd"), []byte("\x00\x08abcd"))
+
+	testBinaryEncodeFail(t, schema, []byte("abc"), "datum size ought to equal schema size: 3 != 4")
+	testBinaryEncodeFail(t, schema, Union("f1", []byte("abcde")), "datum size ought to equal schema size: 5 != 4")
+	testTextEncodeFail(t, schema, []byte("abc"), "datum size ought to equal schema size: 3 != 4")
+
+	codec, err := NewCodecForStandardJSON(schema)
+	ensureError(t, err)
+	_, _, err = codec.NativeFromTextual([]byte(`"abc"`))
+	ensureError(t, err, "could not decode any json data")
+}
+
+func TestUnionTextPointerUsesMemberName(t *testing.T) {
+	someString := "some string"
+	testTextEncodePass(t, `["null","string"]`, &someString, []byte(`{"string":"some string"}`))
+	testTextEncodePass(t, `["string","null"]`, &someString, []byte(`{"string":"some string"}`))
+}
+
+func TestUnionHelper(t *testing.T) {
+	if got, want := len(Union("string", "x")), 1; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got := Union("", "x"); got != nil {
+		t.Errorf("GOT: %v; WANT: %v", got, nil)
+	}
+	testBinaryCodecPass(t, `["null","string","int"]`, Union("string", "hi"), []byte("\x02\x04hi"))
+	testBinaryEncodeFail(t, `["null","string","int"]`, Union("", "hi"), "map ought to have a single key naming a member schema type")
+}
+
+func TestUnionNullable(t *testing.T) {
+	for _, schema := range []string{`["null","int"]`, `["int","null"]`} {
+		codec, err := NewCodec(schema)
+		ensureError(t, err)
+		inner, ok := codec.Nullable()
+		if !ok {
+			t.Fatalf("%s: GOT: %v; WANT: %v", schema, ok, true)
+		}
+		if actual, expected := inner.typeName.fullName, "int"; actual != expected {
+			t.Errorf("%s: GOT: %v; WANT: %v", schema, actual, expected)
+		}
+		buf, err := inner.BinaryFromNative(nil, 3)
+		ensureError(t, err)
+		if actual, expected := buf, []byte{0x6}; !bytes.Equal(actual, expected) {
+			t.Errorf("%s: GOT: %#v; WANT: %#v", schema, actual, expected)
+		}
+	}
+
+	codec, err := NewCodec(`{"type":"record","name":"r1","fields":[{"name":"f1","type":[{"type":"enum","name":"e1","symbols":["alpha","beta"]},"null"]}]}`)
+	ensureError(t, err)
+	inner, ok := codec.recordFields[0].codec.Nullable()
+	if !ok {
+		t.Fatalf("GOT: %v; WANT: %v", ok, true)
+	}
+	if symbols, ok := inner.EnumSymbols(); !ok || fmt.Sprint(symbols) != "[alpha beta]" {
+		t.Errorf("GOT: %v, %v; WANT: %v, %v", symbols, ok, "[alpha beta]", true)
+	}
+
+	for _, schema := range []string{`"int"`, `"null"`, `["int","string"]`, `["null","int","string"]`, `["null"]`} {
+		codec, err := NewCodec(schema)
+		ensureError(t, err)
+		if inner, ok := codec.Nullable(); ok || inner != nil {
+			t.Errorf("%s: GOT: %v, %v; WANT: %v, %v", schema, inner, ok, nil, false)
+		}
+	}
+}
+
+func TestUnionMembers(t *testing.T) {
+	codec, err := NewCodec(`{"type":"record","name":"r1","namespace":"com.example","fields":[{"name":"f1","type":["null","string",{"type":"long","logicalType":"timestamp-millis"},{"type":"enum","name":"e1","symbols":["alpha"]}]}]}`)
+	ensureError(t, err)
+
+	if _, ok := codec.UnionMembers(); ok {
+		t.Errorf("GOT: %v; WANT: %v", ok, false)
+	}
+
+	union := codec.recordFields[0].codec
+	members, ok := union.UnionMembers()
+	if !ok {
+		t.Fatalf("GOT: %v; WANT: %v", ok, true)
+	}
+	if actual, expected := fmt.Sprintf("%q", members), `["null" "string" "long.timestamp-millis" "com.example.e1"]`; actual != expected {
+		t.Errorf("GOT: %v; WANT: %v", actual, expected)
+	}
+	for _, name := range members {
+		if name == "null" {
+			continue
+		}
+		if _, err := union.BinaryFromNative(nil, Union(name, 3)); err != nil && strings.Contains(err.Error(), "member schema type") {
+			t.Errorf("member %q: %s", name, err)
+		}
+	}
+
+	// modifying the returned slice does not modify the codec
+	members[1] = "int"
+	_ = append(members[:2], "bytes")
+	if members, _ = union.UnionMembers(); fmt.Sprintf("%q", members) != `["null" "string" "long.timestamp-millis" "com.example.e1"]` {
+		t.Errorf("GOT: %q; WANT: codec members unchanged", members)
+	}
+	if _, err = union.BinaryFromNative(nil, Union("string", "hi")); err != nil {
+		t.Error(err)
+	}
+}
+
+func testTextStandardEncodePass(t *testing.T, schema string, datum interface{}, expected []byte) {
+	t.Helper()
+	codec, err := NewCodec(schema)
+	if err != nil {
+		t.Fatalf("Schema: %q %s", schema, err)
+	}
+	actual, err := codec.TextualFromNativeStandard(nil, datum)
+	if err != nil {
+		t.Fatalf("schema: %s; Datum: %v; %s", schema, datum, err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("schema: %s; Datum: %v; GOT: %s; WANT: %s", schema, datum, actual, expected)
+	}
+}
+
+func TestUnionTextualFromNativeStandard(t *testing.T) {
+	testTextStandardEncodePass(t, `["null","string","int"]`, nil, []byte("null"))
+	testTextStandardEncodePass(t, `["null","string","int"]`, Union("string", "some string"), []byte(`"some string"`))
+	testTextStandardEncodePass(t, `["null","string","int"]`, Union("int", 3), []byte("3"))
+	testTextStandardEncodePass(t, `["null","string","int"]`, Union("null", nil), []byte("null"))
+
+	val := "some string"
+	testTextStandardEncodePass(t, `["null","string"]`, &val, []byte(`"some string"`))
+	testTextStandardEncodePass(t, `["string","null"]`, &val, []byte(`"some string"`))
+	testTextStandardEncodePass(t, `["null","string"]`, (*string)(nil), []byte("null"))
+
+	// unions nested in arrays, maps, and records are unwrapped
+	testTextStandardEncodePass(t, `{"type":"array","items":["null","int"]}`, []interface{}{Union("int", 1), nil, Union("int", 3)}, []byte("[1,null,3]"))
+	testTextStandardEncodePass(t, `{"type":"map","values":["null","string","int"]}`, map[string]interface{}{"k": Union("string", "v")}, []byte(`{"k":"v"}`))
+	testTextStandardEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":["null","string"]}]}`, map[string]interface{}{"f1": &val}, []byte(`{"f1":"some string"}`))
+	testTextStandardEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":["null","string"]}]}`, map[string]interface{}{"f1": nil}, []byte(`{"f1":null}`))
+	testTextStandardEncodePass(t, `{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"array","items":{"type":"record","name":"r2","fields":[{"name":"f2","type":["null","long","string"]}]}}}]}`,
+		map[string]interface{}{"f1": []interface{}{map[string]interface{}{"f2": Union("long", 13)}, map[string]interface{}{"f2": nil}}},
+		[]byte(`{"f1":[{"f2":13},{"f2":null}]}`))
+
+	// recursive records are unwrapped at every level
+	testTextStandardEncodePass(t, `{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`,
+		map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": nil})},
+		[]byte(`{"next":{"next":null}}`))
+
+	// the avro-JSON encoding is unchanged
+	testTextEncodePass(t, `{"type":"array","items":["null","int"]}`, []interface{}{nil}, []byte("[null]"))
+}
+
+func TestUnionTextualFromNativeStandardFail(t *testing.T) {
+	codec, err := NewCodec(`["null","string","int"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.TextualFromNativeStandard(nil, Union("float", 3.5))
+	ensureError(t, err, "map ought to have a single key naming a member schema type")
+
+	codec, err = NewCodec(`["string","int"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = codec.TextualFromNativeStandard(nil, nil)
+	ensureError(t, err, "no member schema types support datum")
+}
+
+func ExampleUnion() {
+	codec, err := NewCodec(`["null","string","int"]`)
+	if err != nil {
+		fmt.Println(err)
+	}
+	buf, err := codec.BinaryFromNative(nil, Union("int", 3))
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("%#v", buf)
+	// Output: []byte{0x4, 0x6}
+}
+
 func ExampleJSONUnion() {
 	codec, err := NewCodec(`["null","string"]`)
 	if err != nil {
@@ -170,7 +604,29 @@ func ExampleJSONStringToTextual() {
 		fmt.Println(err)
 	}
 	fmt.Println(string(buf))
-	// Output: {"string":"some string"}
+	// Output: "some string"
+}
+
+func ExampleNewCodecForStandardJSON() {
+	codec, err := NewCodecForStandardJSON(`["null","string","int"]`)
+	if err != nil {
+		fmt.Println(err)
+	}
+	for _, text := range []string{`"some string"`, `3`, `null`} {
+		native, _, err := codec.NativeFromTextual([]byte(text))
+		if err != nil {
+			fmt.Println(err)
+		}
+		buf, err := codec.TextualFromNative(nil, native)
+		if err != nil {
+			fmt.Println(err)
+		}
+		fmt.Println(native, string(buf))
+	}
+	// Output:
+	// map[string:some string] "some string"
+	// map[int:3] 3
+	// <nil> null
 }
 
 func ExampleJSONStringToNative() {
@@ -200,35 +656,339 @@ func ExampleJSONStringToNative() {
 	// Output: some string one
 }
 
+func TestUnionJSONCompositeMemberMismatch(t *testing.T) {
+	codec, err := NewCodecForStandardJSON(`["null","string",{"type":"map","values":"int"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = codec.NativeFromTextual([]byte(`[1,2]`))
+	ensureError(t, err, "could not decode any json data")
+}
+
+func TestUnionJSONRecordOrMap(t *testing.T) {
+	schema := `["null",{"type":"map","values":"string"},{"type":"record","name":"r","fields":[{"name":"field1","type":"string"},{"name":"field2","type":"string","default":""}]}]`
+	// record fields are compatible with the map, but the record is more specific
+	testJSONDecodePass(t, schema, Union("r", map[string]interface{}{"field1": "value1", "field2": "value2"}), []byte(`{"field1":"value1","field2":"value2"}`))
+	testJSONDecodePass(t, schema, Union("r", map[string]interface{}{"field1": "value1", "field2": ""}), []byte(`{"field1":"value1"}`))
+	// keys which are not record fields only fit the map
+	testJSONDecodePass(t, schema, Union("map", map[string]interface{}{"k1": "v1"}), []byte(`{"k1":"v1"}`))
+	testJSONDecodePass(t, schema, Union("map", map[string]interface{}{"field1": "value1", "k1": "v1"}), []byte(`{"field1":"value1","k1":"v1"}`))
+}
+
+func TestUnionJSONBoolean(t *testing.T) {
+	for _, v := range []bool{true, false} {
+		encoded := []byte(fmt.Sprintf("%t", v))
+		testJSONDecodePass(t, `["null","string","long","boolean"]`, Union("boolean", v), encoded)
+		testTextStandardEncodePass(t, `["null","string","long","boolean"]`, Union("boolean", v), encoded)
+	}
+
+	codec, err := NewCodecForStandardJSON(`["null","string"]`)
+	ensureError(t, err)
+	_, _, err = codec.NativeFromTextual([]byte(`true`))
+	ensureError(t, err, "could not decode any json data")
+}
+
+func TestUnionJSONStringMemberOrder(t *testing.T) {
+	// members able to decode a JSON string are tried in schema order
+	testJSONDecodePass(t, `["null","bytes","string"]`, Union("bytes", []byte("")), []byte(`""`))
+	testJSONDecodePass(t, `["null","string","bytes"]`, Union("string", ""), []byte(`""`))
+	testJSONDecodePass(t, `["null","bytes","string"]`, Union("bytes", []byte("value1")), []byte(`"value1"`))
+	testJSONDecodePass(t, `["null","string","bytes"]`, Union("string", "value1"), []byte(`"value1"`))
+	testJSONDecodePass(t, `["bytes","long","string"]`, Union("bytes", []byte("value1")), []byte(`"value1"`))
+}
+
+func TestUnionJSONLargeLong(t *testing.T) {
+	// 2^53 + 1 and the extremes of long cannot be represented as a float64
+	for _, v := range []int64{9007199254740993, -9007199254740993, math.MaxInt64, math.MinInt64} {
+		encoded := []byte(strconv.FormatInt(v, 10))
+		testJSONDecodePass(t, `["null","long","double"]`, Union("long", v), encoded)
+		testJSONDecodePass(t, `["null","double","long"]`, Union("long", v), encoded)
+		testTextStandardEncodePass(t, `["null","long","double"]`, Union("long", v), encoded)
+	}
+	// whole numbers too large for a long fall back to the floating point member
+	testJSONDecodePass(t, `["null","long","double"]`, Union("double", 9223372036854775808.0), []byte("9223372036854775808"))
+	testJSONDecodePass(t, `["null","long","double"]`, Union("double", 3.0), []byte("3.0"))
+}
+
+func TestUnionJSONRoundTrip(t *testing.T) {
+	cases := []struct {
+		schema string
+		text   string
+	}{
+		{`["null","string","int"]`, `"x"`},
+		{`["null","string","int"]`, `3`},
+		{`["null","string","int"]`, `null`},
+		{`{"type":"array","items":["null","long"]}`, `[1,null,3]`},
+		{`{"type":"map","values":["null","string"]}`, `{"k":"v"}`},
+		{`{"type":"record","name":"r1","fields":[{"name":"f1","type":["null","string"]}]}`, `{"f1":"x"}`},
+		{`{"type":"record","name":"r1","fields":[{"name":"f1","type":["null",{"type":"record","name":"r2","fields":[{"name":"f2","type":["null","long","string"]}]}]}]}`, `{"f1":{"f2":13}}`},
+		{`{"type":"record","name":"r1","fields":[{"name":"f1","type":["null",{"type":"record","name":"r2","fields":[{"name":"f2","type":["null","long","string"]}]}]}]}`, `{"f1":{"f2":null}}`},
+		{`{"type":"record","name":"r1","fields":[{"name":"f1","type":{"type":"array","items":{"type":"record","name":"r2","fields":[{"name":"f2","type":["null","boolean"]}]}}}]}`, `{"f1":[{"f2":true},{"f2":null}]}`},
+	}
+	for _, c := range cases {
+		codec, err := NewCodecForStandardJSON(c.schema)
+		ensureError(t, err)
+		native, _, err := codec.NativeFromTextual([]byte(c.text))
+		ensureError(t, err)
+
+		buf, err := codec.TextualFromNative(nil, native)
+		ensureError(t, err)
+		if actual, expected := string(buf), c.text; actual != expected {
+			t.Errorf("schema: %s; GOT: %v; WANT: %v", c.schema, actual, expected)
+		}
+
+		// natives decoded from binary encode to the same text
+		buf, err = codec.BinaryFromNative(nil, native)
+		ensureError(t, err)
+		native, _, err = codec.NativeFromBinary(buf)
+		ensureError(t, err)
+		buf, err = codec.TextualFromNative(nil, native)
+		ensureError(t, err)
+		if actual, expected := string(buf), c.text; actual != expected {
+			t.Errorf("schema: %s; GOT: %v; WANT: %v", c.schema, actual, expected)
+		}
+	}
+}
+
+func TestUnionJSONPrefersMemberConsumingEntireValue(t *testing.T) {
+	// the int member only consumes the leading `3`
+	testJSONDecodePass(t, `["null","int","double"]`, Union("double", 300.0), []byte(`3e2`))
+}
+
+func TestUnionJSONLogicalTypeFromString(t *testing.T) {
+	decode := func(t *testing.T, schema, encoded string) (interface{}, error) {
+		t.Helper()
+		codec, err := NewCodecForStandardJSON(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, remaining, err := codec.NativeFromTextual([]byte(encoded))
+		if err == nil && len(remaining) != 0 {
+			t.Errorf("GOT: %q; WANT: %q", remaining, "")
+		}
+		return decoded, err
+	}
+
+	t.Run("timestamp-millis", func(t *testing.T) {
+		decoded, err := decode(t, `["null",{"type":"long","logicalType":"timestamp-millis"}]`, `"2021-02-03T04:05:06.789+01:00"`)
+		ensureError(t, err)
+		value := decoded.(map[string]interface{})["long.timestamp-millis"]
+		if actual, expected := value, time.Date(2021, 2, 3, 3, 5, 6, 789000000, time.UTC); !actual.(time.Time).Equal(expected) {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	})
+
+	t.Run("timestamp-micros truncates", func(t *testing.T) {
+		decoded, err := decode(t, `["null",{"type":"long","logicalType":"timestamp-micros"}]`, `"2021-02-03T04:05:06.123456789Z"`)
+		ensureError(t, err)
+		value := decoded.(map[string]interface{})["long.timestamp-micros"]
+		if actual, expected := value, time.Date(2021, 2, 3, 4, 5, 6, 123456000, time.UTC); !actual.(time.Time).Equal(expected) {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	})
+
+	t.Run("date", func(t *testing.T) {
+		decoded, err := decode(t, `["null",{"type":"int","logicalType":"date"}]`, `"2021-02-03"`)
+		ensureError(t, err)
+		value := decoded.(map[string]interface{})["int.date"]
+		if actual, expected := value, time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC); !actual.(time.Time).Equal(expected) {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	})
+
+	t.Run("time-millis", func(t *testing.T) {
+		decoded, err := decode(t, `["null",{"type":"int","logicalType":"time-millis"}]`, `"04:05:06.789"`)
+		ensureError(t, err)
+		if actual, expected := decoded.(map[string]interface{})["int.time-millis"], 4*time.Hour+5*time.Minute+6789*time.Millisecond; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	})
+
+	t.Run("string member preferred", func(t *testing.T) {
+		decoded, err := decode(t, `["null","string",{"type":"long","logicalType":"timestamp-millis"}]`, `"2021-02-03T04:05:06Z"`)
+		ensureError(t, err)
+		if actual, expected := decoded.(map[string]interface{})["string"], "2021-02-03T04:05:06Z"; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	})
+
+	t.Run("numeric form", func(t *testing.T) {
+		decoded, err := decode(t, `["null",{"type":"long","logicalType":"timestamp-millis"}]`, `1612325106789`)
+		ensureError(t, err)
+		value := decoded.(map[string]interface{})["long.timestamp-millis"]
+		if actual, expected := value, time.Date(2021, 2, 3, 4, 5, 6, 789000000, time.UTC); !actual.(time.Time).Equal(expected) {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := decode(t, `["null",{"type":"long","logicalType":"timestamp-millis"}]`, `"not a timestamp"`)
+		ensureError(t, err, "could not decode any json data")
+	})
+}
+
+func TestUnionJSONFollowedByAnotherField(t *testing.T) {
+	codec, err := NewCodecForStandardJSON(`{"type":"record","name":"r1","fields":[{"name":"f1","type":["null","string","int"]},{"name":"f2","type":"int"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		encoded string
+		f1      interface{}
+	}{
+		{`{"f1":"value1","f2":3}`, Union("string", "value1")},
+		{`{"f1": 7 , "f2":3}`, Union("int", int32(7))},
+		{`{"f1":null,"f2":3}`, nil},
+	}
+	for _, c := range cases {
+		decoded, remaining, err := codec.NativeFromTextual([]byte(c.encoded + ` trailing`))
+		if err != nil {
+			t.Fatalf("%s: %s", c.encoded, err)
+		}
+		if actual, expected := string(remaining), " trailing"; actual != expected {
+			t.Errorf("%s: remaining; Actual: %q; Expected: %q", c.encoded, actual, expected)
+		}
+		record := decoded.(map[string]interface{})
+		if actual, expected := fmt.Sprintf("%v", *record["f1"].(*interface{})), fmt.Sprintf("%v", c.f1); actual != expected {
+			t.Errorf("%s: f1; Actual: %v; Expected: %v", c.encoded, actual, expected)
+		}
+		if actual, expected := record["f2"], int32(3); actual != expected {
+			t.Errorf("%s: f2; Actual: %v; Expected: %v", c.encoded, actual, expected)
+		}
+	}
+}
+
 func TestUnionJSON(t *testing.T) {
 	testJSONDecodePass(t, `["null","int"]`, nil, []byte("null"))
+	testJSONDecodePass(t, `["null","int"]`, Union("int", 3), []byte("  3"))
 	int3 := 3
 	testJSONDecodePass(t, `["null","int"]`, &int3, []byte(`3`))
 	long33 := 333333333333333
 	testJSONDecodePass(t, `["null","long"]`, &long33, []byte(`333333333333333`))
 	float6 := 6.77
 	testJSONDecodePass(t, `["null","float"]`, &float6, []byte(`6.77`))
-	//double6 := 6.77
-	//testJSONDecodePass(t, `["null","double"]`, &double6, []byte(`6.77`))
-	//testJSONDecodePass(t, `["null",{"type":"array","items":"int"}]`, Union("array", []interface{}{1, 2}), []byte(`[1,2]`))
-	//testJSONDecodePass(t, `["null",{"type":"map","values":"int"}]`, Union("map", map[string]interface{}{"k1": 13}), []byte(`{"k1":13}`))
-	//testJSONDecodePass(t, `["null",{"name":"r1","type":"record","fields":[{"name":"field1","type":"string"},{"name":"field2","type":"string"}]}]`, Union("r1", map[string]interface{}{"field1": "value1", "field2": "value2"}), []byte(`{"field1": "value1", "field2": "value2"}`))
-	//testJSONDecodePass(t, `["null","boolean"]`, Union("boolean", true), []byte(`true`))
-	//testJSONDecodePass(t, `["null","boolean"]`, Union("boolean", false), []byte(`false`))
+	testJSONDecodePass(t, `["null","double"]`, Union("double", 6.77), []byte(`6.77`))
+	testJSONDecodePass(t, `["null","float","double"]`, Union("double", 6.77), []byte(`6.77`))
+	testJSONDecodePass(t, `["null","double","float"]`, Union("double", 6.77), []byte(`6.77`))
+	testJSONDecodePass(t, `["null","int","double"]`, Union("int", 3), []byte(`3`))
+	testJSONDecodePass(t, `["null","double","int"]`, Union("int", 3), []byte(`3`))
+	testJSONDecodePass(t, `["null","int","double"]`, Union("double", 3.5), []byte(`3.5`))
+	testJSONDecodePass(t, `["null","int","long"]`, Union("long", 333333333333333), []byte(`333333333333333`))
+	testJSONDecodePass(t, `["null",{"type":"array","items":"int"}]`, Union("array", []interface{}{1, 2}), []byte(`[1,2]`))
+	testJSONDecodePass(t, `["null",{"type":"map","values":"int"}]`, Union("map", map[string]interface{}{"k1": 13}), []byte(`{"k1":13}`))
+	testJSONDecodePass(t, `["null",{"type":"map","values":"int"},{"type":"array","items":"int"}]`, Union("array", []interface{}{1, 2}), []byte(`[1,2]`))
+	testJSONDecodePass(t, `["null",{"type":"array","items":"int"},{"type":"map","values":"int"}]`, Union("map", map[string]interface{}{"k1": 13}), []byte(`{"k1":13}`))
+	testJSONDecodePass(t, `["null","string",{"type":"array","items":"string"}]`, Union("array", []interface{}{"a", "b"}), []byte(`["a","b"]`))
+	testJSONDecodePass(t, `["null",{"name":"r1","type":"record","fields":[{"name":"field1","type":"string"},{"name":"field2","type":"string"}]}]`, Union("r1", map[string]interface{}{"field1": "value1", "field2": "value2"}), []byte(`{"field1": "value1", "field2": "value2"}`))
+	testJSONDecodePass(t, `["null","boolean"]`, Union("boolean", true), []byte(`true`))
+	testJSONDecodePass(t, `["null","boolean"]`, Union("boolean", false), []byte(`false`))
 	//testJSONDecodePass(t, `["null",{"type":"enum","name":"e1","symbols":["alpha","bravo"]}]`, Union("e1", "bravo"), []byte(`"bravo"`))
-	//testJSONDecodePass(t, `["null", "bytes"]`, Union("bytes", []byte("")), []byte("\"\""))
-	//testJSONDecodePass(t, `["null", "bytes", "string"]`, Union("bytes", []byte("")), []byte("\"\""))
-	//testJSONDecodePass(t, `["null", "string", "bytes"]`, Union("string", "value1"), []byte(`"value1"`))
+	testJSONDecodePass(t, `["null", "bytes"]`, Union("bytes", []byte("")), []byte("\"\""))
+	testJSONDecodePass(t, `["null", "bytes", "string"]`, Union("bytes", []byte("")), []byte("\"\""))
+	testJSONDecodePass(t, `["null", "string", "bytes"]`, Union("string", "value1"), []byte(`"value1"`))
 	//testJSONDecodePass(t, `["null", {"type":"enum","name":"e1","symbols":["alpha","bravo"]}, "string"]`, Union("e1", "bravo"), []byte(`"bravo"`))
-	//testJSONDecodePass(t, `["null", {"type":"fixed","name":"f1","size":4}]`, Union("f1", []byte(`abcd`)), []byte(`"abcd"`))
-	//testJSONDecodePass(t, `"string"`, "abcd", []byte(`"abcd"`))
-	//testJSONDecodePass(t, `{"type":"record","name":"kubeEvents","fields":[{"name":"field1","type":"string","default":""}]}`, map[string]interface{}{"field1": "value1"}, []byte(`{"field1":"value1"}`))
-	//testJSONDecodePass(t, `{"type":"record","name":"kubeEvents","fields":[{"name":"field1","type":"string","default":""},{"name":"field2","type":"string"}]}`, map[string]interface{}{"field1": "", "field2": "deef"}, []byte(`{"field2": "deef"}`))
-	//testJSONDecodePass(t, `{"type":"record","name":"kubeEvents","fields":[{"name":"field1","type":["string","null"],"default":""}]}`, map[string]interface{}{"field1": Union("string", "value1")}, []byte(`{"field1":"value1"}`))
-	//testJSONDecodePass(t, `{"type":"record","name":"kubeEvents","fields":[{"name":"field1","type":["string","null"],"default":""}]}`, map[string]interface{}{"field1": nil}, []byte(`{"field1":null}`))
-	//// union of null which has minimal syntax
-	//testJSONDecodePass(t, `{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`, map[string]interface{}{"next": nil}, []byte(`{"next": null}`))
-	//// record containing union of record (recursive record)
-	//testJSONDecodePass(t, `{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`, map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": nil})}, []byte(`{"next":{"next":null}}`))
-	//testJSONDecodePass(t, `{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`, map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": nil})})}, []byte(`{"next":{"next":{"next":null}}}`))
+	testJSONDecodePass(t, `["null", {"type":"fixed","name":"f1","size":4}]`, Union("f1", []byte(`abcd`)), []byte(`"abcd"`))
+	testJSONDecodePass(t, `"string"`, "abcd", []byte(`"abcd"`))
+	testJSONDecodePass(t, `{"type":"record","name":"kubeEvents","fields":[{"name":"field1","type":"string","default":""}]}`, map[string]interface{}{"field1": "value1"}, []byte(`{"field1":"value1"}`))
+	testJSONDecodePass(t, `{"type":"record","name":"kubeEvents","fields":[{"name":"field1","type":"string","default":""},{"name":"field2","type":"string"}]}`, map[string]interface{}{"field1": "", "field2": "deef"}, []byte(`{"field2": "deef"}`))
+	testJSONDecodePass(t, `{"type":"record","name":"kubeEvents","fields":[{"name":"field1","type":["string","null"],"default":""}]}`, map[string]interface{}{"field1": Union("string", "value1")}, []byte(`{"field1":"value1"}`))
+	testJSONDecodePass(t, `{"type":"record","name":"kubeEvents","fields":[{"name":"field1","type":["string","null"],"default":""}]}`, map[string]interface{}{"field1": nil}, []byte(`{"field1":null}`))
+	testJSONDecodePass(t, `{"type":"record","name":"kubeEvents","fields":[{"name":"field1","type":["string","null"],"default":"none"}]}`, map[string]interface{}{"field1": Union("string", "none")}, []byte(`{}`))
+	testJSONDecodePass(t, `{"type":"record","name":"kubeEvents","fields":[{"name":"field1","type":["null","string"],"default":null},{"name":"field2","type":"string"}]}`, map[string]interface{}{"field1": nil, "field2": "deef"}, []byte(`{"field2":"deef"}`))
+	// union of null which has minimal syntax
+	testJSONDecodePass(t, `{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`, map[string]interface{}{"next": nil}, []byte(`{"next": null}`))
+	// record containing union of record (recursive record)
+	testJSONDecodePass(t, `{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`, map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": nil})}, []byte(`{"next":{"next":null}}`))
+	testJSONDecodePass(t, `{"type":"record","name":"LongList","fields":[{"name":"next","type":["null","LongList"],"default":null}]}`, map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": Union("LongList", map[string]interface{}{"next": nil})})}, []byte(`{"next":{"next":{"next":null}}}`))
+}
+
+func TestUnionResolver(t *testing.T) {
+	schema := `[
+		"null",
+		{"type":"record","name":"r1","namespace":"com.example","fields":[{"name":"a","type":"int"}]},
+		{"type":"record","name":"r2","namespace":"com.example","fields":[{"name":"a","type":"int"},{"name":"b","type":"int"}]}
+	]`
+	resolver := func(datum interface{}) (string, bool) {
+		m, ok := datum.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if _, ok = m["b"]; ok {
+			return "com.example.r2", true
+		}
+		if _, ok = m["a"]; ok {
+			return "com.example.r1", true
+		}
+		return "", false
+	}
+	codec, err := NewCodecWithOptions(schema, WithUnionResolver(resolver))
+	ensureError(t, err)
+
+	t.Run("binary", func(t *testing.T) {
+		buf, err := codec.BinaryFromNative(nil, map[string]interface{}{"a": 3})
+		ensureError(t, err)
+		if actual, expected := buf, []byte{0x02, 0x06}; !bytes.Equal(actual, expected) {
+			t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+		}
+		buf, err = codec.BinaryFromNative(nil, map[string]interface{}{"a": 3, "b": 4})
+		ensureError(t, err)
+		if actual, expected := buf, []byte{0x04, 0x06, 0x08}; !bytes.Equal(actual, expected) {
+			t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+		}
+	})
+
+	t.Run("textual", func(t *testing.T) {
+		buf, err := codec.TextualFromNative(nil, map[string]interface{}{"a": 3})
+		ensureError(t, err)
+		if actual, expected := string(buf), `{"com.example.r1":{"a":3}}`; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+		buf, err = codec.TextualFromNativeStandard(nil, map[string]interface{}{"a": 3})
+		ensureError(t, err)
+		if actual, expected := string(buf), `{"a":3}`; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+		buf, err = codec.TextualFromNative(nil, nil)
+		ensureError(t, err)
+		if actual, expected := string(buf), `null`; actual != expected {
+			t.Errorf("GOT: %v; WANT: %v", actual, expected)
+		}
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		// resolver declines values other than maps, and the encoder falls back
+		// to the usual union forms
+		buf, err := codec.BinaryFromNative(nil, nil)
+		ensureError(t, err)
+		if actual, expected := buf, []byte{0x00}; !bytes.Equal(actual, expected) {
+			t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+		}
+		// resolver declines a map with neither field
+		buf, err = codec.BinaryFromNative(nil, Union("com.example.r1", map[string]interface{}{"a": 3}))
+		ensureError(t, err)
+		if actual, expected := buf, []byte{0x02, 0x06}; !bytes.Equal(actual, expected) {
+			t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+		}
+	})
+
+	t.Run("unknown member", func(t *testing.T) {
+		codec, err := NewCodecWithOptions(schema, WithUnionResolver(func(interface{}) (string, bool) { return "r3", true }))
+		ensureError(t, err)
+		_, err = codec.BinaryFromNative(nil, map[string]interface{}{"a": 3})
+		ensureError(t, err, "cannot encode binary union", "union resolver ought to return name of member schema type", "r3")
+	})
+
+	t.Run("nested union", func(t *testing.T) {
+		codec, err := NewCodecWithOptions(`{"type":"record","name":"outer","fields":[{"name":"f1","type":`+schema+`}]}`, WithUnionResolver(resolver))
+		ensureError(t, err)
+		buf, err := codec.BinaryFromNative(nil, map[string]interface{}{"f1": map[string]interface{}{"a": 3, "b": 4}})
+		ensureError(t, err)
+		if actual, expected := buf, []byte{0x04, 0x06, 0x08}; !bytes.Equal(actual, expected) {
+			t.Errorf("GOT: %#v; WANT: %#v", actual, expected)
+		}
+	})
+
+	t.Run("without resolver", func(t *testing.T) {
+		testBinaryEncodeFail(t, schema, map[string]interface{}{"a": 3, "b": 4}, "map ought to have a single key naming a member schema type")
+	})
 }